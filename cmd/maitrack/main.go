@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/asashakira/maitrack/internal/admin"
+	"github.com/asashakira/maitrack/internal/database"
+	"github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/service"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: maitrack admin <command> [flags]
+
+commands:
+  rotate-keys   re-encrypt stored SEGA credentials with ENCRYPTION_ACTIVE_KEY_ID
+  grant-role    set the role of a user: grant-role <userID> <user|curator|admin>
+`
+
+func main() {
+	godotenv.Load(".env")
+
+	if len(os.Args) < 3 || os.Args[1] != "admin" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[2] {
+	case "rotate-keys":
+		rotateKeys(os.Args[3:])
+	case "grant-role":
+		grantRole(os.Args[3:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func rotateKeys(args []string) {
+	fs := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	batchSize := fs.Int("batch-size", 100, "number of users re-encrypted per transaction")
+	dryRun := fs.Bool("dry-run", false, "report how many users would be rotated without writing")
+	fs.Parse(args)
+
+	if *batchSize < 1 {
+		log.Fatal("batch-size must be positive")
+	}
+
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		log.Fatal("DB_URL is not found in the environment")
+	}
+
+	keyring, err := utils.LoadKeyring()
+	if err != nil {
+		log.Fatalf("failed to load keyring: %s", err)
+	}
+
+	pool, err := database.Connect(os.Getenv("PORT"), dbURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Close()
+
+	log.Printf("rotating credentials to key '%s'", keyring.ActiveKeyID())
+	result, err := admin.RotateKeys(context.Background(), pool, keyring, int32(*batchSize), *dryRun)
+	if err != nil {
+		log.Fatalf("rotate-keys failed after %d users: %s", result.Scanned, err)
+	}
+
+	if *dryRun {
+		log.Printf("dry run: %d of %d users would be rotated", result.Rotated, result.Scanned)
+		return
+	}
+	log.Printf("done: rotated %d of %d users", result.Rotated, result.Scanned)
+}
+
+func grantRole(args []string) {
+	if len(args) != 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	userID, role := args[0], args[1]
+	if !service.IsValidRole(role) {
+		log.Fatalf("unknown role '%s'", role)
+	}
+
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		log.Fatal("DB_URL is not found in the environment")
+	}
+
+	pool, err := database.Connect(os.Getenv("PORT"), dbURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Close()
+
+	queries := sqlc.New(pool)
+	ctx := context.Background()
+
+	// plain users have no row
+	if role == service.RoleUser {
+		if err := queries.DeleteUserRoleByUserID(ctx, userID); err != nil {
+			log.Fatalf("failed to revoke role of '%s': %s", userID, err)
+		}
+		log.Printf("'%s' is now a %s", userID, role)
+		return
+	}
+
+	_, err = queries.UpsertUserRoleByUserID(ctx, sqlc.UpsertUserRoleByUserIDParams{
+		Role:   role,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Fatalf("no user found with userID '%s'", userID)
+		}
+		log.Fatalf("failed to grant role to '%s': %s", userID, err)
+	}
+	log.Printf("'%s' is now a %s", userID, role)
+}
@@ -0,0 +1,375 @@
+// Command openapigen checks internal/api/openapi/openapi.json against the
+// registered routes and generates the typed client in pkg/maitrackapi.
+//
+//	go run ./cmd/openapigen -check
+//	go generate ./pkg/maitrackapi
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/asashakira/maitrack/internal/api"
+	"github.com/asashakira/maitrack/internal/api/handler"
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+type schema struct {
+	Ref         string            `json:"$ref"`
+	Type        string            `json:"type"`
+	Format      string            `json:"format"`
+	Description string            `json:"description"`
+	Nullable    bool              `json:"nullable"`
+	Enum        []string          `json:"enum"`
+	Items       *schema           `json:"items"`
+	Properties  orderedProperties `json:"properties"`
+	Required    []string          `json:"required"`
+	AllOf       []*schema         `json:"allOf"`
+}
+
+// properties keep the order they are written in so generated structs
+// read like the spec
+type orderedProperties struct {
+	names  []string
+	values map[string]*schema
+}
+
+func (p *orderedProperties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	p.values = map[string]*schema{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+		var s schema
+		if err := dec.Decode(&s); err != nil {
+			return err
+		}
+		p.names = append(p.names, name)
+		p.values[name] = &s
+	}
+	return nil
+}
+
+type parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type mediaTypes struct {
+	JSON *struct {
+		Schema *schema `json:"schema"`
+	} `json:"application/json"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	RequestBody *struct {
+		Content mediaTypes `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content mediaTypes `json:"content"`
+	} `json:"responses"`
+	Paginated bool `json:"x-paginated"`
+
+	method, path string
+}
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas    map[string]*schema   `json:"schemas"`
+		Parameters map[string]parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+func main() {
+	check := flag.Bool("check", false, "only check that the spec documents every route")
+	out := flag.String("out", "maitrackapi.gen.go", "file to write the generated client to")
+	flag.Parse()
+
+	// handlers are never called, so they need no database
+	router := chi.NewRouter()
+	api.SetUpRoutes(router, handler.New(nil), middleware.New(nil))
+	if err := openapi.CheckRoutes(router); err != nil {
+		log.Fatal(err)
+	}
+	if *check {
+		return
+	}
+
+	var doc document
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		log.Fatalf("parsing openapi.json: %s", err)
+	}
+
+	src, err := generate(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(doc document) ([]byte, error) {
+	g := &generator{doc: doc}
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Error is written by hand in client.go so it can carry the status code
+		if name == "Error" {
+			continue
+		}
+		g.schemaType(name, doc.Components.Schemas[name])
+	}
+
+	var ops []*operation
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			op.method, op.path = strings.ToUpper(method), path
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].OperationID < ops[j].OperationID })
+	for _, op := range ops {
+		g.operation(op)
+	}
+
+	var file bytes.Buffer
+	file.WriteString("// Code generated by cmd/openapigen from internal/api/openapi/openapi.json. DO NOT EDIT.\n\n")
+	file.WriteString("package maitrackapi\n\nimport (\n")
+	for _, pkg := range []string{"context", "fmt", "net/url", "time"} {
+		if bytes.Contains(g.buf.Bytes(), []byte(pkg[strings.LastIndex(pkg, "/")+1:]+".")) {
+			fmt.Fprintf(&file, "%q\n", pkg)
+		}
+	}
+	file.WriteString(")\n\n")
+	file.Write(g.buf.Bytes())
+
+	src, err := format.Source(file.Bytes())
+	if err != nil {
+		return file.Bytes(), fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	doc document
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) comment(text string) {
+	if text != "" {
+		g.printf("// %s\n", text)
+	}
+}
+
+func (g *generator) schemaType(name string, s *schema) {
+	g.comment(s.Description)
+	if len(s.AllOf) == 0 && s.Type != "object" {
+		g.printf("type %s %s\n\n", name, g.goType(s))
+		return
+	}
+	g.printf("type %s struct {\n", name)
+	parts := s.AllOf
+	if len(parts) == 0 {
+		parts = []*schema{s}
+	}
+	for _, part := range parts {
+		if part.Ref != "" {
+			g.printf("%s\n", refName(part.Ref))
+			continue
+		}
+		g.fields(part)
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) fields(s *schema) {
+	for _, name := range s.Properties.names {
+		prop := s.Properties.values[name]
+		required := slices.Contains(s.Required, name)
+		typ := g.goType(prop)
+		tag := name
+		if !required {
+			tag += ",omitempty"
+		}
+		if (!required || prop.Nullable) && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") {
+			typ = "*" + typ
+		}
+		g.comment(describe(prop))
+		g.printf("%s %s `json:\"%s\"`\n", exported(name), typ, tag)
+	}
+}
+
+func describe(s *schema) string {
+	if len(s.Enum) == 0 {
+		return s.Description
+	}
+	return joinNonEmpty(s.Description, "one of "+strings.Join(s.Enum, ", "))
+}
+
+func joinNonEmpty(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), ", ")
+}
+
+func (g *generator) goType(s *schema) string {
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int32"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items)
+	}
+	return "map[string]any"
+}
+
+func (g *generator) operation(op *operation) {
+	name := exported(op.OperationID)
+
+	var pathParams, queryParams []parameter
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			p = g.doc.Components.Parameters[refName(p.Ref)]
+		}
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		}
+	}
+
+	if len(queryParams) > 0 {
+		g.printf("// %sParams are the query parameters of %s, nil fields are not sent\n", name, name)
+		g.printf("type %sParams struct {\n", name)
+		for _, p := range queryParams {
+			g.comment(joinNonEmpty(p.Description, describe(p.Schema)))
+			g.printf("%s *%s\n", exported(p.Name), g.goType(p.Schema))
+		}
+		g.printf("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, p.Name+" string")
+	}
+	if len(queryParams) > 0 {
+		args = append(args, "params *"+name+"Params")
+	}
+	body := "nil"
+	if op.RequestBody != nil && op.RequestBody.Content.JSON != nil {
+		args = append(args, "body "+g.goType(op.RequestBody.Content.JSON.Schema))
+		body = "body"
+	}
+
+	var result *schema
+	for _, code := range []string{"200", "201"} {
+		if res, ok := op.Responses[code]; ok && res.Content.JSON != nil {
+			result = res.Content.JSON.Schema
+		}
+	}
+	var results, zero, out string
+	switch {
+	case result == nil:
+		results, zero, out = "error", "", "nil"
+	case op.Paginated:
+		results, zero, out = fmt.Sprintf("(%s, string, error)", g.goType(result)), "nil, \"\", ", "&out"
+	case result.Ref != "":
+		results, zero, out = fmt.Sprintf("(*%s, error)", g.goType(result)), "nil, ", "&out"
+	default:
+		results, zero, out = fmt.Sprintf("(%s, error)", g.goType(result)), "out, ", "&out"
+	}
+
+	g.printf("// %s: %s\n//\n//\t%s %s\n", name, op.Summary, op.method, op.path)
+	g.printf("func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), results)
+
+	path := fmt.Sprintf("%q", op.path)
+	for _, p := range pathParams {
+		path = strings.Replace(path, "{"+p.Name+"}", `" + url.PathEscape(`+p.Name+`) + "`, 1)
+	}
+	path = strings.TrimSuffix(strings.ReplaceAll(path, ` + ""`, ""), ` + ""`)
+
+	query := "nil"
+	if len(queryParams) > 0 {
+		query = "query"
+		g.printf("query := url.Values{}\nif params != nil {\n")
+		for _, p := range queryParams {
+			field := "params." + exported(p.Name)
+			g.printf("if %s != nil {\nquery.Set(%q, fmt.Sprint(*%s))\n}\n", field, p.Name, field)
+		}
+		g.printf("}\n")
+	}
+
+	if result != nil {
+		g.printf("var out %s\n", g.goType(result))
+	}
+	call := fmt.Sprintf("c.do(ctx, %q, %s, %s, %s, %s)", op.method, path, query, body, out)
+	switch {
+	case result == nil:
+		g.printf("_, err := %s\nreturn err\n", call)
+	case op.Paginated:
+		g.printf("res, err := %s\nif err != nil {\nreturn %serr\n}\nreturn out, nextCursor(res), nil\n", call, zero)
+	default:
+		ret := "out"
+		if result.Ref != "" {
+			ret = "&out"
+		}
+		g.printf("if _, err := %s; err != nil {\nreturn %serr\n}\nreturn %s, nil\n", call, zero, ret)
+	}
+	g.printf("}\n\n")
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func exported(name string) string {
+	if name == "" || name == "id" {
+		return strings.ToUpper(name)
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
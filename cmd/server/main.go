@@ -7,6 +7,7 @@ import (
 	"github.com/asashakira/maitrack/internal/api"
 	"github.com/asashakira/maitrack/internal/cron"
 	"github.com/asashakira/maitrack/internal/database"
+	"github.com/asashakira/maitrack/internal/utils"
 	"github.com/joho/godotenv"
 )
 
@@ -22,6 +23,10 @@ func main() {
 		log.Fatal("DB_URL is not found in the environment")
 	}
 
+	if err := utils.InitKeyring(); err != nil {
+		log.Fatalf("failed to load keyring: %s", err)
+	}
+
 	// connect to database
 	pool, err := database.Connect(port, dbURL)
 	if err != nil {
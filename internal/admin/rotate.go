@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RotateKeysResult struct {
+	Scanned int
+	Rotated int
+}
+
+// RotateKeys re-encrypts every user's SEGA credentials under the active
+// key of the keyring, each with a fresh data key. Users are processed in
+// batches of batchSize, each batch in its own transaction, so an
+// interrupted run can simply be started again.
+func RotateKeys(ctx context.Context, pool *pgxpool.Pool, keyring *utils.Keyring, batchSize int32, dryRun bool) (RotateKeysResult, error) {
+	var result RotateKeysResult
+	queries := database.New(pool)
+
+	lastID := uuid.Nil
+	for {
+		users, err := queries.GetSegaCredentialsBatch(ctx, database.GetSegaCredentialsBatchParams{
+			ID:    lastID,
+			Limit: batchSize,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to get credentials after '%s': %w", lastID, err)
+		}
+		if len(users) == 0 {
+			return result, nil
+		}
+
+		rotated, err := rotateBatch(ctx, pool, keyring, users, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Scanned += len(users)
+		result.Rotated += rotated
+		log.Printf("rotate-keys: scanned %d, rotated %d", result.Scanned, result.Rotated)
+
+		lastID = users[len(users)-1].ID
+	}
+}
+
+func rotateBatch(ctx context.Context, pool *pgxpool.Pool, keyring *utils.Keyring, users []database.GetSegaCredentialsBatchRow, dryRun bool) (int, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	queries := database.New(pool).WithTx(tx)
+
+	rotated := 0
+	for _, u := range users {
+		segaID, segaIDChanged, err := keyring.Rewrap(u.EncryptedSegaID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rewrap SEGA ID of user '%s': %w", u.ID, err)
+		}
+		segaPassword, segaPasswordChanged, err := keyring.Rewrap(u.EncryptedSegaPassword)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rewrap SEGA password of user '%s': %w", u.ID, err)
+		}
+		if !segaIDChanged && !segaPasswordChanged {
+			continue
+		}
+
+		rotated++
+		if dryRun {
+			continue
+		}
+		err = queries.UpdateSegaCredentials(ctx, database.UpdateSegaCredentialsParams{
+			ID:                    u.ID,
+			EncryptedSegaID:       segaID,
+			EncryptedSegaPassword: segaPassword,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to update credentials of user '%s': %w", u.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return rotated, nil
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	mostPlayedBeatmaps = 10
+	// the first maimai came out in 2012
+	firstActivityYear = 2012
+)
+
+// GetActivity returns a user's plays per JST day for a play calendar,
+// their streaks, most played charts and plays per version.
+//
+//	?year=2025
+//
+// Without year the calendar covers the 365 days up to today.
+func (h *Handler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	today := utils.JSTDay(time.Now())
+	from, to := today.AddDate(0, 0, -364), today.AddDate(0, 0, 1)
+	if v := r.URL.Query().Get("year"); v != "" {
+		year, err := strconv.Atoi(v)
+		if err != nil || year < firstActivityYear || year > today.Year() {
+			utils.RespondWithError(w, utils.Invalid("year", "must be a year between %d and %d", firstActivityYear, today.Year()))
+			return
+		}
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, today.Location())
+		to = from.AddDate(1, 0, 0)
+	}
+	playedFrom := pgtype.Timestamp{Time: from.UTC(), Valid: true}
+	playedTo := pgtype.Timestamp{Time: to.UTC(), Valid: true}
+
+	days, err := h.queries.GetDailyPlayCountsByUserID(r.Context(), database.GetDailyPlayCountsByUserIDParams{
+		UserID:     userID,
+		PlayedFrom: playedFrom,
+		PlayedTo:   playedTo,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetDailyPlayCountsByUserID: %w", err), ""))
+		return
+	}
+	streaks, err := h.queries.GetPlayStreaksByUserID(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetPlayStreaksByUserID: %w", err), ""))
+		return
+	}
+	mostPlayed, err := h.queries.GetMostPlayedBeatmapsByUserID(r.Context(), database.GetMostPlayedBeatmapsByUserIDParams{
+		UserID:     userID,
+		PlayedFrom: playedFrom,
+		PlayedTo:   playedTo,
+		MaxResults: mostPlayedBeatmaps,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetMostPlayedBeatmapsByUserID: %w", err), ""))
+		return
+	}
+	versions, err := h.queries.GetPlayCountsByVersionByUserID(r.Context(), database.GetPlayCountsByVersionByUserIDParams{
+		UserID:     userID,
+		PlayedFrom: playedFrom,
+		PlayedTo:   playedTo,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetPlayCountsByVersionByUserID: %w", err), ""))
+		return
+	}
+
+	out := response.Activity{
+		From:       from.Format("2006-01-02"),
+		To:         to.AddDate(0, 0, -1).Format("2006-01-02"),
+		ActiveDays: len(days),
+		MostPlayed: response.NewPlayedBeatmaps(mostPlayed),
+		Versions:   response.NewVersionPlays(versions),
+	}
+
+	// every day of the calendar, including the ones without plays
+	plays := make(map[string]int64, len(days))
+	for _, d := range days {
+		plays[d.Day.Time.Format("2006-01-02")] = d.Plays
+		out.TotalPlays += d.Plays
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		out.Days = append(out.Days, response.ActivityDay{Date: date, Plays: plays[date]})
+	}
+
+	// a streak is still going if the user played today or yesterday
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	for i, s := range streaks {
+		if i == 0 && s.LastDay.Time.Format("2006-01-02") >= yesterday {
+			out.CurrentStreak = response.NewStreak(s)
+		}
+		if out.LongestStreak == nil || s.Days > out.LongestStreak.Days {
+			out.LongestStreak = response.NewStreak(s)
+		}
+	}
+	utils.RespondWithJSON(w, 200, out)
+}
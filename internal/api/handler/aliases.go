@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// aliases of a song, for anyone
+func (h *Handler) GetSongAliases(w http.ResponseWriter, r *http.Request) {
+	songID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("id", "must be a UUID"))
+		return
+	}
+
+	aliases, err := h.queries.GetSongAliasesBySongID(r.Context(), songID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetSongAliasesBySongID: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewSongAliases(aliases))
+}
+
+// adds an alias to a song, curators only
+func (h *Handler) CreateSongAlias(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	songID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("id", "must be a UUID"))
+		return
+	}
+
+	alias, ok := decodeAlias(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.queries.GetSongByID(r.Context(), songID); err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(err, fmt.Sprintf("No song found with provided id '%s'", songID)))
+		return
+	}
+
+	user, err := h.queries.GetUserByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserByUserID: %w", err)))
+		return
+	}
+
+	created, err := h.queries.CreateSongAlias(r.Context(), database.CreateSongAliasParams{
+		ID:        uuid.New(),
+		SongID:    songID,
+		Alias:     alias,
+		CreatedBy: pgtype.UUID{Bytes: user.ID, Valid: true},
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			utils.RespondWithError(w, aliasExists(alias))
+			return
+		}
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateSongAlias: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 201, response.NewSongAlias(created))
+}
+
+// renames an alias, curators only
+func (h *Handler) UpdateSongAlias(w http.ResponseWriter, r *http.Request) {
+	aliasID, err := uuid.Parse(chi.URLParam(r, "aliasID"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("aliasID", "must be a UUID"))
+		return
+	}
+
+	alias, ok := decodeAlias(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.queries.UpdateSongAlias(r.Context(), database.UpdateSongAliasParams{
+		ID:    aliasID,
+		Alias: alias,
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			utils.RespondWithError(w, aliasExists(alias))
+			return
+		}
+		utils.RespondWithError(w, utils.DatabaseError(err, fmt.Sprintf("No alias found with provided id '%s'", aliasID)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewSongAlias(updated))
+}
+
+// removes an alias, curators only
+func (h *Handler) DeleteSongAlias(w http.ResponseWriter, r *http.Request) {
+	aliasID, err := uuid.Parse(chi.URLParam(r, "aliasID"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("aliasID", "must be a UUID"))
+		return
+	}
+
+	deleted, err := h.queries.DeleteSongAlias(r.Context(), aliasID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("DeleteSongAlias: %w", err)))
+		return
+	}
+	if deleted == 0 {
+		utils.RespondWithError(w, utils.NotFound("No alias found with provided id '%s'", aliasID))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// body of CreateSongAlias and UpdateSongAlias
+type aliasParams struct {
+	Alias string `json:"alias" validate:"required,maxlen=100"`
+}
+
+func (p *aliasParams) normalize() {
+	p.Alias = strings.TrimSpace(p.Alias)
+}
+
+func decodeAlias(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var params aliasParams
+	if !decodeJSONStrict(w, r, &params) {
+		return "", false
+	}
+	return params.Alias, true
+}
+
+func aliasExists(alias string) *utils.APIError {
+	return utils.Conflict(utils.CodeConflict, "'%s' is already an alias of this song", alias)
+}
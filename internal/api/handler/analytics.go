@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultWeakSpots = 10
+	maxWeakSpots     = 50
+	// a single bad play does not make a weak spot
+	minWeakSpotPlays = 2
+)
+
+// GetAnalytics sums up the judgements and fast/late of a user's plays.
+//
+//	?difficulty=&type=&levelMin=&levelMax=&playedFrom=&playedTo=&limit=
+//
+// playedFrom and playedTo are JST dates, both inclusive. limit is the
+// number of weak spots.
+func (h *Handler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	q := newListQuery(r, listOptions{})
+	levelMin, levelMax := q.numericRange("level", 0, 15)
+	playedFrom, playedTo := q.dateRange("played")
+	params := database.GetJudgementTotalsByUserIDParams{
+		UserID:     userID,
+		Difficulty: q.text("difficulty", difficulties...),
+		Type:       q.text("type", beatmapTypes...),
+		LevelMin:   levelMin,
+		LevelMax:   levelMax,
+		PlayedFrom: playedFrom,
+		PlayedTo:   playedTo,
+	}
+	limit := defaultWeakSpots
+	if v := q.values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxWeakSpots {
+			q.errorf("limit", "must be an integer between 1 and %d", maxWeakSpots)
+		}
+		limit = n
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	totals, err := h.queries.GetJudgementTotalsByUserID(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetJudgementTotalsByUserID: %w", err), ""))
+		return
+	}
+	weeks, err := h.queries.GetFastLateTrendByUserID(r.Context(), database.GetFastLateTrendByUserIDParams(params))
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetFastLateTrendByUserID: %w", err), ""))
+		return
+	}
+	spots, err := h.queries.GetWeakSpotsByUserID(r.Context(), database.GetWeakSpotsByUserIDParams{
+		UserID:     params.UserID,
+		Difficulty: params.Difficulty,
+		Type:       params.Type,
+		LevelMin:   params.LevelMin,
+		LevelMax:   params.LevelMax,
+		PlayedFrom: params.PlayedFrom,
+		PlayedTo:   params.PlayedTo,
+		MinPlays:   minWeakSpotPlays,
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetWeakSpotsByUserID: %w", err), ""))
+		return
+	}
+
+	out := response.Analytics{
+		Plays:         totals.Plays,
+		Judgements:    response.NewJudgementRates(totals),
+		FastLate:      response.NewFastLate(totals.Fast, totals.Late),
+		FastLateTrend: make([]response.FastLateWeek, 0, len(weeks)),
+		WeakSpots:     make([]response.WeakSpot, 0, len(spots)),
+	}
+	out.BreakCriticalRate = out.Judgements.Break.CriticalRate
+	for _, wk := range weeks {
+		out.FastLateTrend = append(out.FastLateTrend, response.NewFastLateWeek(wk))
+	}
+	out.FastLateSlope = biasSlope(weeks)
+	for _, s := range spots {
+		out.WeakSpots = append(out.WeakSpots, response.NewWeakSpot(s))
+	}
+	utils.RespondWithJSON(w, 200, out)
+}
+
+// biasSlope fits a line through the weekly fast/late bias and returns how
+// much it moves per week, positive when getting later. Weeks without fast
+// or late are skipped, null when fewer than two weeks are left.
+func biasSlope(weeks []database.GetFastLateTrendByUserIDRow) *float64 {
+	var xs, ys []float64
+	for _, wk := range weeks {
+		if !wk.Week.Valid || wk.Fast+wk.Late == 0 {
+			continue
+		}
+		xs = append(xs, wk.Week.Time.Sub(weeks[0].Week.Time).Hours()/(7*24))
+		ys = append(ys, float64(wk.Late-wk.Fast)/float64(wk.Fast+wk.Late))
+	}
+	if len(xs) < 2 {
+		return nil
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+	var cov, variance float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	slope := cov / variance
+	return &slope
+}
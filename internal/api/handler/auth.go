@@ -1,14 +1,16 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/asashakira/maitrack/internal/api/middleware"
@@ -22,24 +24,24 @@ import (
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		UserID       string `json:"userID"`
-		DisplayName  string `json:"displayName"`
-		Password     string `json:"password"`
-		SegaID       string `json:"segaID"`
-		SegaPassword string `json:"segaPassword"`
+		UserID       string `json:"userID" validate:"required,userid"`
+		DisplayName  string `json:"displayName" validate:"maxlen=50"`
+		Password     string `json:"password" validate:"required,password"`
+		SegaID       string `json:"segaID" validate:"required"`
+		SegaPassword string `json:"segaPassword" validate:"required"`
 	}
 
-	// Parse request
 	var params parameters
-	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
-		utils.RespondWithError(w, 400, "Invalid JSON payload")
+	if !decodeJSON(w, r, &params) {
 		return
 	}
 
-	// Input validation
-	// TODO: do better
-	if params.UserID == "" || params.Password == "" || params.SegaID == "" || params.SegaPassword == "" {
-		utils.RespondWithError(w, 400, "All fields are required")
+	// Don't bother maimaidx.net for IDs that are taken
+	if _, err := h.queries.GetUserByUserID(r.Context(), params.UserID); err == nil {
+		utils.RespondWithError(w, userExists(params.UserID))
+		return
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserByUserID: %w", err)))
 		return
 	}
 
@@ -48,29 +50,26 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	err := m.Login(params.SegaID, params.SegaPassword)
 	if err != nil {
 		log.Printf("Invalid SEGA Credentials '%s': %s\n", params.SegaID, err)
-		utils.RespondWithError(w, 400, "Invalid SEGA Credentials")
+		utils.RespondWithError(w, segaLoginError(err))
 		return
 	}
 
 	// Hash Password
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Printf("Error hashing password: %s", err)
-		utils.RespondWithError(w, 500, "Internal Server Error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("hashing password: %w", err)))
 		return
 	}
 
 	// Encrypt SEGA Creds
 	encryptedSegaID, err := utils.Encrypt(params.SegaID)
 	if err != nil {
-		log.Printf("Error encrypting SEGA ID: %s", err)
-		utils.RespondWithError(w, 500, "Internal Server Error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("encrypting SEGA ID: %w", err)))
 		return
 	}
 	encryptedSegaPassword, err := utils.Encrypt(params.SegaPassword)
 	if err != nil {
-		log.Printf("Error encrypting SEGA password: %s", err)
-		utils.RespondWithError(w, 500, "Internal Server Error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("encrypting SEGA password: %w", err)))
 		return
 	}
 
@@ -87,9 +86,11 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		LastScrapedAt:         pgtype.Timestamp{Time: defaultTime, Valid: true},
 	})
 	if err != nil {
-		errorMessage := fmt.Sprintf("Error creating user: %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		if utils.IsUniqueViolation(err) {
+			utils.RespondWithError(w, userExists(params.UserID))
+			return
+		}
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateUser: %w", err)))
 		return
 	}
 
@@ -102,9 +103,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		TotalPlayCount:  0,
 	})
 	if createUserDataErr != nil {
-		errorMessage := fmt.Sprintf("failed to create user data for user '%s': %s", user.UserID, createUserDataErr)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to create user data for user '%s': %w", user.UserID, createUserDataErr)))
 		return
 	}
 
@@ -113,9 +112,14 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		UserUuid: user.ID,
 	})
 	if err != nil {
-		errorMessage := fmt.Sprintf("Error Creating UserMetadata: %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateUserMetadata: %w", err)))
+		return
+	}
+
+	// create default privacy settings
+	_, err = h.queries.CreateUserPrivacy(r.Context(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateUserPrivacy: %w", err)))
 		return
 	}
 
@@ -134,15 +138,13 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	secretKey, err := service.GetSecretKey()
 	if err != nil {
-		log.Println("Failed to get secret key:", err)
-		utils.RespondWithError(w, 500, "Internal server error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to get secret key: %w", err)))
 		return
 	}
 
 	tokenString, err := token.SignedString(secretKey)
 	if err != nil {
-		log.Println("Failed to generate token:", err)
-		utils.RespondWithError(w, 500, "Internal server error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to generate token: %w", err)))
 		return
 	}
 
@@ -169,15 +171,25 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		UserID   string `json:"userID"`
-		Password string `json:"password"`
+		UserID   string `json:"userID" validate:"required"`
+		Password string `json:"password" validate:"required"`
 	}
 
-	// Decode request body
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	if err := decoder.Decode(&params); err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("Error parsing JSON: %s", err))
+	var params parameters
+	if !decodeJSON(w, r, &params) {
+		return
+	}
+
+	// Reject locked accounts before touching the password.
+	// Don't lock everyone out when the store is down.
+	ip := middleware.ClientIP(r)
+	wait, err := h.loginLockout.LockedFor(r.Context(), params.UserID, ip)
+	if err != nil {
+		log.Printf("login lockout: %s", err)
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		utils.RespondWithError(w, utils.NewAPIError(429, utils.CodeRateLimited, "Too many failed login attempts, please try again later"))
 		return
 	}
 
@@ -185,16 +197,21 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	user, err := h.queries.GetPasswordHashByUserID(r.Context(), params.UserID)
 	if err != nil {
 		log.Println("User not found:", err)
-		utils.RespondWithError(w, 400, "Invalid login credentials")
+		h.failLogin(r, params.UserID, ip)
+		utils.RespondWithError(w, invalidLogin())
 		return
 	}
 
 	// Compare hashed password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(params.Password)); err != nil {
 		log.Println("Invalid password:", err)
-		utils.RespondWithError(w, 401, "Invalid login credentials")
+		h.failLogin(r, params.UserID, ip)
+		utils.RespondWithError(w, invalidLogin())
 		return
 	}
+	if err := h.loginLockout.Reset(r.Context(), params.UserID, ip); err != nil {
+		log.Printf("login lockout: %s", err)
+	}
 
 	// Define JWT claims
 	claims := service.Claims{
@@ -211,15 +228,13 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	secretKey, err := service.GetSecretKey()
 	if err != nil {
-		log.Println("Failed to get secret key:", err)
-		utils.RespondWithError(w, 500, "Internal server error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to get secret key: %w", err)))
 		return
 	}
 
 	tokenString, err := token.SignedString(secretKey)
 	if err != nil {
-		log.Println("Failed to generate token:", err)
-		utils.RespondWithError(w, 500, "Internal server error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to generate token: %w", err)))
 		return
 	}
 
@@ -274,3 +289,34 @@ func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 
 	utils.RespondWithJSON(w, 200, data)
 }
+
+func userExists(userID string) *utils.APIError {
+	return utils.Conflict(utils.CodeUserExists, "userID '%s' is already taken", userID)
+}
+
+// unknown user IDs and wrong passwords look the same to the client
+func invalidLogin() *utils.APIError {
+	return utils.NewAPIError(401, utils.CodeInvalidCredentials, "Invalid login credentials")
+}
+
+// maps a failed maimai DX NET login to the error shown to the user
+func segaLoginError(err error) *utils.APIError {
+	switch {
+	case errors.Is(err, maimaiclient.ErrInvalidCredentials):
+		return utils.NewAPIError(400, utils.CodeInvalidSegaCredentials, "Invalid SEGA ID or password")
+	case errors.Is(err, maimaiclient.ErrMaintenance):
+		return utils.UpstreamMaintenance()
+	}
+	return utils.Upstream(err)
+}
+
+func (h *Handler) failLogin(r *http.Request, userID, ip string) {
+	lock, err := h.loginLockout.Fail(r.Context(), userID, ip)
+	if err != nil {
+		log.Printf("login lockout: %s", err)
+		return
+	}
+	if lock > 0 {
+		log.Printf("Locked out '%s' on %s for %s", userID, ip, lock)
+	}
+}
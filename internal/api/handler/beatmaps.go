@@ -1,42 +1,38 @@
 package handler
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"slices"
 
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
 	database "github.com/asashakira/maitrack/internal/database/sqlc"
 	"github.com/asashakira/maitrack/internal/utils"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (h *Handler) CreateBeatmap(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		SongID        uuid.UUID      `json:"songID"`
-		Difficulty    string         `json:"difficulty"`
-		Level         string         `json:"level"`
+		SongID        uuid.UUID      `json:"songID" validate:"required"`
+		Difficulty    string         `json:"difficulty" validate:"required,oneof=basic advanced expert master remaster utage"`
+		Level         string         `json:"level" validate:"required"`
 		InternalLevel pgtype.Numeric `json:"internalLevel"`
-		Type          string         `json:"type"`
-		TotalNotes    int32          `json:"totalNotes"`
-		Tap           int32          `json:"tap"`
-		Hold          int32          `json:"hold"`
-		Slide         int32          `json:"slide"`
-		Touch         int32          `json:"touch"`
-		Break         int32          `json:"break"`
+		Type          string         `json:"type" validate:"required,oneof=dx std utage"`
+		TotalNotes    int32          `json:"totalNotes" validate:"min=0"`
+		Tap           int32          `json:"tap" validate:"min=0"`
+		Hold          int32          `json:"hold" validate:"min=0"`
+		Slide         int32          `json:"slide" validate:"min=0"`
+		Touch         int32          `json:"touch" validate:"min=0"`
+		Break         int32          `json:"break" validate:"min=0"`
 		NoteDesigner  string         `json:"noteDesigner"`
-		MaxDxScore    int32          `json:"maxDxScore"`
-		PlayCount     int32          `json:"playCount"`
+		MaxDxScore    int32          `json:"maxDxScore" validate:"min=0"`
+		PlayCount     int32          `json:"playCount" validate:"min=0"`
 	}
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err := decoder.Decode(&params)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("Error parsing JSON: %s", err))
+	var params parameters
+	if !decodeJSON(w, r, &params) {
 		return
 	}
 
@@ -57,78 +53,104 @@ func (h *Handler) CreateBeatmap(w http.ResponseWriter, r *http.Request) {
 		MaxDxScore:    params.MaxDxScore,
 	})
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("CreateBeatmap %s", err))
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("CreateBeatmap: %w", err), ""))
 		return
 	}
-	utils.RespondWithJSON(w, 200, beatmap)
+	utils.RespondWithJSON(w, 200, response.NewBeatmap(beatmap))
 }
 
+// GetAllBeatmaps lists beatmaps hardest first.
+//
+//	?version=&genre=&difficulty=&type=&level=&levelMin=&levelMax=
+//	&sort=[-]level|title|releaseDate&limit=&cursor=
 func (h *Handler) GetAllBeatmaps(w http.ResponseWriter, r *http.Request) {
-	beatmaps, err := h.queries.GetAllBeatmaps(r.Context())
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"level": true, "title": false, "releaseDate": true},
+		defaultSort:  "level",
+		defaultLimit: 100,
+	})
+	levelMin, levelMax := q.numericRange("level", 0, 15)
+	params := database.ListBeatmapsParams{
+		Sort:         q.sort,
+		Version:      q.text("version"),
+		Genre:        q.text("genre"),
+		Difficulty:   q.text("difficulty", difficulties...),
+		Type:         q.text("type", beatmapTypes...),
+		Level:        q.text("level"),
+		LevelMin:     levelMin,
+		LevelMax:     levelMax,
+		CursorNumber: q.cursorNumber("level"),
+		CursorValue:  q.cursorValue(),
+		Descending:   q.descending,
+		CursorID:     q.cursorID(),
+		PageSize:     q.pageSize(),
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	beatmaps, err := h.queries.ListBeatmaps(r.Context(), params)
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("GetAllBeatmaps %s", err))
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("ListBeatmaps: %w", err)))
 		return
 	}
-	utils.RespondWithJSON(w, 200, beatmaps)
+
+	beatmaps, next := page(q, beatmaps, func(b database.ListBeatmapsRow) (string, uuid.UUID) {
+		if b.SortNumber.Valid {
+			return numberKey(b.SortNumber), b.ID
+		}
+		return b.SortValue.String, b.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, response.NewBeatmapsWithSong(beatmaps))
 }
 
 func (h *Handler) GetBeatmapsBySongID(w http.ResponseWriter, r *http.Request) {
 	songID, err := uuid.Parse(chi.URLParam(r, "songID"))
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing songID: %s", err))
+		utils.RespondWithError(w, utils.Invalid("songID", "must be a UUID"))
 		return
 	}
 
 	beatmaps, err := h.queries.GetBeatmapsBySongID(r.Context(), songID)
 	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No beatmaps found with provided songID: %s", err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
-			return
-		}
-		// Handle other errors
-		errorMessage := fmt.Sprintf("GetBeatmapsBySongID %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetBeatmapsBySongID: %w", err)))
 		return
 	}
-	utils.RespondWithJSON(w, 200, beatmaps)
+	out := make([]response.Beatmap, 0, len(beatmaps))
+	for _, b := range beatmaps {
+		out = append(out, response.NewBeatmapWithSong(database.GetBeatmapByBeatmapIDRow(b)))
+	}
+	utils.RespondWithJSON(w, 200, out)
 }
 
 func (h *Handler) UpdateBeatmap(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		BeatmapID     uuid.UUID       `json:"beatmapID"`
+		BeatmapID     uuid.UUID       `json:"beatmapID" validate:"required"`
 		SongID        *uuid.UUID      `json:"songID,omitempty"`
-		Difficulty    *string         `json:"difficulty,omitempty"`
+		Difficulty    *string         `json:"difficulty,omitempty" validate:"oneof=basic advanced expert master remaster utage"`
 		Level         *string         `json:"level,omitempty"`
 		InternalLevel *pgtype.Numeric `json:"internalLevel,omitempty"`
-		Type          *string         `json:"type,omitempty"`
-		TotalNotes    *int32          `json:"totalNotes,omitempty"`
-		Tap           *int32          `json:"tap,omitempty"`
-		Hold          *int32          `json:"hold,omitempty"`
-		Slide         *int32          `json:"slide,omitempty"`
-		Touch         *int32          `json:"touch,omitempty"`
-		Break         *int32          `json:"break,omitempty"`
+		Type          *string         `json:"type,omitempty" validate:"oneof=dx std utage"`
+		TotalNotes    *int32          `json:"totalNotes,omitempty" validate:"min=0"`
+		Tap           *int32          `json:"tap,omitempty" validate:"min=0"`
+		Hold          *int32          `json:"hold,omitempty" validate:"min=0"`
+		Slide         *int32          `json:"slide,omitempty" validate:"min=0"`
+		Touch         *int32          `json:"touch,omitempty" validate:"min=0"`
+		Break         *int32          `json:"break,omitempty" validate:"min=0"`
 		NoteDesigner  *string         `json:"noteDesigner,omitempty"`
-		MaxDxScore    *int32          `json:"maxDxScore,omitempty"`
-		PlayCount     *int32          `json:"playCount,omitempty"`
+		MaxDxScore    *int32          `json:"maxDxScore,omitempty" validate:"min=0"`
+		PlayCount     *int32          `json:"playCount,omitempty" validate:"min=0"`
 	}
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err := decoder.Decode(&params)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("Error parsing JSON: %v", err))
+	var params parameters
+	if !decodeJSON(w, r, &params) {
 		return
 	}
 
 	// Fetch existing beatmap
 	beatmap, err := h.queries.GetBeatmapByBeatmapID(r.Context(), params.BeatmapID)
 	if err != nil {
-		errorMessage := fmt.Sprintf("beatmap not found %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(err, fmt.Sprintf("No beatmap found with provided beatmapID '%s'", params.BeatmapID)))
 		return
 	}
 
@@ -149,8 +171,123 @@ func (h *Handler) UpdateBeatmap(w http.ResponseWriter, r *http.Request) {
 		MaxDxScore:    ifNotNil(params.MaxDxScore, beatmap.MaxDxScore),
 	})
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("UpdateBeatmap %s", err))
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("UpdateBeatmap: %w", err), ""))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewBeatmap(updatedBeatmap))
+}
+
+// judgements of one note type in a simulated play, the notes not
+// counted here are CRITICAL PERFECT
+type simulatedJudgement struct {
+	Perfect int32 `json:"perfect" validate:"min=0"`
+	Great   int32 `json:"great" validate:"min=0"`
+	Good    int32 `json:"good" validate:"min=0"`
+	Miss    int32 `json:"miss" validate:"min=0"`
+}
+
+func (j simulatedJudgement) count() int32 {
+	return j.Perfect + j.Great + j.Good + j.Miss
+}
+
+// ranks budgeted when no target is given
+const minBudgetRank = "S"
+
+// SimulateBeatmap computes the achievement, rank and DX score of a play
+// from its judgements, and how many more GREATs, GOODs and MISSes it can
+// afford while keeping a target rank.
+//
+//	{"tap": {"great": 3}, "break": {"perfect": 2}, "target": "SSS+"}
+//
+// Without a target the budget is given for every rank from S up that the
+// play still reaches.
+func (h *Handler) SimulateBeatmap(w http.ResponseWriter, r *http.Request) {
+	beatmapID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("id", "must be a UUID"))
+		return
+	}
+
+	type parameters struct {
+		Tap    simulatedJudgement `json:"tap" validate:"dive"`
+		Hold   simulatedJudgement `json:"hold" validate:"dive"`
+		Slide  simulatedJudgement `json:"slide" validate:"dive"`
+		Touch  simulatedJudgement `json:"touch" validate:"dive"`
+		Break  simulatedJudgement `json:"break" validate:"dive"`
+		Target string             `json:"target" validate:"oneof=SSS+ SSS SS+ SS S+ S AAA AA A BBB BB B C D"`
+	}
+	var params parameters
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+
+	beatmap, err := h.queries.GetBeatmapByBeatmapID(r.Context(), beatmapID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetBeatmapByBeatmapID: %w", err), fmt.Sprintf("No beatmap found with provided id '%s'", beatmapID)))
+		return
+	}
+	notes := calculator.Notes{Tap: beatmap.Tap, Hold: beatmap.Hold, Slide: beatmap.Slide, Touch: beatmap.Touch, Break: beatmap.Break}
+	if notes.Total() == 0 {
+		utils.RespondWithError(w, utils.Conflict(utils.CodeConflict, "The note counts of this beatmap are not known yet"))
 		return
 	}
-	utils.RespondWithJSON(w, 200, updatedBeatmap)
+
+	var judgements calculator.Judgements
+	var invalid []utils.FieldError
+	for _, t := range []struct {
+		field string
+		j     simulatedJudgement
+		notes int32
+		out   *calculator.Judgement
+	}{
+		{"tap", params.Tap, notes.Tap, &judgements.Tap},
+		{"hold", params.Hold, notes.Hold, &judgements.Hold},
+		{"slide", params.Slide, notes.Slide, &judgements.Slide},
+		{"touch", params.Touch, notes.Touch, &judgements.Touch},
+		{"break", params.Break, notes.Break, &judgements.Break},
+	} {
+		if t.j.count() > t.notes {
+			invalid = append(invalid, utils.FieldError{Field: t.field, Message: fmt.Sprintf("counts %d notes but the beatmap has %d", t.j.count(), t.notes)})
+			continue
+		}
+		*t.out = calculator.Judgement{
+			Critical: t.notes - t.j.count(),
+			Perfect:  t.j.Perfect,
+			Great:    t.j.Great,
+			Good:     t.j.Good,
+			Miss:     t.j.Miss,
+		}
+	}
+	if len(invalid) > 0 {
+		utils.RespondWithError(w, utils.ValidationFailed(invalid...))
+		return
+	}
+
+	low, high := judgements.Achievement()
+	low, high = calculator.Truncate(low), calculator.Truncate(high)
+	simulation := response.Simulation{
+		BeatmapID:      beatmapID,
+		AchievementMin: low,
+		AchievementMax: high,
+		Rank:           calculator.RankOf(low).Name,
+		DxScore:        judgements.DxScore(),
+		MaxDxScore:     notes.MaxDxScore(),
+		Budgets:        []response.RankBudget{},
+	}
+
+	targets := calculator.Ranks
+	if params.Target != "" {
+		rank, _ := calculator.ParseRank(params.Target)
+		targets = []calculator.Rank{rank}
+	} else {
+		lowest, _ := calculator.ParseRank(minBudgetRank)
+		targets = slices.DeleteFunc(slices.Clone(targets), func(r calculator.Rank) bool { return r.Achievement < lowest.Achievement })
+	}
+	for _, rank := range targets {
+		if budget, ok := judgements.Budget(notes, rank.Achievement); ok {
+			simulation.Budgets = append(simulation.Budgets, response.NewRankBudget(rank, budget))
+		}
+	}
+
+	utils.RespondWithJSON(w, 200, simulation)
 }
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+var dxStarCounts = []string{"1", "2", "3", "4", "5"}
+
+// how many of the user's played charts earned each number of DX stars,
+// overall and per difficulty, utage left out. With below, the played
+// charts under that many stars are listed hardest first.
+//
+//	?difficulty=&type=&level=&levelMin=&levelMax=&below=3
+func (h *Handler) GetDxStars(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	q := newListQuery(r, listOptions{})
+	levelMin, levelMax := q.numericRange("level", 0, 15)
+	params := database.GetLevelTableByUserIDParams{
+		UserID:     userID,
+		Level:      q.text("level", levels...),
+		LevelMin:   levelMin,
+		LevelMax:   levelMax,
+		Difficulty: q.text("difficulty", "basic", "advanced", "expert", "master", "remaster"),
+		Type:       q.text("type", beatmapTypes...),
+	}
+	below := q.dxStarsBelow("below")
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	charts, err := h.queries.GetLevelTableByUserID(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetLevelTableByUserID: %w", err)))
+		return
+	}
+
+	out := response.DxStarStats{
+		Charts:       len(charts),
+		Difficulties: []response.DxStarDifficulty{},
+		BelowCharts:  []response.LevelTableChart{},
+	}
+	total := map[int]int{}
+	byDifficulty := map[string]map[int]int{}
+	played := map[string]int{}
+	for _, row := range charts {
+		if !row.DxScore.Valid {
+			continue
+		}
+		out.Played++
+		played[row.Difficulty]++
+		stars, ok := calculator.DxStars(row.DxScore.Int32, row.MaxDxScore)
+		if !ok {
+			out.Unknown++
+			continue
+		}
+		total[stars]++
+		if byDifficulty[row.Difficulty] == nil {
+			byDifficulty[row.Difficulty] = map[int]int{}
+		}
+		byDifficulty[row.Difficulty][stars]++
+	}
+	out.DxStars = dxStarDistribution(total)
+	for _, d := range []string{"basic", "advanced", "expert", "master", "remaster"} {
+		if played[d] == 0 {
+			continue
+		}
+		out.Difficulties = append(out.Difficulties, response.DxStarDifficulty{
+			Difficulty: d,
+			Played:     played[d],
+			DxStars:    dxStarDistribution(byDifficulty[d]),
+		})
+	}
+	if below > 0 {
+		out.Below = &below
+		for _, row := range belowDxStars(charts, below) {
+			out.BelowCharts = append(out.BelowCharts, response.NewLevelTableChart(row))
+		}
+	}
+
+	utils.RespondWithJSON(w, 200, out)
+}
+
+// optional number of DX stars from 1 to 5, 0 when not given
+func (q *listQuery) dxStarsBelow(name string) int {
+	v := q.text(name, dxStarCounts...)
+	if !v.Valid {
+		return 0
+	}
+	n, _ := strconv.Atoi(v.String)
+	return n
+}
+
+// played charts with fewer than n DX stars. Charts whose max DX score is
+// not known are left out.
+func belowDxStars(rows []database.GetLevelTableByUserIDRow, n int) []database.GetLevelTableByUserIDRow {
+	out := []database.GetLevelTableByUserIDRow{}
+	for _, row := range rows {
+		if !row.DxScore.Valid {
+			continue
+		}
+		if stars, ok := calculator.DxStars(row.DxScore.Int32, row.MaxDxScore); ok && stars < n {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// counts from the most stars down to none
+func dxStarDistribution(stars map[int]int) []response.LevelTableCount {
+	out := make([]response.LevelTableCount, 0, calculator.MaxDxStars+1)
+	for n := calculator.MaxDxStars; n >= 0; n-- {
+		out = append(out, response.LevelTableCount{Name: strconv.Itoa(n), Count: stars[n]})
+	}
+	return out
+}
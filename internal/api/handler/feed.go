@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/google/uuid"
+)
+
+type FeedResponse struct {
+	Items      []response.FeedItem `json:"items"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+	HasMore    bool                `json:"hasMore"`
+}
+
+// notable events of the users the signed in user follows, newest first by
+// default
+//
+//	?kind=&sort=[-]createdAt&limit=&cursor=
+func (h *Handler) GetMyFeed(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"createdAt": true},
+		defaultSort:  "createdAt",
+		defaultLimit: 20,
+	})
+	params := database.GetFeedByUserIDParams{
+		UserID:     claims.UserID,
+		Kind:       q.text("kind", "personal_best", "rank", "lamp", "rating_milestone"),
+		CursorTime: q.cursorTime("createdAt"),
+		Descending: q.descending,
+		CursorID:   q.cursorID(),
+		PageSize:   q.pageSize(),
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	items, err := h.queries.GetFeedByUserID(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetFeedByUserID: %w", err), ""))
+		return
+	}
+
+	items, next := page(q, items, func(f database.GetFeedByUserIDRow) (string, uuid.UUID) {
+		return timeKey(f.CreatedAt), f.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, FeedResponse{
+		Items:      response.NewFeedItems(items),
+		NextCursor: next,
+		HasMore:    next != "",
+	})
+}
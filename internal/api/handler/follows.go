@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// users a user follows, subject to their profile privacy
+func (h *Handler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeProfile); !ok {
+		return
+	}
+
+	following, err := h.queries.GetFollowingByUserID(r.Context(), database.GetFollowingByUserIDParams{
+		UserID:   userID,
+		ViewerID: viewerID(r),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetFollowingByUserID: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewFollowing(following))
+}
+
+// users following a user, subject to their profile privacy
+func (h *Handler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeProfile); !ok {
+		return
+	}
+
+	followers, err := h.queries.GetFollowersByUserID(r.Context(), database.GetFollowersByUserIDParams{
+		UserID:   userID,
+		ViewerID: viewerID(r),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetFollowersByUserID: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewFollowers(followers))
+}
+
+// the signed in user follows a user, following back makes them friends
+func (h *Handler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	var params struct {
+		UserID string `json:"userID" validate:"required,userid"`
+	}
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+	if params.UserID == claims.UserID {
+		utils.RespondWithError(w, utils.Invalid("userID", "must not be yourself"))
+		return
+	}
+
+	// users with a private profile cannot be followed. A follow reveals
+	// nothing until it is mutual, so friends-only profiles can be followed.
+	followee, err := h.queries.GetUserPrivacyByUserID(r.Context(), params.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserPrivacyByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", params.UserID)))
+		return
+	}
+	if followee.ProfileVisibility == visibilityPrivate {
+		utils.RespondWithError(w, utils.NewAPIError(403, utils.CodeForbidden, "'%s' has made this data private", params.UserID))
+		return
+	}
+	user, err := h.queries.GetUserByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserByUserID: %w", err)))
+		return
+	}
+
+	if _, err := h.queries.CreateFollow(r.Context(), database.CreateFollowParams{
+		FollowerUuid: user.ID,
+		FolloweeUuid: followee.UserUuid,
+	}); err != nil {
+		if utils.IsUniqueViolation(err) {
+			utils.RespondWithError(w, utils.Conflict(utils.CodeConflict, "You already follow '%s'", params.UserID))
+			return
+		}
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateFollow: %w", err)))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (h *Handler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+	followeeID := chi.URLParam(r, "userID")
+
+	deleted, err := h.queries.DeleteFollow(r.Context(), database.DeleteFollowParams{
+		FollowerID: claims.UserID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("DeleteFollow: %w", err)))
+		return
+	}
+	if deleted == 0 {
+		utils.RespondWithError(w, utils.NotFound("You do not follow '%s'", followeeID))
+		return
+	}
+	w.WriteHeader(204)
+}
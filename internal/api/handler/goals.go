@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/service"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxGoals = 100
+
+// the definition of a goal. Which fields apply depends on kind, see
+// checkGoal. On update, fields left out keep their value and an empty
+// string clears a text field.
+type goalParameters struct {
+	Kind         *string  `json:"kind,omitempty" validate:"nonempty,oneof=rating chart chart_set count"`
+	Title        *string  `json:"title,omitempty" validate:"maxlen=100"`
+	TargetRating *int32   `json:"targetRating,omitempty" validate:"min=1,max=20000"`
+	BeatmapID    *string  `json:"beatmapID,omitempty" validate:"uuid"`
+	TargetRank   *string  `json:"targetRank,omitempty" validate:"oneof=SSS+ SSS SS+ SS S+ S AAA AA A BBB BB B C"`
+	TargetLamp   *string  `json:"targetLamp,omitempty" validate:"oneof=FC FC+ AP AP+"`
+	Difficulty   *string  `json:"difficulty,omitempty" validate:"oneof=basic advanced expert master remaster utage"`
+	Type         *string  `json:"type,omitempty" validate:"oneof=dx std"`
+	Level        *string  `json:"level,omitempty" validate:"oneof=1 2 3 4 5 6 7 7+ 8 8+ 9 9+ 10 10+ 11 11+ 12 12+ 13 13+ 14 14+ 15"`
+	LevelMin     *float64 `json:"levelMin,omitempty" validate:"min=1,max=15"`
+	LevelMax     *float64 `json:"levelMax,omitempty" validate:"min=1,max=15"`
+	TargetCount  *int32   `json:"targetCount,omitempty" validate:"min=1,max=10000"`
+}
+
+// apply returns goal with the given fields replaced
+func (p goalParameters) apply(goal database.Goal) database.Goal {
+	goal.Title = ifNotNil(p.Title, goal.Title)
+	if p.TargetRating != nil {
+		goal.TargetRating = pgtype.Int4{Int32: *p.TargetRating, Valid: true}
+	}
+	if p.BeatmapID != nil {
+		goal.BeatmapID = pgtype.UUID{}
+		if id, err := uuid.Parse(*p.BeatmapID); err == nil {
+			goal.BeatmapID = pgtype.UUID{Bytes: id, Valid: true}
+		}
+	}
+	goal.TargetRank = textOrKeep(p.TargetRank, goal.TargetRank)
+	goal.TargetLamp = textOrKeep(p.TargetLamp, goal.TargetLamp)
+	goal.Difficulty = textOrKeep(p.Difficulty, goal.Difficulty)
+	goal.Type = textOrKeep(p.Type, goal.Type)
+	goal.Level = textOrKeep(p.Level, goal.Level)
+	goal.LevelMin = numericOrKeep(p.LevelMin, goal.LevelMin)
+	goal.LevelMax = numericOrKeep(p.LevelMax, goal.LevelMax)
+	if p.TargetCount != nil {
+		goal.TargetCount = pgtype.Int4{Int32: *p.TargetCount, Valid: true}
+	}
+	return goal
+}
+
+func numericOrKeep(v *float64, fallback pgtype.Numeric) pgtype.Numeric {
+	if v == nil {
+		return fallback
+	}
+	var n pgtype.Numeric
+	if err := n.Scan(strconv.FormatFloat(*v, 'f', -1, 64)); err != nil {
+		return fallback
+	}
+	return n
+}
+
+// checkGoal reports the fields a goal of its kind is missing or must not
+// have:
+//
+//	rating     targetRating
+//	chart      beatmapID, targetRank and/or targetLamp
+//	chart_set  targetRank and/or targetLamp, optional chart filters
+//	count      as chart_set, plus targetCount
+func checkGoal(goal database.Goal) error {
+	var errs []utils.FieldError
+	required := func(field string, set bool) {
+		if !set {
+			errs = append(errs, utils.FieldError{Field: field, Message: fmt.Sprintf("is required for %s goals", goal.Kind)})
+		}
+	}
+	forbidden := func(field string, set bool) {
+		if set {
+			errs = append(errs, utils.FieldError{Field: field, Message: fmt.Sprintf("does not apply to %s goals", goal.Kind)})
+		}
+	}
+	target := func() {
+		if !goal.TargetRank.Valid && !goal.TargetLamp.Valid {
+			errs = append(errs, utils.FieldError{Field: "targetRank", Message: fmt.Sprintf("targetRank or targetLamp is required for %s goals", goal.Kind)})
+		}
+	}
+	filters := goal.Difficulty.Valid || goal.Type.Valid || goal.Level.Valid || goal.LevelMin.Valid || goal.LevelMax.Valid
+
+	switch goal.Kind {
+	case "rating":
+		required("targetRating", goal.TargetRating.Valid)
+		forbidden("beatmapID", goal.BeatmapID.Valid)
+		forbidden("targetRank", goal.TargetRank.Valid)
+		forbidden("targetLamp", goal.TargetLamp.Valid)
+		forbidden("targetCount", goal.TargetCount.Valid)
+		if filters {
+			errs = append(errs, utils.FieldError{Field: "kind", Message: "rating goals take no chart filters"})
+		}
+	case "chart":
+		required("beatmapID", goal.BeatmapID.Valid)
+		target()
+		forbidden("targetRating", goal.TargetRating.Valid)
+		forbidden("targetCount", goal.TargetCount.Valid)
+		if filters {
+			errs = append(errs, utils.FieldError{Field: "kind", Message: "chart goals take no chart filters"})
+		}
+	case "chart_set", "count":
+		target()
+		forbidden("targetRating", goal.TargetRating.Valid)
+		forbidden("beatmapID", goal.BeatmapID.Valid)
+		if goal.Kind == "count" {
+			required("targetCount", goal.TargetCount.Valid)
+		} else {
+			forbidden("targetCount", goal.TargetCount.Valid)
+		}
+		lo, _ := goal.LevelMin.Float64Value()
+		hi, _ := goal.LevelMax.Float64Value()
+		if lo.Valid && hi.Valid && lo.Float64 > hi.Float64 {
+			errs = append(errs, utils.FieldError{Field: "levelMin", Message: "must not be greater than levelMax"})
+		}
+	}
+	if len(errs) > 0 {
+		return utils.ValidationFailed(errs...)
+	}
+	return nil
+}
+
+// goals of the signed in user, oldest first
+//
+//	?completed=true|false
+func (h *Handler) GetMyGoals(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	var completed pgtype.Bool
+	if v := r.URL.Query().Get("completed"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.RespondWithError(w, utils.Invalid("completed", "must be true or false"))
+			return
+		}
+		completed = pgtype.Bool{Bool: b, Valid: true}
+	}
+
+	goals, err := h.queries.GetGoalsByUserID(r.Context(), database.GetGoalsByUserIDParams{
+		UserID:    claims.UserID,
+		Completed: completed,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetGoalsByUserID: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewGoals(goals))
+}
+
+func (h *Handler) GetMyGoal(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.myGoal(w, r)
+	if !ok {
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewGoal(goal))
+}
+
+// creates a goal and evaluates it right away
+func (h *Handler) CreateMyGoal(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	var params goalParameters
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+	if params.Kind == nil {
+		utils.RespondWithError(w, utils.Invalid("kind", "is required"))
+		return
+	}
+	goal := params.apply(database.Goal{Kind: *params.Kind})
+	if err := checkGoal(goal); err != nil {
+		utils.RespondWithError(w, err)
+		return
+	}
+	if !h.checkGoalBeatmap(w, r, goal) {
+		return
+	}
+
+	user, err := h.queries.GetUserByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserByUserID: %w", err)))
+		return
+	}
+	count, err := h.queries.CountGoalsByUserUUID(r.Context(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CountGoalsByUserUUID: %w", err)))
+		return
+	}
+	if count >= maxGoals {
+		utils.RespondWithError(w, utils.Conflict(utils.CodeConflict, "You already have %d goals", maxGoals))
+		return
+	}
+
+	created, err := h.queries.CreateGoal(r.Context(), database.CreateGoalParams{
+		ID:           uuid.New(),
+		UserUuid:     user.ID,
+		Kind:         goal.Kind,
+		Title:        goal.Title,
+		TargetRating: goal.TargetRating,
+		BeatmapID:    goal.BeatmapID,
+		TargetRank:   goal.TargetRank,
+		TargetLamp:   goal.TargetLamp,
+		Difficulty:   goal.Difficulty,
+		Type:         goal.Type,
+		Level:        goal.Level,
+		LevelMin:     goal.LevelMin,
+		LevelMax:     goal.LevelMax,
+		TargetCount:  goal.TargetCount,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateGoal: %w", err)))
+		return
+	}
+
+	evaluated, err := service.EvaluateGoal(h.queries, created)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("EvaluateGoal: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 201, response.NewGoal(evaluated))
+}
+
+// updates a goal, its kind cannot change. The goal is evaluated again
+// and its completion reset.
+func (h *Handler) UpdateMyGoal(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.myGoal(w, r)
+	if !ok {
+		return
+	}
+
+	var params goalParameters
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+	if params.Kind != nil && *params.Kind != current.Kind {
+		utils.RespondWithError(w, utils.Invalid("kind", "cannot be changed"))
+		return
+	}
+	goal := params.apply(current)
+	if err := checkGoal(goal); err != nil {
+		utils.RespondWithError(w, err)
+		return
+	}
+	if !h.checkGoalBeatmap(w, r, goal) {
+		return
+	}
+
+	updated, err := h.queries.UpdateGoal(r.Context(), database.UpdateGoalParams{
+		ID:           goal.ID,
+		Title:        goal.Title,
+		TargetRating: goal.TargetRating,
+		BeatmapID:    goal.BeatmapID,
+		TargetRank:   goal.TargetRank,
+		TargetLamp:   goal.TargetLamp,
+		Difficulty:   goal.Difficulty,
+		Type:         goal.Type,
+		Level:        goal.Level,
+		LevelMin:     goal.LevelMin,
+		LevelMax:     goal.LevelMax,
+		TargetCount:  goal.TargetCount,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("UpdateGoal: %w", err)))
+		return
+	}
+
+	evaluated, err := service.EvaluateGoal(h.queries, updated)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("EvaluateGoal: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewGoal(evaluated))
+}
+
+func (h *Handler) DeleteMyGoal(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+	goalID, err := uuid.Parse(chi.URLParam(r, "goalID"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("goalID", "must be a UUID"))
+		return
+	}
+
+	deleted, err := h.queries.DeleteGoal(r.Context(), database.DeleteGoalParams{
+		ID:     goalID,
+		UserID: claims.UserID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("DeleteGoal: %w", err)))
+		return
+	}
+	if deleted == 0 {
+		utils.RespondWithError(w, utils.NotFound("No goal found with ID '%s'", goalID))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// myGoal loads the goal in the URL, which must belong to the signed in user
+func (h *Handler) myGoal(w http.ResponseWriter, r *http.Request) (database.Goal, bool) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return database.Goal{}, false
+	}
+	goalID, err := uuid.Parse(chi.URLParam(r, "goalID"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("goalID", "must be a UUID"))
+		return database.Goal{}, false
+	}
+
+	goal, err := h.queries.GetGoalByUserID(r.Context(), database.GetGoalByUserIDParams{
+		ID:     goalID,
+		UserID: claims.UserID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetGoalByUserID: %w", err), fmt.Sprintf("No goal found with ID '%s'", goalID)))
+		return database.Goal{}, false
+	}
+	return goal, true
+}
+
+// the beatmap of a chart goal must exist
+func (h *Handler) checkGoalBeatmap(w http.ResponseWriter, r *http.Request, goal database.Goal) bool {
+	if !goal.BeatmapID.Valid {
+		return true
+	}
+	_, err := h.queries.GetBeatmapByBeatmapID(r.Context(), goal.BeatmapID.Bytes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.RespondWithError(w, utils.Invalid("beatmapID", "no beatmap found with ID '%s'", uuid.UUID(goal.BeatmapID.Bytes)))
+		return false
+	}
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetBeatmapByBeatmapID: %w", err)))
+		return false
+	}
+	return true
+}
@@ -2,15 +2,19 @@ package handler
 
 import (
 	"github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/service"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Handler struct {
-	queries *sqlc.Queries
+	queries      *sqlc.Queries
+	loginLockout *service.LoginLockout
 }
 
 func New(pool *pgxpool.Pool) *Handler {
+	queries := sqlc.New(pool)
 	return &Handler{
-		queries: sqlc.New(pool),
+		queries:      queries,
+		loginLockout: service.NewLoginLockout(queries),
 	}
 }
@@ -13,5 +13,5 @@ func HealthCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func ErrorCheck(w http.ResponseWriter, r *http.Request) {
-	utils.RespondWithError(w, 400, "Something went wrong")
+	utils.RespondWithError(w, utils.BadRequest("Something went wrong"))
 }
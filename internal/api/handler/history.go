@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// history buckets, each keeps the last snapshot taken in it
+const (
+	bucketSnapshot = "snapshot"
+	bucketDay      = "day"
+	bucketWeek     = "week"
+)
+
+// GetRatingHistory returns the user's rating and play counts over time.
+// Snapshots that did not change anything are left out.
+//
+//	?bucket=snapshot|day|week&dateFrom=&dateTo=
+//
+// dateFrom and dateTo are JST dates, both inclusive.
+func (h *Handler) GetRatingHistory(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeRatingHistory); !ok {
+		return
+	}
+
+	q := newListQuery(r, listOptions{})
+	bucket := q.text("bucket", bucketSnapshot, bucketDay, bucketWeek)
+	from, to := q.dateRange("date")
+	if q.respondIfInvalid(w) {
+		return
+	}
+	if !bucket.Valid {
+		bucket.String = bucketSnapshot
+	}
+
+	// snapshots before dateFrom are still needed for the first delta
+	snapshots, err := h.queries.GetUserDataHistoryByUserID(r.Context(), database.GetUserDataHistoryByUserIDParams{
+		UserID:        userID,
+		CreatedBefore: to,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserDataHistoryByUserID: %w", err), ""))
+		return
+	}
+
+	points := historyPoints(snapshots, bucket.String)
+	if from.Valid {
+		start := 0
+		for start < len(points) && points[start].RecordedAt.Before(from.Time) {
+			start++
+		}
+		points = points[start:]
+	}
+	utils.RespondWithJSON(w, 200, response.RatingHistory{
+		Bucket: bucket.String,
+		Points: points,
+	})
+}
+
+// historyPoints keeps the last snapshot of every bucket and computes the
+// deltas between them.
+func historyPoints(snapshots []database.GetUserDataHistoryByUserIDRow, bucket string) []response.RatingHistoryPoint {
+	points := make([]response.RatingHistoryPoint, 0, len(snapshots))
+	for _, s := range snapshots {
+		if !s.CreatedAt.Valid {
+			continue
+		}
+		point := response.RatingHistoryPoint{
+			Date:            bucketStart(s.CreatedAt.Time, bucket).Format("2006-01-02"),
+			RecordedAt:      s.CreatedAt.Time,
+			Rating:          s.Rating,
+			SeasonPlayCount: s.SeasonPlayCount,
+			TotalPlayCount:  s.TotalPlayCount,
+		}
+		if bucket != bucketSnapshot && len(points) > 0 && points[len(points)-1].Date == point.Date {
+			points[len(points)-1] = point
+			continue
+		}
+		points = append(points, point)
+	}
+
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], &points[i]
+		cur.RatingDelta = delta(cur.Rating, prev.Rating)
+		cur.SeasonPlayCountDelta = delta(cur.SeasonPlayCount, prev.SeasonPlayCount)
+		cur.TotalPlayCountDelta = delta(cur.TotalPlayCount, prev.TotalPlayCount)
+	}
+
+	// bucketing can leave neighbours with the same values
+	if bucket != bucketSnapshot {
+		deduped := points[:0]
+		for i, p := range points {
+			if i > 0 && *p.RatingDelta == 0 && *p.SeasonPlayCountDelta == 0 && *p.TotalPlayCountDelta == 0 {
+				continue
+			}
+			deduped = append(deduped, p)
+		}
+		points = deduped
+	}
+	return points
+}
+
+// JST day, or the Monday of the JST week, t falls in
+func bucketStart(t time.Time, bucket string) time.Time {
+	day := utils.JSTDay(t)
+	if bucket == bucketWeek {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+func delta(cur, prev int32) *int32 {
+	d := cur - prev
+	return &d
+}
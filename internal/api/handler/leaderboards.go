@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// me is the caller's own entry wherever it is on the leaderboard, null
+// when signed out or not on it
+type BeatmapLeaderboardResponse struct {
+	Entries    []response.BeatmapLeaderboardEntry `json:"entries"`
+	Me         *response.BeatmapLeaderboardEntry  `json:"me"`
+	NextCursor string                             `json:"nextCursor,omitempty"`
+	HasMore    bool                               `json:"hasMore"`
+}
+
+type RatingLeaderboardResponse struct {
+	Entries    []response.RatingLeaderboardEntry `json:"entries"`
+	Me         *response.RatingLeaderboardEntry  `json:"me"`
+	NextCursor string                            `json:"nextCursor,omitempty"`
+	HasMore    bool                              `json:"hasMore"`
+}
+
+// the sort keys of the last entry of a page, see cursorKeys
+type beatmapLeaderboardKey struct {
+	Achievement pgtype.Numeric `json:"achievement"`
+	DxScore     int32          `json:"dxScore"`
+	PlayedAt    time.Time      `json:"playedAt"`
+}
+
+type ratingLeaderboardKey struct {
+	Rating int32  `json:"rating"`
+	UserID string `json:"userID"`
+}
+
+// leaderboards are always ranked best first
+func newLeaderboardQuery(r *http.Request) *listQuery {
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"rank": false},
+		defaultSort:  "rank",
+		defaultLimit: 50,
+	})
+	if q.descending {
+		q.errorf("sort", "leaderboards can only be sorted by rank")
+	}
+	return q
+}
+
+// the signed in user's userID, invalid when signed out
+func viewerID(r *http.Request) pgtype.Text {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: claims.UserID, Valid: true}
+}
+
+// ranks every user's best score of the beatmap, users who hide their
+// scores are left out
+//
+//	?limit=&cursor=
+func (h *Handler) GetBeatmapLeaderboard(w http.ResponseWriter, r *http.Request) {
+	beatmapID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("id", "must be a UUID"))
+		return
+	}
+
+	q := newLeaderboardQuery(r)
+	viewer := viewerID(r)
+	params := database.GetBeatmapLeaderboardParams{
+		BeatmapID: beatmapID,
+		ViewerID:  viewer,
+		PageSize:  q.pageSize(),
+	}
+	var after beatmapLeaderboardKey
+	if q.cursorKeys(&after) {
+		if !after.Achievement.Valid {
+			q.errorf("cursor", "invalid cursor")
+		}
+		params.CursorAchievement = after.Achievement
+		params.CursorDxScore = after.DxScore
+		params.CursorPlayedAt = pgtype.Timestamp{Time: after.PlayedAt, Valid: true}
+		params.CursorID = q.cursorID()
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	if _, err := h.queries.GetBeatmapByBeatmapID(r.Context(), beatmapID); err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetBeatmapByBeatmapID: %w", err), fmt.Sprintf("No beatmap found with provided id '%s'", beatmapID)))
+		return
+	}
+
+	entries, err := h.queries.GetBeatmapLeaderboard(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetBeatmapLeaderboard: %w", err), ""))
+		return
+	}
+
+	var me *response.BeatmapLeaderboardEntry
+	if viewer.Valid {
+		entry, err := h.queries.GetBeatmapLeaderboardEntry(r.Context(), database.GetBeatmapLeaderboardEntryParams{
+			BeatmapID: beatmapID,
+			ViewerID:  viewer,
+		})
+		switch {
+		case err == nil:
+			e := response.NewBeatmapLeaderboardEntry(entry, viewer.String)
+			me = &e
+		case !errors.Is(err, pgx.ErrNoRows):
+			utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetBeatmapLeaderboardEntry: %w", err)))
+			return
+		}
+	}
+
+	entries, next := page(q, entries, func(e database.GetBeatmapLeaderboardRow) (string, uuid.UUID) {
+		return compositeKey(beatmapLeaderboardKey{
+			Achievement: e.Achievement,
+			DxScore:     e.DxScore,
+			PlayedAt:    e.PlayedAt.Time,
+		}), e.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, BeatmapLeaderboardResponse{
+		Entries:    response.NewBeatmapLeaderboardEntries(entries, viewer.String),
+		Me:         me,
+		NextCursor: next,
+		HasMore:    next != "",
+	})
+}
+
+// ranks users by their latest rating, users who hide their profile are
+// left out
+//
+//	?limit=&cursor=
+func (h *Handler) GetRatingLeaderboard(w http.ResponseWriter, r *http.Request) {
+	q := newLeaderboardQuery(r)
+	viewer := viewerID(r)
+	params := database.GetRatingLeaderboardParams{
+		ViewerID: viewer,
+		PageSize: q.pageSize(),
+	}
+	var after ratingLeaderboardKey
+	if q.cursorKeys(&after) {
+		params.CursorRating = pgtype.Int4{Int32: after.Rating, Valid: true}
+		params.CursorUserID = after.UserID
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	entries, err := h.queries.GetRatingLeaderboard(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetRatingLeaderboard: %w", err), ""))
+		return
+	}
+
+	var me *response.RatingLeaderboardEntry
+	if viewer.Valid {
+		entry, err := h.queries.GetRatingLeaderboardEntry(r.Context(), viewer)
+		switch {
+		case err == nil:
+			e := response.NewRatingLeaderboardEntry(entry, viewer.String)
+			me = &e
+		case !errors.Is(err, pgx.ErrNoRows):
+			utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetRatingLeaderboardEntry: %w", err)))
+			return
+		}
+	}
+
+	entries, next := page(q, entries, func(e database.GetRatingLeaderboardRow) (string, uuid.UUID) {
+		return compositeKey(ratingLeaderboardKey{Rating: e.Rating, UserID: e.UserID}), e.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, RatingLeaderboardResponse{
+		Entries:    response.NewRatingLeaderboardEntries(entries, viewer.String),
+		Me:         me,
+		NextCursor: next,
+		HasMore:    next != "",
+	})
+}
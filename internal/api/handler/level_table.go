@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// every chart of a level, or of an internal level range, with the user's
+// bests, utage left out, and how many charts reached each rank, lamp and
+// DX star count. dxStarsBelow keeps only the played charts under that
+// many DX stars.
+//
+//	?level=13%2B|levelMin=&levelMax=&difficulty=&type=&dxStarsBelow=
+func (h *Handler) GetLevelTable(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	q := newListQuery(r, listOptions{})
+	level := q.text("level", levels...)
+	levelMin, levelMax := q.numericRange("level", 0, 15)
+	if q.values.Get("level") == "" && q.values.Get("levelMin") == "" && q.values.Get("levelMax") == "" {
+		q.errorf("level", "level or levelMin/levelMax is required")
+	}
+	params := database.GetLevelTableByUserIDParams{
+		UserID:     userID,
+		Level:      level,
+		LevelMin:   levelMin,
+		LevelMax:   levelMax,
+		Difficulty: q.text("difficulty", "basic", "advanced", "expert", "master", "remaster"),
+		Type:       q.text("type", beatmapTypes...),
+	}
+	below := q.dxStarsBelow("dxStarsBelow")
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	charts, err := h.queries.GetLevelTableByUserID(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetLevelTableByUserID: %w", err)))
+		return
+	}
+
+	if below > 0 {
+		charts = belowDxStars(charts, below)
+	}
+
+	out := levelTable(charts)
+	if level.Valid {
+		out.Level = &level.String
+	}
+	if v, err := levelMin.Float64Value(); err == nil && v.Valid {
+		out.LevelMin = &v.Float64
+	}
+	if v, err := levelMax.Float64Value(); err == nil && v.Valid {
+		out.LevelMax = &v.Float64
+	}
+	utils.RespondWithJSON(w, 200, out)
+}
+
+// charts come hardest first
+func levelTable(rows []database.GetLevelTableByUserIDRow) response.LevelTable {
+	out := response.LevelTable{
+		Charts:  len(rows),
+		Ranks:   []response.LevelTableCount{},
+		Lamps:   []response.LevelTableCount{},
+		DxStars: []response.LevelTableCount{},
+		Tiers:   []response.LevelTier{},
+	}
+	ranks := map[string]int{}
+	lamps := map[string]int{}
+	stars := map[int]int{}
+
+	for i, row := range rows {
+		chart := response.NewLevelTableChart(row)
+		if i == 0 || !sameLevel(chart.InternalLevel, out.Tiers[len(out.Tiers)-1].InternalLevel) {
+			out.Tiers = append(out.Tiers, response.LevelTier{InternalLevel: chart.InternalLevel})
+		}
+		tier := &out.Tiers[len(out.Tiers)-1]
+		tier.Charts = append(tier.Charts, chart)
+
+		if chart.Rank == nil {
+			continue
+		}
+		out.Played++
+		ranks[*chart.Rank]++
+		if chart.Lamp != nil {
+			lamps[*chart.Lamp]++
+		}
+		if chart.DxStars != nil {
+			stars[*chart.DxStars]++
+		}
+	}
+
+	for _, rank := range calculator.Ranks {
+		out.Ranks = append(out.Ranks, response.LevelTableCount{Name: rank.Name, Count: ranks[rank.Name]})
+	}
+	for l := calculator.LampAPPlus; l > calculator.LampNone; l-- {
+		out.Lamps = append(out.Lamps, response.LevelTableCount{Name: l.String(), Count: lamps[l.String()]})
+	}
+	out.DxStars = dxStarDistribution(stars)
+	return out
+}
+
+func sameLevel(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/api/openapi"
+)
+
+// serves the OpenAPI description of the v1 API
+func GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(openapi.Spec)
+}
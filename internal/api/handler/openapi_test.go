@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/openapi"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func newValidator(t *testing.T) *openapi.Validator {
+	t.Helper()
+	v, err := openapi.NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// requests that are answered before the database is needed, run through
+// the real handlers and middleware
+func TestHandlerResponsesMatchSpec(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test")
+	v := newValidator(t)
+	h := New(nil)
+	m := middleware.New(nil)
+
+	tests := []struct {
+		name    string
+		method  string
+		route   string
+		handler http.HandlerFunc
+		url     string
+		body    string
+		status  int
+	}{
+		{"health", "GET", "/healthz", HealthCheck, "/healthz", "", 200},
+		{"spec", "GET", "/openapi.json", GetOpenAPISpec, "/openapi.json", "", 200},
+		{"error", "GET", "/err", ErrorCheck, "/err", "", 400},
+		{"unauthenticated", "GET", "/me/goals", m.Auth(h.GetMyGoals), "/me/goals", "", 401},
+		{"invalid token", "GET", "/me/feed", m.Auth(h.GetMyFeed), "/me/feed", "", 401},
+		{"level table without level", "GET", "/users/by-user-id/{userID}/level-table", h.GetLevelTable, "/users/by-user-id/someone/level-table", "", 400},
+		{"dx stars invalid filters", "GET", "/users/by-user-id/{userID}/dx-stars", h.GetDxStars, "/users/by-user-id/someone/dx-stars?below=9&levelMin=14&levelMax=13", "", 400},
+		{"songs invalid sort", "GET", "/songs", h.GetAllSongs, "/songs?sort=nope&limit=0", "", 400},
+		{"score missing fields", "POST", "/scores", h.CreateScore, "/scores", `{}`, 400},
+		{"simulate invalid id", "POST", "/beatmaps/{id}/simulate", h.SimulateBeatmap, "/beatmaps/nope/simulate", `{}`, 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.MethodFunc(tt.method, tt.route, tt.handler)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.name == "invalid token" {
+				req.Header.Set("Authorization", "Bearer nope")
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if err := v.ValidateResponse(tt.method, tt.route, rec.Code, rec.Body.Bytes()); err != nil {
+				t.Errorf("response does not match the spec:\n%s\n%s", err, rec.Body)
+			}
+		})
+	}
+}
+
+func pgTime(s string) pgtype.Timestamp {
+	t, _ := time.Parse(time.RFC3339, s)
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+func pgNumeric(s string) pgtype.Numeric {
+	var n pgtype.Numeric
+	n.Scan(s)
+	return n
+}
+
+// representative bodies the handlers build from query rows
+func TestResponseBodiesMatchSpec(t *testing.T) {
+	v := newValidator(t)
+
+	beatmapID, songID := uuid.New(), uuid.New()
+	best := database.GetPersonalBestsByUserIDRow{
+		ID:            uuid.New(),
+		BeatmapID:     beatmapID,
+		SongID:        songID,
+		Accuracy:      "100.5000%",
+		DxScore:       2400,
+		PlayedAt:      pgTime("2025-06-01T12:00:00Z"),
+		Title:         "系ぎて",
+		ImageUrl:      "x.png",
+		Difficulty:    "master",
+		Level:         "14+",
+		InternalLevel: pgNumeric("14.7"),
+		Type:          "dx",
+		MaxDxScore:    2500,
+	}
+	rivalBest := best
+	rivalBest.ID = uuid.New()
+	rivalBest.Accuracy = "99.8000%"
+	unplayed := database.GetLevelTableByUserIDRow{
+		BeatmapID:     uuid.New(),
+		SongID:        uuid.New(),
+		Title:         "極圏",
+		ImageUrl:      "y.png",
+		Difficulty:    "master",
+		Level:         "14+",
+		InternalLevel: pgNumeric("14.7"),
+		Type:          "dx",
+	}
+	played := unplayed
+	played.BeatmapID = beatmapID
+	played.Accuracy = pgtype.Text{String: "100.5000%", Valid: true}
+	played.Achievement = pgtype.Float8{Float64: 100.5, Valid: true}
+	played.Lamp = pgtype.Int4{Int32: 2, Valid: true}
+	played.DxScore = pgtype.Int4{Int32: 2400, Valid: true}
+	played.MaxDxScore = 2500
+
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		status int
+		body   any
+	}{
+		{"user", "GET", "/users/by-user-id/{userID}", 200, response.NewUser(database.GetUserByUserIDRow{
+			ID:            uuid.New(),
+			UserID:        "someone",
+			DisplayName:   "someone",
+			LastPlayedAt:  pgTime("2025-06-01T12:00:00Z"),
+			ScrapeStatus:  pgtype.Text{String: "ok", Valid: true},
+			Rating:        15000,
+			Bio:           pgtype.Text{String: "hi", Valid: true},
+			TwitterID:     pgtype.Text{String: "someone", Valid: true},
+			LastScrapedAt: pgTime("2025-06-01T12:30:00Z"),
+		})},
+		{"created score", "POST", "/scores", 200, response.NewScore(database.Score{
+			ID:        uuid.New(),
+			BeatmapID: beatmapID,
+			SongID:    songID,
+			Accuracy:  "100.5000%",
+			DxScore:   2400,
+			PlayedAt:  pgTime("2025-06-01T12:00:00Z"),
+		}).WithDxStars(2500)},
+		{"goal", "GET", "/me/goals/{goalID}", 200, response.NewGoal(database.Goal{
+			ID:          uuid.New(),
+			Kind:        "chart",
+			Title:       "SSS+ 系ぎて",
+			BeatmapID:   pgtype.UUID{Bytes: beatmapID, Valid: true},
+			TargetRank:  pgtype.Text{String: "SSS+", Valid: true},
+			Progress:    1,
+			Target:      1,
+			CompletedAt: pgTime("2025-06-01T12:00:00Z"),
+			UpdatedAt:   pgTime("2025-06-01T12:00:00Z"),
+			CreatedAt:   pgTime("2025-05-01T12:00:00Z"),
+		})},
+		{"level table", "GET", "/users/by-user-id/{userID}/level-table", 200, levelTable([]database.GetLevelTableByUserIDRow{played, unplayed})},
+		{"head to head", "GET", "/users/by-user-id/{userID}/compare/{rivalID}", 200, headToHead("someone", "rival", []database.GetPersonalBestsByUserIDRow{best}, []database.GetPersonalBestsByUserIDRow{rivalBest})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := v.ValidateResponse(tt.method, tt.route, tt.status, b); err != nil {
+				t.Errorf("response does not match the spec:\n%s\n%s", err, b)
+			}
+		})
+	}
+}
+
+func TestRequestBodiesMatchSpec(t *testing.T) {
+	v := newValidator(t)
+
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		body   string
+	}{
+		{"follow", "POST", "/me/follows", `{"userID":"someone"}`},
+		{"privacy", "PATCH", "/me/privacy", `{"scoresVisibility":"friends"}`},
+		{"goal", "POST", "/me/goals", `{"kind":"rating","title":"15000","targetRating":15000}`},
+		{"song", "POST", "/songs", `{"title":"極圏","titleKana":"きょくけん","artist":"cosMo@暴走P","genre":"maimai","bpm":"180","imageUrl":"x.png","version":"PRiSM","releaseDate":"2025-01-01"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.ValidateRequest(tt.method, tt.route, []byte(tt.body)); err != nil {
+				t.Errorf("request does not match the spec:\n%s", err)
+			}
+		})
+	}
+}
+
+// the checks above are only as good as the validator
+func TestValidatorRejectsMismatches(t *testing.T) {
+	v := newValidator(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"undocumented field", `{"userID":"someone","password":"x","extra":true}`},
+		{"missing field", `{"userID":"someone"}`},
+		{"wrong type", `{"userID":5,"password":"x"}`},
+		{"null", `{"userID":null,"password":"x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.ValidateSchema("LoginRequest", []byte(tt.body)); err == nil {
+				t.Errorf("%s was accepted", tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// List endpoints use keyset pagination. Each list query computes the
+// requested sort key with its own type, sort_number for numbers, sort_time
+// for timestamps and sort_value for text, and pages over (key, id), so the
+// cursor handed to the client is the sort key, direction and the key and
+// id of the last row it received.
+//
+//	GET /v1/songs?version=PRiSM&sort=-releaseDate&limit=50
+//	Link: </v1/songs?version=PRiSM&sort=-releaseDate&limit=50&cursor=...>; rel="next"
+
+const maxPageSize = 500
+
+var (
+	difficulties = []string{"basic", "advanced", "expert", "master", "remaster", "utage"}
+	beatmapTypes = []string{"dx", "std", "utage"}
+	levels       = []string{"1", "2", "3", "4", "5", "6", "7", "7+", "8", "8+", "9", "9+", "10", "10+", "11", "11+", "12", "12+", "13", "13+", "14", "14+", "15"}
+)
+
+type listOptions struct {
+	// sort keys mapped to whether they sort descending by default
+	sorts        map[string]bool
+	defaultSort  string
+	defaultLimit int
+}
+
+// listQuery parses and validates the query parameters of a list request.
+// Errors are collected so every invalid parameter is reported at once.
+type listQuery struct {
+	values url.Values
+	errs   []utils.FieldError
+
+	sort       string
+	descending bool
+	limit      int
+	cursor     *listCursor
+}
+
+type listCursor struct {
+	Sort       string    `json:"sort"`
+	Descending bool      `json:"desc"`
+	Value      string    `json:"value"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func newListQuery(r *http.Request, opts listOptions) *listQuery {
+	q := &listQuery{
+		values:     r.URL.Query(),
+		sort:       opts.defaultSort,
+		descending: opts.sorts[opts.defaultSort],
+		limit:      opts.defaultLimit,
+	}
+
+	if sort := q.values.Get("sort"); sort != "" {
+		key := strings.TrimPrefix(sort, "-")
+		if _, ok := opts.sorts[key]; ok {
+			q.sort = key
+			q.descending = strings.HasPrefix(sort, "-")
+		} else {
+			q.errorf("sort", "must be one of %s, optionally prefixed with '-'", strings.Join(sortKeys(opts.sorts), ", "))
+		}
+	}
+
+	if limit := q.values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageSize {
+			q.errorf("limit", "must be an integer between 1 and %d", maxPageSize)
+		} else {
+			q.limit = n
+		}
+	}
+
+	if cursor := q.values.Get("cursor"); cursor != "" {
+		c, err := decodeCursor(cursor)
+		switch {
+		case err != nil:
+			q.errorf("cursor", "invalid cursor")
+		case c.Sort != q.sort || c.Descending != q.descending:
+			q.errorf("cursor", "does not match the requested sort")
+		default:
+			q.cursor = &c
+		}
+	}
+
+	return q
+}
+
+func (q *listQuery) errorf(field, format string, args ...any) {
+	q.errs = append(q.errs, utils.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// respondIfInvalid writes a VALIDATION_FAILED listing every invalid parameter.
+func (q *listQuery) respondIfInvalid(w http.ResponseWriter) bool {
+	if len(q.errs) == 0 {
+		return false
+	}
+	utils.RespondWithError(w, utils.ValidationFailed(q.errs...))
+	return true
+}
+
+// optional text filter, restricted to allowed when given
+func (q *listQuery) text(name string, allowed ...string) pgtype.Text {
+	v := q.values.Get(name)
+	if v == "" {
+		return pgtype.Text{}
+	}
+	if len(allowed) > 0 && !slices.Contains(allowed, v) {
+		q.errorf(name, "must be one of %s", strings.Join(allowed, ", "))
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: v, Valid: true}
+}
+
+// optional number filter within [min, max]
+func (q *listQuery) numeric(name string, min, max float64) pgtype.Numeric {
+	v := q.values.Get(name)
+	if v == "" {
+		return pgtype.Numeric{}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || f < min || f > max {
+		q.errorf(name, "must be a number between %g and %g", min, max)
+		return pgtype.Numeric{}
+	}
+	var n pgtype.Numeric
+	if err := n.Scan(v); err != nil {
+		q.errorf(name, "must be a number between %g and %g", min, max)
+		return pgtype.Numeric{}
+	}
+	return n
+}
+
+// numericRange reads <name>Min and <name>Max
+func (q *listQuery) numericRange(name string, min, max float64) (pgtype.Numeric, pgtype.Numeric) {
+	lo := q.numeric(name+"Min", min, max)
+	hi := q.numeric(name+"Max", min, max)
+	if lo.Valid && hi.Valid {
+		l, _ := lo.Float64Value()
+		h, _ := hi.Float64Value()
+		if l.Float64 > h.Float64 {
+			q.errorf(name+"Min", "must not be greater than %sMax", name)
+		}
+	}
+	return lo, hi
+}
+
+// dateRange reads <name>From and <name>To as JST dates ("2006-01-02")
+// and returns UTC bounds, the upper one exclusive so the whole To day is
+// included.
+func (q *listQuery) dateRange(name string) (pgtype.Timestamp, pgtype.Timestamp) {
+	parse := func(param string) pgtype.Timestamp {
+		v := q.values.Get(param)
+		if v == "" {
+			return pgtype.Timestamp{}
+		}
+		t, err := utils.StringToUTCDate(v)
+		if err != nil {
+			q.errorf(param, "must be a date formatted as YYYY-MM-DD")
+			return pgtype.Timestamp{}
+		}
+		return pgtype.Timestamp{Time: t, Valid: true}
+	}
+
+	from := parse(name + "From")
+	to := parse(name + "To")
+	if to.Valid {
+		to.Time = to.Time.Add(24 * time.Hour)
+	}
+	if from.Valid && to.Valid && !from.Time.Before(to.Time) {
+		q.errorf(name+"From", "must not be after %sTo", name)
+	}
+	return from, to
+}
+
+// cursorValue is the key of the cursor as text, null on the first page.
+// Queries compare text sort keys with it directly.
+func (q *listQuery) cursorValue() pgtype.Text {
+	if q.cursor == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: q.cursor.Value, Valid: true}
+}
+
+// cursorNumber is the key of the cursor when the requested sort is one of
+// the numeric sorts, null otherwise
+func (q *listQuery) cursorNumber(sorts ...string) pgtype.Numeric {
+	if q.cursor == nil || !slices.Contains(sorts, q.sort) {
+		return pgtype.Numeric{}
+	}
+	var n pgtype.Numeric
+	if err := n.Scan(q.cursor.Value); err != nil || n.NaN {
+		q.errorf("cursor", "invalid cursor")
+		return pgtype.Numeric{}
+	}
+	return n
+}
+
+// cursorTime is the key of the cursor when the requested sort is one of
+// the timestamp sorts, null otherwise
+func (q *listQuery) cursorTime(sorts ...string) pgtype.Timestamp {
+	if q.cursor == nil || !slices.Contains(sorts, q.sort) {
+		return pgtype.Timestamp{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, q.cursor.Value)
+	if err != nil {
+		q.errorf("cursor", "invalid cursor")
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+func (q *listQuery) cursorID() uuid.UUID {
+	if q.cursor == nil {
+		return uuid.Nil
+	}
+	return q.cursor.ID
+}
+
+// one more row than the page size is fetched to know whether there is a next page
+func (q *listQuery) pageSize() int32 {
+	return int32(q.limit + 1)
+}
+
+// page trims rows to the page size and returns the cursor of the next
+// page, or "" when rows was the last page.
+func page[T any](q *listQuery, rows []T, key func(T) (string, uuid.UUID)) ([]T, string) {
+	if len(rows) <= q.limit {
+		return rows, ""
+	}
+	rows = rows[:q.limit]
+	value, id := key(rows[len(rows)-1])
+	return rows, encodeCursor(listCursor{
+		Sort:       q.sort,
+		Descending: q.descending,
+		Value:      value,
+		ID:         id,
+	})
+}
+
+// cursorKeys decodes the key of a cursor whose sort spans several columns
+// into keys, false on the first page
+func (q *listQuery) cursorKeys(keys any) bool {
+	if q.cursor == nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(q.cursor.Value), keys); err != nil {
+		q.errorf("cursor", "invalid cursor")
+		return false
+	}
+	return true
+}
+
+// compositeKey encodes the sort keys of a row for cursorKeys
+func compositeKey(keys any) string {
+	data, _ := json.Marshal(keys)
+	return string(data)
+}
+
+// numberKey and timeKey format a typed sort key of a row for its cursor
+func numberKey(n pgtype.Numeric) string {
+	v, _ := n.Value()
+	s, _ := v.(string)
+	return s
+}
+
+func timeKey(t pgtype.Timestamp) string {
+	return t.Time.Format(time.RFC3339Nano)
+}
+
+// setNextLink sets the Link header to the request URL with cursor replaced.
+func setNextLink(w http.ResponseWriter, r *http.Request, cursor string) {
+	if cursor == "" {
+		return
+	}
+	values := r.URL.Query()
+	values.Set("cursor", cursor)
+	next := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+}
+
+func encodeCursor(c listCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	var c listCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+func sortKeys(sorts map[string]bool) []string {
+	keys := make([]string, 0, len(sorts))
+	for k := range sorts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
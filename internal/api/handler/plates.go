@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// plate progress of a user for every version, oldest version first
+func (h *Handler) GetPlates(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	charts, err := h.queries.GetPlateChartsByUserID(r.Context(), database.GetPlateChartsByUserIDParams{UserID: userID})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetPlateChartsByUserID: %w", err)))
+		return
+	}
+
+	out := []response.VersionPlates{}
+	for _, version := range groupByVersion(charts) {
+		summary := response.VersionPlates{Version: version[0].Version, Charts: len(version)}
+		for _, p := range plateProgress(version) {
+			summary.Plates = append(summary.Plates, p.PlateSummary)
+		}
+		out = append(out, summary)
+	}
+	utils.RespondWithJSON(w, 200, out)
+}
+
+// plate progress of a user for one version, with the charts left for
+// each plate
+func (h *Handler) GetVersionPlates(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	version, err := url.QueryUnescape(chi.URLParam(r, "version"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("version", "must be URL encoded"))
+		return
+	}
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	charts, err := h.queries.GetPlateChartsByUserID(r.Context(), database.GetPlateChartsByUserIDParams{
+		UserID:  userID,
+		Version: pgtype.Text{String: version, Valid: true},
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetPlateChartsByUserID: %w", err)))
+		return
+	}
+	if len(charts) == 0 {
+		utils.RespondWithError(w, utils.NotFound("No charts found for version '%s'", version))
+		return
+	}
+
+	utils.RespondWithJSON(w, 200, response.VersionPlateProgress{
+		Version: version,
+		Charts:  len(charts),
+		Plates:  plateProgress(charts),
+	})
+}
+
+// charts come ordered by version
+func groupByVersion(charts []database.GetPlateChartsByUserIDRow) [][]database.GetPlateChartsByUserIDRow {
+	var groups [][]database.GetPlateChartsByUserIDRow
+	for i, c := range charts {
+		if i == 0 || c.Version != charts[i-1].Version {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], c)
+	}
+	return groups
+}
+
+func plateProgress(charts []database.GetPlateChartsByUserIDRow) []response.PlateProgress {
+	out := make([]response.PlateProgress, 0, len(calculator.Plates))
+	for _, plate := range calculator.Plates {
+		p := response.PlateProgress{
+			PlateSummary: response.PlateSummary{
+				Name:        plate.Name,
+				Requirement: plate.Requirement,
+				Tracked:     plate.Tracked(),
+			},
+			ByDifficulty:    []response.PlateDifficulty{},
+			RemainingCharts: []response.PlateChart{},
+		}
+		if !plate.Tracked() {
+			out = append(out, p)
+			continue
+		}
+
+		byDifficulty := map[string]*response.PlateDifficulty{}
+		cleared := 0
+		for _, c := range charts {
+			d, ok := byDifficulty[c.Difficulty]
+			if !ok {
+				d = &response.PlateDifficulty{Difficulty: c.Difficulty}
+				byDifficulty[c.Difficulty] = d
+			}
+			d.Total++
+			if c.Achievement.Valid && plate.Reached(c.Achievement.Float64, calculator.Lamp(c.Lamp.Int32)) {
+				d.Cleared++
+				cleared++
+			} else {
+				p.RemainingCharts = append(p.RemainingCharts, response.NewPlateChart(c))
+			}
+		}
+		for _, difficulty := range calculator.PlateDifficulties {
+			if d, ok := byDifficulty[difficulty]; ok {
+				p.ByDifficulty = append(p.ByDifficulty, *d)
+			}
+		}
+		remaining := len(charts) - cleared
+		p.Cleared, p.Remaining = &cleared, &remaining
+		p.Complete = remaining == 0
+		out = append(out, p)
+	}
+	return out
+}
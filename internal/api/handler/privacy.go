@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/service"
+	"github.com/asashakira/maitrack/internal/utils"
+)
+
+const (
+	visibilityPublic  = "public"
+	visibilityFriends = "friends"
+	visibilityPrivate = "private"
+)
+
+// parts of a user's data that can be hidden from others
+type privacyScope int
+
+const (
+	scopeProfile privacyScope = iota
+	scopeScores
+	scopeRatingHistory
+)
+
+func (s privacyScope) visibility(p database.GetUserPrivacyByUserIDRow) string {
+	switch s {
+	case scopeProfile:
+		return p.ProfileVisibility
+	case scopeScores:
+		return p.ScoresVisibility
+	case scopeRatingHistory:
+		return p.RatingHistoryVisibility
+	default:
+		return visibilityPrivate
+	}
+}
+
+// authorizeUserData checks that the requester may see the given scope of
+// userID's data. The owner can always see their own data.
+// Writes an error response and returns false if the request must stop.
+func (h *Handler) authorizeUserData(w http.ResponseWriter, r *http.Request, userID string, scope privacyScope) (database.GetUserPrivacyByUserIDRow, bool) {
+	privacy, err := h.queries.GetUserPrivacyByUserID(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserPrivacyByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", userID)))
+		return privacy, false
+	}
+
+	viewer, _ := middleware.ClaimsFromContext(r.Context())
+	allowed, err := h.canView(r.Context(), viewer, privacy, scope.visibility(privacy))
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("canView: %w", err)))
+		return privacy, false
+	}
+	if !allowed {
+		utils.RespondWithError(w, utils.NewAPIError(403, utils.CodeForbidden, "'%s' has made this data private", userID))
+		return privacy, false
+	}
+
+	return privacy, true
+}
+
+// canViewScope checks another scope of data of a user the requester was
+// authorized for with authorizeUserData
+func (h *Handler) canViewScope(r *http.Request, privacy database.GetUserPrivacyByUserIDRow, scope privacyScope) (bool, error) {
+	viewer, _ := middleware.ClaimsFromContext(r.Context())
+	return h.canView(r.Context(), viewer, privacy, scope.visibility(privacy))
+}
+
+func (h *Handler) canView(ctx context.Context, viewer *service.Claims, owner database.GetUserPrivacyByUserIDRow, visibility string) (bool, error) {
+	if viewer != nil && viewer.UserID == owner.UserID {
+		return true, nil
+	}
+
+	switch visibility {
+	case visibilityPublic:
+		return true, nil
+	case visibilityFriends:
+		if viewer == nil {
+			return false, nil
+		}
+		return h.areFriends(ctx, viewer.UserID, owner.UserID)
+	default:
+		return false, nil
+	}
+}
+
+// users that follow each other are friends
+func (h *Handler) areFriends(ctx context.Context, userID, otherUserID string) (bool, error) {
+	return h.queries.AreFriends(ctx, database.AreFriendsParams{
+		UserID:      userID,
+		OtherUserID: otherUserID,
+	})
+}
+
+func (h *Handler) GetMyPrivacy(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	privacy, err := h.queries.GetUserPrivacyByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserPrivacyByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", claims.UserID)))
+		return
+	}
+
+	utils.RespondWithJSON(w, 200, response.NewPrivacy(privacy))
+}
+
+// update visibility of the authenticated user's data
+// each field is one of "public", "friends" or "private"
+func (h *Handler) UpdateMyPrivacy(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	type parameters struct {
+		ProfileVisibility       *string `json:"profileVisibility,omitempty" validate:"nonempty,oneof=public friends private"`
+		ScoresVisibility        *string `json:"scoresVisibility,omitempty" validate:"nonempty,oneof=public friends private"`
+		RatingHistoryVisibility *string `json:"ratingHistoryVisibility,omitempty" validate:"nonempty,oneof=public friends private"`
+	}
+	var params parameters
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+
+	current, err := h.queries.GetUserPrivacyByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserPrivacyByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", claims.UserID)))
+		return
+	}
+
+	updated, err := h.queries.UpsertUserPrivacy(r.Context(), database.UpsertUserPrivacyParams{
+		UserUuid:                current.UserUuid,
+		ProfileVisibility:       ifNotNil(params.ProfileVisibility, current.ProfileVisibility),
+		ScoresVisibility:        ifNotNil(params.ScoresVisibility, current.ScoresVisibility),
+		RatingHistoryVisibility: ifNotNil(params.RatingHistoryVisibility, current.RatingHistoryVisibility),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("UpsertUserPrivacy: %w", err)))
+		return
+	}
+
+	utils.RespondWithJSON(w, 200, response.NewPrivacy(database.GetUserPrivacyByUserIDRow{
+		UserUuid:                updated.UserUuid,
+		UserID:                  current.UserID,
+		ProfileVisibility:       updated.ProfileVisibility,
+		ScoresVisibility:        updated.ScoresVisibility,
+		RatingHistoryVisibility: updated.RatingHistoryVisibility,
+	}))
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/service"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// body of UpdateMyProfile
+type profileUpdate struct {
+	Bio       *string `json:"bio,omitempty" validate:"maxlen=500"`
+	Location  *string `json:"location,omitempty" validate:"maxlen=100"`
+	TwitterID *string `json:"twitterID,omitempty" validate:"twitterid"`
+}
+
+func (p *profileUpdate) normalize() {
+	if p.Bio != nil {
+		*p.Bio = strings.TrimSpace(*p.Bio)
+	}
+	if p.Location != nil {
+		*p.Location = strings.TrimSpace(*p.Location)
+	}
+	if p.TwitterID != nil {
+		*p.TwitterID = strings.TrimPrefix(strings.TrimSpace(*p.TwitterID), "@")
+	}
+}
+
+// profile of any user, subject to their privacy settings
+func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if _, ok := h.authorizeUserData(w, r, userID, scopeProfile); !ok {
+		return
+	}
+	h.respondWithProfile(w, r, userID)
+}
+
+// profile of the authenticated user
+func (h *Handler) GetMyProfile(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+	h.respondWithProfile(w, r, claims.UserID)
+}
+
+func (h *Handler) respondWithProfile(w http.ResponseWriter, r *http.Request, userID string) {
+	user, err := h.queries.GetUserByUserID(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", userID)))
+		return
+	}
+
+	utils.RespondWithJSON(w, 200, response.NewProfile(user))
+}
+
+// update bio, location and twitterID of the authenticated user
+// omitted fields are left unchanged, empty strings clear the field
+func (h *Handler) UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	var params profileUpdate
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+
+	user, err := h.queries.GetUserByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", claims.UserID)))
+		return
+	}
+
+	metadata, err := h.queries.GetUserMetadataByUserID(r.Context(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserMetadataByUserID: %w", err)))
+		return
+	}
+
+	// Update only the fields provided in the request
+	_, err = h.queries.UpdateUserMetadata(r.Context(), database.UpdateUserMetadataParams{
+		UserUuid:        user.ID,
+		Bio:             textOrKeep(params.Bio, metadata.Bio),
+		ProfileImageUrl: metadata.ProfileImageUrl,
+		Location:        textOrKeep(params.Location, metadata.Location),
+		TwitterID:       textOrKeep(params.TwitterID, metadata.TwitterID),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("UpdateUserMetadata: %w", err)))
+		return
+	}
+
+	h.respondWithProfile(w, r, claims.UserID)
+}
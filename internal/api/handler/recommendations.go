@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRecommendations = 20
+	maxRecommendations     = 50
+	// plays the achievement distribution per level is taken from
+	recentPlays = 500
+)
+
+// GetRecommendations lists the charts where reaching the next rank would
+// raise the user's rating the most, weighted by how often they reached
+// that achievement at a similar level recently.
+//
+//	?limit=20
+func (h *Handler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+
+	limit := defaultRecommendations
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxRecommendations {
+			utils.RespondWithError(w, utils.Invalid("limit", "must be an integer between 1 and %d", maxRecommendations))
+			return
+		}
+		limit = n
+	}
+
+	bests, err := h.queries.GetPersonalBestsByUserID(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetPersonalBestsByUserID: %w", err), ""))
+		return
+	}
+	plays, err := h.queries.GetRecentAchievementsByUserID(r.Context(), database.GetRecentAchievementsByUserIDParams{
+		UserID:   userID,
+		MaxPlays: recentPlays,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetRecentAchievementsByUserID: %w", err), ""))
+		return
+	}
+
+	charts, rows := ratedCharts(bests)
+	recent := make([]calculator.Play, 0, len(plays))
+	for _, p := range plays {
+		level, err := p.InternalLevel.Float64Value()
+		achievement, aerr := calculator.ParseAchievement(p.Accuracy)
+		if err != nil || aerr != nil {
+			continue
+		}
+		recent = append(recent, calculator.Play{InternalLevel: level.Float64, Achievement: achievement})
+	}
+
+	recs := calculator.Recommend(charts, recent)
+	out := response.Recommendations{
+		Rating:          calculator.NewFrame(charts).Total(),
+		Recommendations: make([]response.Recommendation, 0, min(limit, len(recs))),
+	}
+	for _, rec := range recs[:min(limit, len(recs))] {
+		out.Recommendations = append(out.Recommendations, response.NewRecommendation(rows[rec.Chart.BeatmapID], rec))
+	}
+	utils.RespondWithJSON(w, 200, out)
+}
+
+// ratedCharts turns personal bests into charts, leaving out beatmaps
+// without an internal level, and indexes the rows by beatmap.
+func ratedCharts(bests []database.GetPersonalBestsByUserIDRow) ([]calculator.Chart, map[uuid.UUID]database.GetPersonalBestsByUserIDRow) {
+	charts := make([]calculator.Chart, 0, len(bests))
+	rows := make(map[uuid.UUID]database.GetPersonalBestsByUserIDRow, len(bests))
+	for _, b := range bests {
+		level, err := b.InternalLevel.Float64Value()
+		if err != nil || !level.Valid {
+			continue
+		}
+		achievement, err := calculator.ParseAchievement(b.Accuracy)
+		if err != nil {
+			continue
+		}
+		charts = append(charts, calculator.Chart{
+			BeatmapID:     b.BeatmapID,
+			InternalLevel: level.Float64,
+			Achievement:   achievement,
+			IsNew:         b.IsNew,
+		})
+		rows[b.BeatmapID] = b
+	}
+	return charts, rows
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxRivals = 50
+
+// rivals of a user, subject to their profile privacy
+func (h *Handler) GetRivals(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeProfile); !ok {
+		return
+	}
+
+	rivals, err := h.queries.GetRivalsByUserID(r.Context(), database.GetRivalsByUserIDParams{
+		UserID:   userID,
+		ViewerID: viewerID(r),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetRivalsByUserID: %w", err)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewRivals(rivals))
+}
+
+// adds a rival to the signed in user
+func (h *Handler) AddMyRival(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+
+	var params struct {
+		UserID string `json:"userID" validate:"required,userid"`
+	}
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+	if params.UserID == claims.UserID {
+		utils.RespondWithError(w, utils.Invalid("userID", "must not be yourself"))
+		return
+	}
+
+	// users that hide their profile cannot be added
+	rival, ok := h.authorizeUserData(w, r, params.UserID, scopeProfile)
+	if !ok {
+		return
+	}
+	user, err := h.queries.GetUserByUserID(r.Context(), claims.UserID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserByUserID: %w", err)))
+		return
+	}
+
+	count, err := h.queries.CountRivalsByUserUUID(r.Context(), user.ID)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CountRivalsByUserUUID: %w", err)))
+		return
+	}
+	if count >= maxRivals {
+		utils.RespondWithError(w, utils.Conflict(utils.CodeConflict, "You already have %d rivals", maxRivals))
+		return
+	}
+
+	if _, err := h.queries.CreateRival(r.Context(), database.CreateRivalParams{
+		UserUuid:  user.ID,
+		RivalUuid: rival.UserUuid,
+	}); err != nil {
+		if utils.IsUniqueViolation(err) {
+			utils.RespondWithError(w, utils.Conflict(utils.CodeConflict, "'%s' is already your rival", params.UserID))
+			return
+		}
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("CreateRival: %w", err)))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// removes a rival of the signed in user
+func (h *Handler) RemoveMyRival(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, utils.Unauthorized())
+		return
+	}
+	rivalID := chi.URLParam(r, "userID")
+
+	deleted, err := h.queries.DeleteRival(r.Context(), database.DeleteRivalParams{
+		UserID:  claims.UserID,
+		RivalID: rivalID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("DeleteRival: %w", err)))
+		return
+	}
+	if deleted == 0 {
+		utils.RespondWithError(w, utils.NotFound("'%s' is not your rival", rivalID))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// CompareUsers lines up the personal bests of two users chart by chart.
+// Both users' scores must be visible to the caller.
+func (h *Handler) CompareUsers(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	rivalID := chi.URLParam(r, "rivalID")
+
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
+	}
+	if _, ok := h.authorizeUserData(w, r, rivalID, scopeScores); !ok {
+		return
+	}
+
+	userBests, err := h.queries.GetPersonalBestsByUserID(r.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetPersonalBestsByUserID: %w", err), ""))
+		return
+	}
+	rivalBests, err := h.queries.GetPersonalBestsByUserID(r.Context(), rivalID)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetPersonalBestsByUserID: %w", err), ""))
+		return
+	}
+
+	utils.RespondWithJSON(w, 200, headToHead(userID, rivalID, userBests, rivalBests))
+}
+
+func headToHead(userID, rivalID string, userBests, rivalBests []database.GetPersonalBestsByUserIDRow) response.HeadToHead {
+	charts := map[string]*response.ChartComparison{}
+	var rows []database.GetPersonalBestsByUserIDRow
+	for _, side := range []struct {
+		bests []database.GetPersonalBestsByUserIDRow
+		user  bool
+	}{{userBests, true}, {rivalBests, false}} {
+		for _, b := range side.bests {
+			key := b.BeatmapID.String()
+			c, ok := charts[key]
+			if !ok {
+				comparison := response.NewChartComparison(b)
+				c = &comparison
+				charts[key] = c
+				rows = append(rows, b)
+			}
+			if side.user {
+				c.User = response.NewPersonalBest(b)
+			} else {
+				c.Rival = response.NewPersonalBest(b)
+			}
+		}
+	}
+
+	out := response.HeadToHead{
+		UserID:       userID,
+		RivalID:      rivalID,
+		ByDifficulty: []response.HeadToHeadGroup{},
+		ByLevel:      []response.HeadToHeadGroup{},
+		Charts:       make([]response.ChartComparison, 0, len(rows)),
+	}
+	byDifficulty := map[string]*response.HeadToHeadCounts{}
+	byLevel := map[string]*response.HeadToHeadCounts{}
+	group := func(groups map[string]*response.HeadToHeadCounts, key string) *response.HeadToHeadCounts {
+		if groups[key] == nil {
+			groups[key] = &response.HeadToHeadCounts{}
+		}
+		return groups[key]
+	}
+
+	for _, row := range rows {
+		c := charts[row.BeatmapID.String()]
+		counts := []*response.HeadToHeadCounts{&out.Summary, group(byDifficulty, c.Difficulty), group(byLevel, c.Level)}
+		switch {
+		case c.Rival == nil:
+			for _, n := range counts {
+				n.OnlyUser++
+			}
+		case c.User == nil:
+			for _, n := range counts {
+				n.OnlyRival++
+			}
+		default:
+			leader := compareBests(c.User, c.Rival)
+			c.Leader = &leader
+			user, _ := calculator.ParseAchievement(c.User.Accuracy)
+			rival, _ := calculator.ParseAchievement(c.Rival.Accuracy)
+			// achievements have 4 decimals, drop the float noise
+			margin := math.Round((user-rival)*10000) / 10000
+			c.Margin = &margin
+			for _, n := range counts {
+				switch leader {
+				case "user":
+					n.Wins++
+				case "rival":
+					n.Losses++
+				default:
+					n.Ties++
+				}
+			}
+		}
+		out.Charts = append(out.Charts, *c)
+	}
+
+	slices.SortStableFunc(out.Charts, func(a, b response.ChartComparison) int {
+		return cmp.Or(
+			cmp.Compare(levelValue(b.Level, b.InternalLevel), levelValue(a.Level, a.InternalLevel)),
+			strings.Compare(a.Title, b.Title),
+			cmp.Compare(slices.Index(difficulties, a.Difficulty), slices.Index(difficulties, b.Difficulty)),
+		)
+	})
+	for _, d := range difficulties {
+		if n, ok := byDifficulty[d]; ok {
+			out.ByDifficulty = append(out.ByDifficulty, response.HeadToHeadGroup{Group: d, HeadToHeadCounts: *n})
+		}
+	}
+	levels := make([]string, 0, len(byLevel))
+	for l := range byLevel {
+		levels = append(levels, l)
+	}
+	slices.SortFunc(levels, func(a, b string) int {
+		return cmp.Compare(levelValue(b, nil), levelValue(a, nil))
+	})
+	for _, l := range levels {
+		out.ByLevel = append(out.ByLevel, response.HeadToHeadGroup{Group: l, HeadToHeadCounts: *byLevel[l]})
+	}
+	return out
+}
+
+// the better achievement leads, then the better DX score
+func compareBests(user, rival *response.PersonalBest) string {
+	u, _ := calculator.ParseAchievement(user.Accuracy)
+	r, _ := calculator.ParseAchievement(rival.Accuracy)
+	switch c := cmp.Or(cmp.Compare(u, r), cmp.Compare(user.DxScore, rival.DxScore)); {
+	case c > 0:
+		return "user"
+	case c < 0:
+		return "rival"
+	}
+	return "tie"
+}
+
+// orders levels like "13+" between 13 and 14, by internal level when known
+func levelValue(level string, internalLevel *float64) float64 {
+	if internalLevel != nil {
+		return *internalLevel
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(level, "+"), 64)
+	if err != nil {
+		return 0
+	}
+	if strings.HasSuffix(level, "+") {
+		v += 0.5
+	}
+	return v
+}
@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"strconv"
 
+	"github.com/asashakira/maitrack/internal/api/response"
+	"github.com/asashakira/maitrack/internal/calculator"
 	database "github.com/asashakira/maitrack/internal/database/sqlc"
 	"github.com/asashakira/maitrack/internal/utils"
 	"github.com/go-chi/chi/v5"
@@ -18,66 +17,57 @@ import (
 
 func (h *Handler) CreateScore(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		BeatmapID     string `json:"beatmapID"`
-		SongID        string `json:"songID"`
-		UserUuid      string `json:"userUuid"`
-		Accuracy      string `json:"accuracy"`
-		MaxCombo      int32  `json:"maxCombo"`
-		DxScore       int32  `json:"dxScore"`
-		TapCritical   int32  `json:"tapCritical"`
-		TapPerfect    int32  `json:"tapPerfect"`
-		TapGreat      int32  `json:"tapGreat"`
-		TapGood       int32  `json:"tapGood"`
-		TapMiss       int32  `json:"tapMiss"`
-		HoldCritical  int32  `json:"holdCritical"`
-		HoldPerfect   int32  `json:"holdPerfect"`
-		HoldGreat     int32  `json:"holdGreat"`
-		HoldGood      int32  `json:"holdGood"`
-		HoldMiss      int32  `json:"holdMiss"`
-		SlideCritical int32  `json:"slideCritical"`
-		SlidePerfect  int32  `json:"slidePerfect"`
-		SlideGreat    int32  `json:"slideGreat"`
-		SlideGood     int32  `json:"slideGood"`
-		SlideMiss     int32  `json:"slideMiss"`
-		TouchCritical int32  `json:"touchCritical"`
-		TouchPerfect  int32  `json:"touchPerfect"`
-		TouchGreat    int32  `json:"touchGreat"`
-		TouchGood     int32  `json:"touchGood"`
-		TouchMiss     int32  `json:"touchMiss"`
-		BreakCritical int32  `json:"breakCritical"`
-		BreakPerfect  int32  `json:"breakPerfect"`
-		BreakGreat    int32  `json:"breakGreat"`
-		BreakGood     int32  `json:"breakGood"`
-		BreakMiss     int32  `json:"breakMiss"`
-		Fast          int32  `json:"fast"`
-		Late          int32  `json:"late"`
-		PlayedAt      string `json:"playedAt"`
+		BeatmapID     string `json:"beatmapID" validate:"required,uuid"`
+		SongID        string `json:"songID" validate:"required,uuid"`
+		UserUuid      string `json:"userUuid" validate:"required,uuid"`
+		Accuracy      string `json:"accuracy" validate:"required,accuracy"`
+		MaxCombo      int32  `json:"maxCombo" validate:"min=0"`
+		DxScore       int32  `json:"dxScore" validate:"min=0"`
+		TapCritical   int32  `json:"tapCritical" validate:"min=0"`
+		TapPerfect    int32  `json:"tapPerfect" validate:"min=0"`
+		TapGreat      int32  `json:"tapGreat" validate:"min=0"`
+		TapGood       int32  `json:"tapGood" validate:"min=0"`
+		TapMiss       int32  `json:"tapMiss" validate:"min=0"`
+		HoldCritical  int32  `json:"holdCritical" validate:"min=0"`
+		HoldPerfect   int32  `json:"holdPerfect" validate:"min=0"`
+		HoldGreat     int32  `json:"holdGreat" validate:"min=0"`
+		HoldGood      int32  `json:"holdGood" validate:"min=0"`
+		HoldMiss      int32  `json:"holdMiss" validate:"min=0"`
+		SlideCritical int32  `json:"slideCritical" validate:"min=0"`
+		SlidePerfect  int32  `json:"slidePerfect" validate:"min=0"`
+		SlideGreat    int32  `json:"slideGreat" validate:"min=0"`
+		SlideGood     int32  `json:"slideGood" validate:"min=0"`
+		SlideMiss     int32  `json:"slideMiss" validate:"min=0"`
+		TouchCritical int32  `json:"touchCritical" validate:"min=0"`
+		TouchPerfect  int32  `json:"touchPerfect" validate:"min=0"`
+		TouchGreat    int32  `json:"touchGreat" validate:"min=0"`
+		TouchGood     int32  `json:"touchGood" validate:"min=0"`
+		TouchMiss     int32  `json:"touchMiss" validate:"min=0"`
+		BreakCritical int32  `json:"breakCritical" validate:"min=0"`
+		BreakPerfect  int32  `json:"breakPerfect" validate:"min=0"`
+		BreakGreat    int32  `json:"breakGreat" validate:"min=0"`
+		BreakGood     int32  `json:"breakGood" validate:"min=0"`
+		BreakMiss     int32  `json:"breakMiss" validate:"min=0"`
+		Fast          int32  `json:"fast" validate:"min=0"`
+		Late          int32  `json:"late" validate:"min=0"`
+		PlayedAt      string `json:"playedAt" validate:"required,jsttime"`
 	}
 
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err := decoder.Decode(&params)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing JSON: %v", err))
+	var params parameters
+	if !decodeJSON(w, r, &params) {
 		return
 	}
 
-	if params.BeatmapID == "" || params.SongID == "" || params.UserUuid == "" {
-		utils.RespondWithError(w, 400, "BeatmapID, SongID, and UserID are required")
-		return
-	}
-
-	playedAt, err := utils.StringToUTCTime(params.PlayedAt)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing played at date: %v", err))
-		return
-	}
+	// validated above, parsing cannot fail
+	beatmapID, _ := uuid.Parse(params.BeatmapID)
+	songID, _ := uuid.Parse(params.SongID)
+	userUuid, _ := uuid.Parse(params.UserUuid)
 
-	score, err := h.queries.CreateScore(r.Context(), database.CreateScoreParams{
+	arg := database.CreateScoreParams{
 		ID:            uuid.New(),
-		BeatmapID:     uuid.MustParse(params.BeatmapID),
-		SongID:        uuid.MustParse(params.SongID),
-		UserUuid:      uuid.MustParse(params.UserUuid),
+		BeatmapID:     beatmapID,
+		SongID:        songID,
+		UserUuid:      userUuid,
 		Accuracy:      params.Accuracy,
 		MaxCombo:      params.MaxCombo,
 		DxScore:       params.DxScore,
@@ -108,64 +98,122 @@ func (h *Handler) CreateScore(w http.ResponseWriter, r *http.Request) {
 		BreakMiss:     params.BreakMiss,
 		Fast:          params.Fast,
 		Late:          params.Late,
-		PlayedAt:      pgtype.Timestamp{Time: playedAt, Valid: true},
-	})
+		PlayedAt:      pgtype.Timestamp{Time: utils.JSTTime(params.PlayedAt), Valid: true},
+	}
+
+	// reject scores that could not have been played on this beatmap
+	beatmap, err := h.queries.GetBeatmapByBeatmapID(r.Context(), beatmapID)
 	if err != nil {
-		errorMessage := fmt.Sprintf("CreateScore %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.RespondWithError(w, utils.Invalid("beatmapID", "refers to a resource that does not exist"))
+			return
+		}
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetBeatmapByBeatmapID: %w", err)))
 		return
 	}
-	utils.RespondWithJSON(w, 200, score)
+	var invalid []utils.FieldError
+	if beatmap.SongID != songID {
+		invalid = append(invalid, utils.FieldError{Field: "songID", Message: "is not the song of the beatmap"})
+	}
+	problems := calculator.Check(calculator.Score{
+		Judgements: scoreParamsJudgements(arg),
+		Accuracy:   arg.Accuracy,
+		MaxCombo:   arg.MaxCombo,
+		DxScore:    arg.DxScore,
+	}, calculator.Notes{Tap: beatmap.Tap, Hold: beatmap.Hold, Slide: beatmap.Slide, Touch: beatmap.Touch, Break: beatmap.Break})
+	for _, p := range problems {
+		invalid = append(invalid, utils.FieldError{Field: p.Field, Message: p.Message})
+	}
+	if len(invalid) > 0 {
+		utils.RespondWithError(w, utils.ValidationFailed(invalid...))
+		return
+	}
+
+	score, err := h.queries.CreateScore(r.Context(), arg)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("CreateScore: %w", err), ""))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewScore(score).WithDxStars(beatmap.MaxDxScore))
+}
+
+func scoreParamsJudgements(s database.CreateScoreParams) calculator.Judgements {
+	return calculator.Judgements{
+		Tap:   calculator.Judgement{Critical: s.TapCritical, Perfect: s.TapPerfect, Great: s.TapGreat, Good: s.TapGood, Miss: s.TapMiss},
+		Hold:  calculator.Judgement{Critical: s.HoldCritical, Perfect: s.HoldPerfect, Great: s.HoldGreat, Good: s.HoldGood, Miss: s.HoldMiss},
+		Slide: calculator.Judgement{Critical: s.SlideCritical, Perfect: s.SlidePerfect, Great: s.SlideGreat, Good: s.SlideGood, Miss: s.SlideMiss},
+		Touch: calculator.Judgement{Critical: s.TouchCritical, Perfect: s.TouchPerfect, Great: s.TouchGreat, Good: s.TouchGood, Miss: s.TouchMiss},
+		Break: calculator.Judgement{Critical: s.BreakCritical, Perfect: s.BreakPerfect, Great: s.BreakGreat, Good: s.BreakGood, Miss: s.BreakMiss},
+	}
 }
 
 type ScoresResponse struct {
-	Scores     []database.GetScoresByUserIDRow `json:"scores"`
-	NextOffset int                             `json:"nextOffset,omitempty"`
-	HasMore    bool                            `json:"hasMore"`
+	Scores     []response.Score `json:"scores"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	HasMore    bool             `json:"hasMore"`
 }
 
 // gets score by maiID (gameName + tagLine)
+//
+//	?difficulty=&type=&version=&levelMin=&levelMax=&playedFrom=&playedTo=
+//	&achievementMin=&achievementMax=&sort=[-]playedAt|achievement|level|dxScore
+//	&limit=&cursor=
+//
+// playedFrom and playedTo are JST dates, both inclusive.
 func (h *Handler) GetScoresByUserID(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 
-	limit, limitErr := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limitErr != nil {
-		limit = 10
-	}
-	offset, offsetErr := strconv.Atoi(r.URL.Query().Get("offset"))
-	if offsetErr != nil {
-		offset = 0
+	if _, ok := h.authorizeUserData(w, r, userID, scopeScores); !ok {
+		return
 	}
 
-	scores, err := h.queries.GetScoresByUserID(r.Context(), database.GetScoresByUserIDParams{
-		UserID: userID,
-		Limit:  int32(limit),
-		Offset: int32(offset),
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"playedAt": true, "achievement": true, "level": true, "dxScore": true},
+		defaultSort:  "playedAt",
+		defaultLimit: 10,
 	})
-	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No score found with provided UserID '%s': %s", userID, err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
-			return
-		}
-		// Handle other errors
-		errorMessage := fmt.Sprintf("GetScoresByScoreID %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+	levelMin, levelMax := q.numericRange("level", 0, 15)
+	playedFrom, playedTo := q.dateRange("played")
+	achievementMin, achievementMax := q.numericRange("achievement", 0, 101)
+	params := database.GetScoresByUserIDParams{
+		Sort:           q.sort,
+		UserID:         userID,
+		Difficulty:     q.text("difficulty", difficulties...),
+		Type:           q.text("type", beatmapTypes...),
+		Version:        q.text("version"),
+		LevelMin:       levelMin,
+		LevelMax:       levelMax,
+		PlayedFrom:     playedFrom,
+		PlayedTo:       playedTo,
+		AchievementMin: achievementMin,
+		AchievementMax: achievementMax,
+		CursorValue:    q.cursorValue(),
+		Descending:     q.descending,
+		CursorNumber:   q.cursorNumber("achievement", "level", "dxScore"),
+		CursorID:       q.cursorID(),
+		CursorTime:     q.cursorTime("playedAt"),
+		PageSize:       q.pageSize(),
+	}
+	if q.respondIfInvalid(w) {
 		return
 	}
 
-	nextOffset := offset + limit
-	hasMore := len(scores) == limit
-
-	response := ScoresResponse{
-		Scores:     scores,
-		NextOffset: nextOffset,
-		HasMore:    hasMore,
+	scores, err := h.queries.GetScoresByUserID(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetScoresByUserID: %w", err), ""))
+		return
 	}
 
-	utils.RespondWithJSON(w, 200, response)
+	scores, next := page(q, scores, func(s database.GetScoresByUserIDRow) (string, uuid.UUID) {
+		if s.SortTime.Valid {
+			return timeKey(s.SortTime), s.ID
+		}
+		return numberKey(s.SortNumber), s.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, ScoresResponse{
+		Scores:     response.NewScoresWithBeatmap(scores),
+		NextCursor: next,
+		HasMore:    next != "",
+	})
 }
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type SessionsResponse struct {
+	Sessions   []response.Session `json:"sessions"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+	HasMore    bool               `json:"hasMore"`
+}
+
+// lists a user's sessions, latest first by default
+//
+//	?startedFrom=&startedTo=&sort=[-]startedAt&limit=&cursor=
+//
+// startedFrom and startedTo are JST dates, both inclusive.
+func (h *Handler) GetSessionsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	privacy, ok := h.authorizeUserData(w, r, userID, scopeScores)
+	if !ok {
+		return
+	}
+	showRatings, err := h.canViewScope(r, privacy, scopeRatingHistory)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("canViewScope: %w", err)))
+		return
+	}
+
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"startedAt": true},
+		defaultSort:  "startedAt",
+		defaultLimit: 20,
+	})
+	startedFrom, startedTo := q.dateRange("started")
+	params := database.GetSessionsByUserIDParams{
+		UserID:      userID,
+		StartedFrom: startedFrom,
+		StartedTo:   startedTo,
+		CursorTime:  q.cursorTime("startedAt"),
+		Descending:  q.descending,
+		CursorID:    q.cursorID(),
+		PageSize:    q.pageSize(),
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	sessions, err := h.queries.GetSessionsByUserID(r.Context(), params)
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetSessionsByUserID: %w", err), ""))
+		return
+	}
+
+	sessions, next := page(q, sessions, func(s database.Session) (string, uuid.UUID) {
+		return timeKey(s.StartedAt), s.ID
+	})
+	out := response.NewSessions(sessions)
+	if !showRatings {
+		for i := range out {
+			out[i] = out[i].WithoutRatings()
+		}
+	}
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, SessionsResponse{
+		Sessions:   out,
+		NextCursor: next,
+		HasMore:    next != "",
+	})
+}
+
+// returns a session of the user with all its plays
+func (h *Handler) GetSession(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	sessionID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("sessionID", "must be a UUID"))
+		return
+	}
+
+	privacy, ok := h.authorizeUserData(w, r, userID, scopeScores)
+	if !ok {
+		return
+	}
+	showRatings, err := h.canViewScope(r, privacy, scopeRatingHistory)
+	if err != nil {
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("canViewScope: %w", err)))
+		return
+	}
+
+	session, err := h.queries.GetSessionByUserID(r.Context(), database.GetSessionByUserIDParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetSessionByUserID: %w", err), fmt.Sprintf("No session found with id '%s'", sessionID)))
+		return
+	}
+
+	// a session is far shorter than a page
+	scores, err := h.queries.GetScoresByUserID(r.Context(), database.GetScoresByUserIDParams{
+		Sort:      "playedAt",
+		UserID:    userID,
+		SessionID: pgtype.UUID{Bytes: sessionID, Valid: true},
+		PageSize:  maxPageSize,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetScoresByUserID: %w", err), ""))
+		return
+	}
+
+	detail := response.SessionDetail{
+		Session: response.NewSession(session),
+		Scores:  response.NewScoresWithBeatmap(scores),
+	}
+	if !showRatings {
+		detail.Session = detail.Session.WithoutRatings()
+	}
+	utils.RespondWithJSON(w, 200, detail)
+}
@@ -1,114 +1,122 @@
 package handler
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/asashakira/maitrack/internal/api/response"
 	database "github.com/asashakira/maitrack/internal/database/sqlc"
 	"github.com/asashakira/maitrack/internal/utils"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (h *Handler) CreateSong(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		Title       string `json:"title"`
-		Artist      string `json:"artist"`
-		Genre       string `json:"genre"`
+		Title       string `json:"title" validate:"required"`
+		TitleKana   string `json:"titleKana"`
+		Artist      string `json:"artist" validate:"required"`
+		ArtistKana  string `json:"artistKana"`
+		Genre       string `json:"genre" validate:"required"`
 		Bpm         string `json:"bpm"`
 		ImageUrl    string `json:"imageUrl"`
-		Version     string `json:"version"`
+		Version     string `json:"version" validate:"required"`
 		IsUtage     bool   `json:"isUtage"`
 		IsAvailable bool   `json:"isAvailable"`
-		ReleaseDate string `json:"releaseDate"`
-		DeleteDate  string `json:"deleteDate,omitempty"`
+		ReleaseDate string `json:"releaseDate" validate:"required,jsttime"`
+		DeleteDate  string `json:"deleteDate,omitempty" validate:"jsttime"`
 	}
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err := decoder.Decode(&params)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing JSON: %v", err))
-		return
-	}
-
-	releaseDate, err := utils.StringToUTCTime(params.ReleaseDate)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing release date: %v", err))
+	var params parameters
+	if !decodeJSON(w, r, &params) {
 		return
 	}
 
 	var deleteDate pgtype.Date
 	if params.DeleteDate != "" {
-		parsedDate, err := utils.StringToUTCTime(params.DeleteDate)
-		deleteDate.Scan(parsedDate)
-		if err != nil {
-			utils.RespondWithError(w, 400, fmt.Sprintf("error parsing delete date: %v", err))
-			return
-		}
+		deleteDate = pgtype.Date{Time: utils.JSTTime(params.DeleteDate), Valid: true}
 	}
 
+	// readings are romanized as given, folding drops the dakuten and small
+	// kana romaji needs. Only the title reading is stored, for search.
 	song, err := h.queries.CreateSong(r.Context(), database.CreateSongParams{
-		ID:          uuid.New(),
-		Title:       params.Title,
-		Artist:      params.Artist,
-		Genre:       params.Genre,
-		Bpm:         params.Bpm,
-		ImageUrl:    params.ImageUrl,
-		Version:     params.Version,
-		IsUtage:     params.IsUtage,
-		IsAvailable: params.IsAvailable,
-		ReleaseDate: pgtype.Date{Time: releaseDate, Valid: true},
-		DeleteDate:  deleteDate,
+		ID:           uuid.New(),
+		Title:        params.Title,
+		TitleKana:    utils.NormalizeKana(params.TitleKana),
+		TitleRomaji:  utils.RomanizeWithReading(params.Title, params.TitleKana),
+		Artist:       params.Artist,
+		ArtistRomaji: utils.RomanizeWithReading(params.Artist, params.ArtistKana),
+		Genre:        params.Genre,
+		Bpm:          params.Bpm,
+		ImageUrl:     params.ImageUrl,
+		Version:      params.Version,
+		IsUtage:      params.IsUtage,
+		IsAvailable:  params.IsAvailable,
+		ReleaseDate:  pgtype.Date{Time: utils.JSTTime(params.ReleaseDate), Valid: true},
+		DeleteDate:   deleteDate,
 	})
 	if err != nil {
-		errorMessage := fmt.Sprintf("CreateSong %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("CreateSong: %w", err), ""))
 		return
 	}
-	utils.RespondWithJSON(w, 200, song)
+	utils.RespondWithJSON(w, 200, response.NewSong(song))
 }
 
+// GetAllSongs lists songs newest first.
+//
+//	?version=&genre=&sort=[-]releaseDate|title|romaji|english|artist&limit=&cursor=
+//
+// romaji sorts by the romanized title and english by the English title,
+// both falling back to the title.
 func (h *Handler) GetAllSongs(w http.ResponseWriter, r *http.Request) {
-	songs, err := h.queries.GetAllSongs(r.Context())
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"releaseDate": true, "title": false, "romaji": false, "english": false, "artist": false},
+		defaultSort:  "releaseDate",
+		defaultLimit: 100,
+	})
+	params := database.ListSongsParams{
+		Sort:        q.sort,
+		Version:     q.text("version"),
+		Genre:       q.text("genre"),
+		CursorValue: q.cursorValue(),
+		Descending:  q.descending,
+		CursorID:    q.cursorID(),
+		PageSize:    q.pageSize(),
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	songs, err := h.queries.ListSongs(r.Context(), params)
 	if err != nil {
-		errorMessage := fmt.Sprintf("GetAllSongs %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("ListSongs: %w", err), ""))
 		return
 	}
-	utils.RespondWithJSON(w, 200, songs)
+
+	songs, next := page(q, songs, func(s database.ListSongsRow) (string, uuid.UUID) {
+		return s.SortValue, s.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, response.NewSongsWithBeatmaps(songs))
 }
 
 func (h *Handler) GetSongByID(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing songID: %s", err))
+		utils.RespondWithError(w, utils.Invalid("id", "must be a UUID"))
 		return
 	}
 
 	song, err := h.queries.GetSongByID(r.Context(), id)
 	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No song found with provided id '%s': %s", id, err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
-			return
-		}
-		// Handle other errors
-		errorMessage := fmt.Sprintf("GetSongByID %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetSongByID: %w", err), fmt.Sprintf("No song found with provided id '%s'", id)))
 		return
 	}
-	utils.RespondWithJSON(w, 200, song)
+	utils.RespondWithJSON(w, 200, response.NewSongWithBeatmaps(song))
 }
 
 // get song using altkey
@@ -120,26 +128,16 @@ func (h *Handler) GetSongByAltKey(w http.ResponseWriter, r *http.Request) {
 	altkey := chi.URLParam(r, "altkey")
 	altkey, err := url.QueryUnescape(altkey)
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error decoding altkey from url: %v", err))
+		utils.RespondWithError(w, utils.Invalid("altkey", "must be URL encoded"))
 		return
 	}
 
 	song, err := h.queries.GetSongByAltKey(r.Context(), altkey)
 	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No song found with provided altkey '%s': %s", altkey, err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
-			return
-		}
-		// Handle other errors
-		errorMessage := fmt.Sprintf("GetSongByAltKey %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetSongByAltKey: %w", err), fmt.Sprintf("No song found with provided altkey '%s'", altkey)))
 		return
 	}
-	utils.RespondWithJSON(w, 200, song)
+	utils.RespondWithJSON(w, 200, response.NewSong(song))
 }
 
 // return array of songs that matches title
@@ -147,100 +145,175 @@ func (h *Handler) GetSongsByTitle(w http.ResponseWriter, r *http.Request) {
 	title := chi.URLParam(r, "title")
 	title, err := url.QueryUnescape(title)
 	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error decoding title from url: %v", err))
+		utils.RespondWithError(w, utils.Invalid("title", "must be URL encoded"))
 		return
 	}
 
 	songs, err := h.queries.GetSongsByTitle(r.Context(), title)
 	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No song found with provided title '%s': %s", title, err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetSongByTitle: %w", err), fmt.Sprintf("No song found with provided title '%s'", title)))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewSongs(songs))
+}
+
+// search limits
+const (
+	maxSearchQueryLength = 100
+	defaultSearchResults = 20
+	maxSearchResults     = 50
+)
+
+// SearchSongs finds songs by title, kana reading, artist or alias,
+// best matches first.
+//
+//	?q=oshama&limit=20
+func (h *Handler) SearchSongs(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		utils.RespondWithError(w, utils.Invalid("q", "must be 1-%d characters", maxSearchQueryLength))
+		return
+	}
+
+	limit := defaultSearchResults
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchResults {
+			utils.RespondWithError(w, utils.Invalid("limit", "must be an integer between 1 and %d", maxSearchResults))
 			return
 		}
-		// Handle other errors
-		errorMessage := fmt.Sprintf("GetSongByTitle %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		limit = n
+	}
+
+	songs, err := h.queries.SearchSongs(r.Context(), database.SearchSongsParams{
+		Query:        query,
+		QueryPattern: likeEscaper.Replace(query),
+		KanaQuery:    utils.NormalizeKana(query),
+		MaxResults:   int32(limit),
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("SearchSongs: %w", err), ""))
 		return
 	}
-	utils.RespondWithJSON(w, 200, songs)
+	utils.RespondWithJSON(w, 200, response.NewSongSearchResults(songs))
 }
 
+// escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func (h *Handler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		SongID      uuid.UUID `json:"songID"`
+		SongID      uuid.UUID `json:"songID" validate:"required"`
 		Title       *string   `json:"title,omitempty"`
+		TitleKana   *string   `json:"titleKana,omitempty"`
 		Artist      *string   `json:"artist,omitempty"`
+		ArtistKana  *string   `json:"artistKana,omitempty"`
 		Genre       *string   `json:"genre,omitempty"`
 		Bpm         *string   `json:"bpm,omitempty"`
 		ImageUrl    *string   `json:"imageUrl,omitempty"`
 		Version     *string   `json:"version,omitempty"`
 		IsUtage     *bool     `json:"isUtage,omitempty"`
 		IsAvailable *bool     `json:"isAvailable,omitempty"`
-		ReleaseDate string    `json:"releaseDate,omitempty"`
-		DeleteDate  string    `json:"deleteDate,omitempty"`
+		ReleaseDate string    `json:"releaseDate,omitempty" validate:"jsttime"`
+		DeleteDate  string    `json:"deleteDate,omitempty" validate:"jsttime"`
 	}
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err := decoder.Decode(&params)
-	if err != nil {
-		utils.RespondWithError(w, 400, fmt.Sprintf("error parsing JSON: %v", err))
+	var params parameters
+	if !decodeJSON(w, r, &params) {
 		return
 	}
 
 	// Fetch existing song
 	song, err := h.queries.GetSongByID(r.Context(), params.SongID)
 	if err != nil {
-		errorMessage := fmt.Sprintf("song not found %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetSongByID: %w", err), fmt.Sprintf("No song found with provided songID '%s'", params.SongID)))
 		return
 	}
 
 	// update release date if provided
 	releaseDate := song.ReleaseDate
 	if params.ReleaseDate != "" {
-		parsedDate, err := utils.StringToUTCTime(params.ReleaseDate)
-		releaseDate.Scan(parsedDate)
-		if err != nil {
-			utils.RespondWithError(w, 400, fmt.Sprintf("error parsing release date: %v", err))
-			return
-		}
+		releaseDate = pgtype.Date{Time: utils.JSTTime(params.ReleaseDate), Valid: true}
 	}
 
 	// update delete date if provided
 	deleteDate := song.DeleteDate
 	if params.DeleteDate != "" {
-		parsedDate, err := utils.StringToUTCTime(params.DeleteDate)
-		deleteDate.Scan(parsedDate)
-		if err != nil {
-			utils.RespondWithError(w, 400, fmt.Sprintf("error parsing delete date: %v", err))
-			return
-		}
+		deleteDate = pgtype.Date{Time: utils.JSTTime(params.DeleteDate), Valid: true}
+	}
+
+	// romaji is kept unless the text or its reading changes. Readings are
+	// romanized as given, the stored title reading is folded for search.
+	title := ifNotNil(params.Title, song.Title)
+	titleKana, titleRomaji := song.TitleKana, song.TitleRomaji
+	if params.TitleKana != nil {
+		titleKana = utils.NormalizeKana(*params.TitleKana)
+		titleRomaji = utils.RomanizeWithReading(title, *params.TitleKana)
+	} else if params.Title != nil {
+		titleRomaji = utils.RomanizeWithReading(title, "")
+	}
+	artist := ifNotNil(params.Artist, song.Artist)
+	artistRomaji := song.ArtistRomaji
+	if params.Artist != nil || params.ArtistKana != nil {
+		artistRomaji = utils.RomanizeWithReading(artist, ifNotNil(params.ArtistKana, ""))
 	}
 
 	// Update only the fields provided in the request
 	updatedSong, err := h.queries.UpdateSong(r.Context(), database.UpdateSongParams{
-		ID:          song.ID,
-		Title:       ifNotNil(params.Title, song.Title),
-		Artist:      ifNotNil(params.Artist, song.Artist),
-		Genre:       ifNotNil(params.Genre, song.Genre),
-		Bpm:         ifNotNil(params.Bpm, song.Bpm),
-		ImageUrl:    ifNotNil(params.ImageUrl, song.ImageUrl),
-		Version:     ifNotNil(params.Version, song.Version),
-		IsUtage:     ifNotNil(params.IsUtage, song.IsUtage),
-		IsAvailable: ifNotNil(params.IsAvailable, song.IsAvailable),
-		ReleaseDate: releaseDate,
-		DeleteDate:  deleteDate,
+		ID:           song.ID,
+		Title:        title,
+		TitleRomaji:  titleRomaji,
+		TitleEnglish: song.TitleEnglish,
+		Artist:       artist,
+		ArtistRomaji: artistRomaji,
+		Genre:        ifNotNil(params.Genre, song.Genre),
+		Bpm:          ifNotNil(params.Bpm, song.Bpm),
+		ImageUrl:     ifNotNil(params.ImageUrl, song.ImageUrl),
+		Version:      ifNotNil(params.Version, song.Version),
+		IsUtage:      ifNotNil(params.IsUtage, song.IsUtage),
+		IsAvailable:  ifNotNil(params.IsAvailable, song.IsAvailable),
+		TitleKana:    titleKana,
+		ReleaseDate:  releaseDate,
+		DeleteDate:   deleteDate,
+	})
+	if err != nil {
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("UpdateSong: %w", err), ""))
+		return
+	}
+	utils.RespondWithJSON(w, 200, response.NewSong(updatedSong))
+}
+
+// body of UpdateSongTitleEnglish
+type titleEnglishUpdate struct {
+	TitleEnglish string `json:"titleEnglish" validate:"maxlen=200"`
+}
+
+func (p *titleEnglishUpdate) normalize() {
+	p.TitleEnglish = strings.TrimSpace(p.TitleEnglish)
+}
+
+// sets or clears the English title of a song, curators only
+func (h *Handler) UpdateSongTitleEnglish(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, utils.Invalid("id", "must be a UUID"))
+		return
+	}
+
+	var params titleEnglishUpdate
+	if !decodeJSONStrict(w, r, &params) {
+		return
+	}
+
+	// an empty title clears the override
+	titleEnglish := params.TitleEnglish
+
+	song, err := h.queries.UpdateSongTitleEnglish(r.Context(), database.UpdateSongTitleEnglishParams{
+		ID:           id,
+		TitleEnglish: pgtype.Text{String: titleEnglish, Valid: titleEnglish != ""},
 	})
 	if err != nil {
-		errorMessage := fmt.Sprintf("UpdateSong %v", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("UpdateSongTitleEnglish: %w", err), fmt.Sprintf("No song found with provided id '%s'", id)))
 		return
 	}
-	utils.RespondWithJSON(w, 200, updatedSong)
+	utils.RespondWithJSON(w, 200, response.NewSong(song))
 }
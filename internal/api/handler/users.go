@@ -1,48 +1,74 @@
 package handler
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
 
+	"github.com/asashakira/maitrack/internal/api/response"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
 	"github.com/asashakira/maitrack/internal/scraper"
 	"github.com/asashakira/maitrack/internal/utils"
 	"github.com/asashakira/maitrack/pkg/maimaiclient"
 	"github.com/go-chi/chi/v5"
-	"github.com/jackc/pgx/v5"
+	"github.com/google/uuid"
 )
 
 func (h *Handler) GetUserByUserID(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 
+	if _, ok := h.authorizeUserData(w, r, userID, scopeProfile); !ok {
+		return
+	}
+
 	user, err := h.queries.GetUserByUserID(r.Context(), userID)
 	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No user found with provided fields: %s", err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
-			return
-		}
-		errorMessage := fmt.Sprintf("GetUserByUserID %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", userID)))
 		return
 	}
 
-	utils.RespondWithJSON(w, 200, user)
+	utils.RespondWithJSON(w, 200, response.NewUser(user))
 }
 
+// GetAllUsers lists users with a public profile.
+//
+//	?sort=[-]userID|rating|lastPlayedAt&limit=&cursor=
 func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.queries.GetAllUsers(r.Context())
+	q := newListQuery(r, listOptions{
+		sorts:        map[string]bool{"userID": false, "rating": true, "lastPlayedAt": true},
+		defaultSort:  "userID",
+		defaultLimit: 100,
+	})
+	params := database.ListUsersParams{
+		Sort:         q.sort,
+		CursorValue:  q.cursorValue(),
+		Descending:   q.descending,
+		CursorNumber: q.cursorNumber("rating"),
+		CursorID:     q.cursorID(),
+		CursorTime:   q.cursorTime("lastPlayedAt"),
+		PageSize:     q.pageSize(),
+	}
+	if q.respondIfInvalid(w) {
+		return
+	}
+
+	users, err := h.queries.ListUsers(r.Context(), params)
 	if err != nil {
-		errorMessage := fmt.Sprintf("GetAllUsers %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("ListUsers: %w", err), ""))
 		return
 	}
-	utils.RespondWithJSON(w, 200, users)
+
+	users, next := page(q, users, func(u database.ListUsersRow) (string, uuid.UUID) {
+		switch {
+		case u.SortNumber.Valid:
+			return numberKey(u.SortNumber), u.ID
+		case u.SortTime.Valid:
+			return timeKey(u.SortTime), u.ID
+		}
+		return u.SortValue.String, u.ID
+	})
+	setNextLink(w, r, next)
+	utils.RespondWithJSON(w, 200, response.NewUserSummaries(users))
 }
 
 func (h *Handler) GetUserHealthCheck(w http.ResponseWriter, r *http.Request) {
@@ -55,44 +81,31 @@ func (h *Handler) UpdateUserByUserID(w http.ResponseWriter, r *http.Request) {
 	// get user data
 	user, err := h.queries.GetUserByUserID(r.Context(), userID)
 	if err != nil {
-		// Handle "no rows found"
-		if errors.Is(err, pgx.ErrNoRows) {
-			errorMessage := fmt.Sprintf("No user found with provided fields: %s", err)
-			log.Println(errorMessage)
-			utils.RespondWithError(w, 404, errorMessage)
-			return
-		}
-		errorMessage := fmt.Sprintf("GetUserByMaiID error: %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.DatabaseError(fmt.Errorf("GetUserByUserID: %w", err), fmt.Sprintf("No user found with userID '%s'", userID)))
 		return
 	}
 
 	segaCreds, err := h.queries.GetSegaCredentialsByUserID(r.Context(), userID)
 	if err != nil {
-		errorMessage := fmt.Sprintf("GetSegaCredentials error: %s", err)
-		log.Println(errorMessage)
-		utils.RespondWithError(w, 400, errorMessage)
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetSegaCredentials: %w", err)))
 		return
 	}
 
 	decryptedSegaID, decryptErr := utils.Decrypt(segaCreds.EncryptedSegaID)
 	if decryptErr != nil {
-		log.Printf("failed to decrypt SEGA ID: %s", decryptErr)
-		utils.RespondWithError(w, 500, "Internal Server Error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to decrypt SEGA ID: %w", decryptErr)))
 		return
 	}
 	decryptedSegaPassword, decryptErr := utils.Decrypt(segaCreds.EncryptedSegaPassword)
 	if decryptErr != nil {
-		log.Printf("failed to decrypt SEGA password: %s", decryptErr)
-		utils.RespondWithError(w, 500, "Internal Server Error")
+		utils.RespondWithError(w, utils.Internal(fmt.Errorf("failed to decrypt SEGA password: %w", decryptErr)))
 		return
 	}
 	m := maimaiclient.New()
 	loginErr := m.Login(decryptedSegaID, decryptedSegaPassword)
 	if loginErr != nil {
-		log.Printf("Failed to login to maimai with SEGAID '%s': %s\n", segaCreds.EncryptedSegaID, err)
-		utils.RespondWithError(w, 400, "Invalid SegaID or password")
+		log.Printf("Failed to login to maimai for '%s': %s\n", userID, loginErr)
+		utils.RespondWithError(w, segaLoginError(loginErr))
 		return
 	}
 
@@ -104,8 +117,7 @@ func (h *Handler) UpdateUserByUserID(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if scrapeErr != nil {
-		log.Printf("Failed to scrape user data: %s", scrapeErr)
-		utils.RespondWithError(w, 400, "Invalid SegaID or password")
+		utils.RespondWithError(w, utils.Upstream(fmt.Errorf("failed to scrape user data: %w", scrapeErr)))
 		return
 	}
 
@@ -1,8 +1,83 @@
 package handler
 
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
 func ifNotNil[T any](v *T, fallback T) T {
 	if v == nil {
 		return fallback
 	}
 	return *v
 }
+
+// returns fallback if v is nil, NULL if v is empty
+func textOrKeep(v *string, fallback pgtype.Text) pgtype.Text {
+	if v == nil {
+		return fallback
+	}
+	if *v == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *v, Valid: true}
+}
+
+// decodeJSON decodes the request body into dst, normalizes it and checks
+// its validate tags. On failure the error response is already written and
+// false is returned.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	return decodeAndValidate(w, json.NewDecoder(r.Body), dst)
+}
+
+// decodeJSONStrict is decodeJSON but also rejects unknown fields, for
+// PATCH endpoints where a typo would otherwise silently change nothing.
+func decodeJSONStrict(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decodeAndValidate(w, decoder, dst)
+}
+
+// payloads implementing normalizer are cleaned up (trimmed etc.) before
+// they are validated
+type normalizer interface {
+	normalize()
+}
+
+func decodeAndValidate(w http.ResponseWriter, decoder *json.Decoder, dst any) bool {
+	if err := decoder.Decode(dst); err != nil {
+		utils.RespondWithError(w, decodeError(err))
+		return false
+	}
+	if n, ok := dst.(normalizer); ok {
+		n.normalize()
+	}
+	if err := utils.Validate(dst); err != nil {
+		utils.RespondWithError(w, err)
+		return false
+	}
+	return true
+}
+
+// a value of the wrong type is reported on its field, anything else is
+// just a bad payload
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		switch typeErr.Type.Kind() {
+		case reflect.String:
+			return utils.Invalid(typeErr.Field, "must be a string")
+		case reflect.Bool:
+			return utils.Invalid(typeErr.Field, "must be a boolean")
+		case reflect.Int32, reflect.Int64, reflect.Float64:
+			return utils.Invalid(typeErr.Field, "must be a number")
+		}
+		return utils.Invalid(typeErr.Field, "has the wrong type")
+	}
+	return utils.BadRequest("Invalid JSON payload")
+}
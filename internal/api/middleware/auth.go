@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,56 +18,99 @@ type contextKey string
 
 const UserContextKey contextKey = "authenticatedUser"
 
+var (
+	errNoToken   = errors.New("no token found")
+	errSecretKey = errors.New("error fetching secret key")
+)
+
 // Auth is a middleware that validates JWTs.
 func (m *Middleware) Auth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var tokenString string
-
-		// 1️⃣ Try getting token from cookie
-		cookie, err := r.Cookie("auth_token")
-		if err == nil {
-			tokenString = cookie.Value
-		} else {
-			// 2️⃣ If no cookie, try Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader != "" {
-				tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+		claims, err := parseClaims(r)
+		if err != nil {
+			// If no token found in either cookies or headers, reject the request
+			if errors.Is(err, errNoToken) {
+				utils.RespondWithError(w, utils.Unauthorized())
+				return
 			}
-		}
-
-		// If no token found in either cookies or headers, reject the request
-		if tokenString == "" {
-			utils.RespondWithError(w, 401, "Unauthorized")
+			if errors.Is(err, errSecretKey) {
+				utils.RespondWithError(w, utils.Internal(err))
+				return
+			}
+			log.Println("Invalid Token:", err)
+			utils.RespondWithError(w, utils.NewAPIError(401, utils.CodeUnauthorized, "Invalid Token"))
 			return
 		}
 
-		// Get the secret key
-		secretKey, secretKeyErr := service.GetSecretKey()
-		if secretKeyErr != nil {
-			log.Println("Error fetching secret key:", secretKeyErr)
-			utils.RespondWithError(w, 500, "Internal Server Error")
-			return
-		}
+		// Store claims in context for later use
+		ctx := context.WithValue(r.Context(), UserContextKey, claims)
 
-		// Parse the token
-		claims := &service.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %s", token.Header["alg"])
-			}
-			return secretKey, nil
-		})
+		next(w, r.WithContext(ctx))
+	}
+}
 
-		// Check if token is valid
-		if err != nil || !token.Valid {
-			log.Println("Invalid Token:", err)
-			utils.RespondWithError(w, 401, "Invalid Token")
+// OptionalAuth stores the claims in the context when a valid JWT is sent
+// but lets anonymous requests through, for routes whose response depends
+// on who is asking.
+func (m *Middleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, err := parseClaims(r)
+		if err != nil {
+			next(w, r)
 			return
 		}
 
-		// Store claims in context for later use
 		ctx := context.WithValue(r.Context(), UserContextKey, claims)
 
 		next(w, r.WithContext(ctx))
 	}
 }
+
+// returns the authenticated user stored by Auth or OptionalAuth
+func ClaimsFromContext(ctx context.Context) (*service.Claims, bool) {
+	claims, ok := ctx.Value(UserContextKey).(*service.Claims)
+	return claims, ok
+}
+
+func parseClaims(r *http.Request) (*service.Claims, error) {
+	var tokenString string
+
+	// 1️⃣ Try getting token from cookie
+	cookie, err := r.Cookie("auth_token")
+	if err == nil {
+		tokenString = cookie.Value
+	} else {
+		// 2️⃣ If no cookie, try Authorization header
+		authHeader := r.Header.Get("Authorization")
+		if authHeader != "" {
+			tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+	}
+
+	if tokenString == "" {
+		return nil, errNoToken
+	}
+
+	// Get the secret key
+	secretKey, err := service.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errSecretKey, err)
+	}
+
+	// Parse the token
+	claims := &service.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Header["alg"])
+		}
+		return secretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("token is invalid")
+	}
+
+	return claims, nil
+}
@@ -6,11 +6,14 @@ import (
 )
 
 type Middleware struct {
-	queries *sqlc.Queries
+	queries    *sqlc.Queries
+	rateLimits RateLimitStore
 }
 
 func New(pool *pgxpool.Pool) *Middleware {
+	queries := sqlc.New(pool)
 	return &Middleware{
-		queries: sqlc.New(pool),
+		queries:    queries,
+		rateLimits: newRateLimitStore(queries),
 	}
 }
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RateLimit allows Limit requests per Window for every key returned by Key.
+// Requests for which Key returns "" are not counted.
+type RateLimit struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	Key    func(r *http.Request) string
+}
+
+func PerIP(name string, limit int, window time.Duration) RateLimit {
+	return RateLimit{Name: name, Limit: limit, Window: window, Key: ClientIP}
+}
+
+// limits by the authenticated user, or the "userID" field of the JSON body
+// for routes such as login where nobody is authenticated yet
+func PerUserID(name string, limit int, window time.Duration) RateLimit {
+	return RateLimit{Name: name, Limit: limit, Window: window, Key: requestUserID}
+}
+
+// limits by the authenticated user, or the client IP of anonymous requests.
+// Needs OptionalAuth or Auth to run first.
+func PerCaller(name string, limit int, window time.Duration) RateLimit {
+	return RateLimit{Name: name, Limit: limit, Window: window, Key: func(r *http.Request) string {
+		if claims, ok := ClaimsFromContext(r.Context()); ok {
+			return "user:" + claims.UserID
+		}
+		return "ip:" + ClientIP(r)
+	}}
+}
+
+// limits by the {userID} URL parameter. Only authenticated requests are
+// counted, so anonymous callers cannot use up the limit of another user.
+// Needs OptionalAuth or Auth to run first.
+func PerTargetUserID(name string, limit int, window time.Duration) RateLimit {
+	return RateLimit{Name: name, Limit: limit, Window: window, Key: func(r *http.Request) string {
+		if _, ok := ClaimsFromContext(r.Context()); !ok {
+			return ""
+		}
+		return chi.URLParam(r, "userID")
+	}}
+}
+
+// RateLimitStore counts hits per key in fixed windows.
+type RateLimitStore interface {
+	// Increment counts a hit for key and returns the number of hits in the
+	// window starting at windowStart.
+	Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int, error)
+}
+
+// newRateLimitStore keeps counters in memory unless RATE_LIMIT_STORE=postgres,
+// which shares them between server instances.
+func newRateLimitStore(queries *database.Queries) RateLimitStore {
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		return &postgresRateLimitStore{queries: queries}
+	}
+	return &memoryRateLimitStore{counters: map[string]*rateLimitCounter{}}
+}
+
+// RateLimit rejects requests with 429 once any of the limits is exceeded.
+func (m *Middleware) RateLimit(next http.HandlerFunc, limits ...RateLimit) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().UTC()
+		for _, limit := range limits {
+			key := limit.Key(r)
+			if key == "" {
+				continue
+			}
+
+			windowStart := now.Truncate(limit.Window)
+			count, err := m.rateLimits.Increment(r.Context(), limit.Name+":"+key, windowStart, limit.Window)
+			if err != nil {
+				// don't lock everyone out when the store is down
+				log.Printf("rate limit '%s': %s", limit.Name, err)
+				continue
+			}
+
+			if count > limit.Limit {
+				retryAfter := windowStart.Add(limit.Window).Sub(now)
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				utils.RespondWithError(w, utils.NewAPIError(429, utils.CodeRateLimited, "Too many requests, please try again later"))
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
+
+// ClientIP is the client address, taken from the last X-Forwarded-For hop
+// when running behind a proxy with TRUST_PROXY_HEADERS=true
+func ClientIP(r *http.Request) string {
+	if os.Getenv("TRUST_PROXY_HEADERS") == "true" {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// largest body peeked at to find the userID
+const maxPeekBodySize = 1 << 20
+
+func requestUserID(r *http.Request) string {
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		return claims.UserID
+	}
+
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBodySize))
+	r.Body.Close()
+	// put the body back for the handler
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		UserID string `json:"userID"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return strings.ToLower(payload.UserID)
+}
+
+type rateLimitCounter struct {
+	windowStart time.Time
+	expiresAt   time.Time
+	count       int
+}
+
+type memoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]*rateLimitCounter
+}
+
+// expired counters are purged once the map grows past this size
+const memoryRateLimitPurgeSize = 10000
+
+func (s *memoryRateLimitStore) Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.counters) > memoryRateLimitPurgeSize {
+		now := time.Now()
+		for k, c := range s.counters {
+			if now.After(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+	}
+
+	c, ok := s.counters[key]
+	if !ok || !c.windowStart.Equal(windowStart) {
+		c = &rateLimitCounter{windowStart: windowStart, expiresAt: windowStart.Add(window)}
+		s.counters[key] = c
+	}
+	c.count++
+
+	return c.count, nil
+}
+
+type postgresRateLimitStore struct {
+	queries *database.Queries
+
+	mu   sync.Mutex
+	hits int
+}
+
+// expired rows are deleted every this many hits
+const postgresRateLimitPurgeInterval = 1000
+
+// rows are kept for this long, longer than any window in use
+const postgresRateLimitRetention = 24 * time.Hour
+
+func (s *postgresRateLimitStore) Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int, error) {
+	s.mu.Lock()
+	s.hits++
+	purge := s.hits%postgresRateLimitPurgeInterval == 0
+	s.mu.Unlock()
+
+	if purge {
+		cutoff := time.Now().UTC().Add(-postgresRateLimitRetention)
+		if err := s.queries.DeleteExpiredRateLimits(ctx, pgtype.Timestamp{Time: cutoff, Valid: true}); err != nil {
+			log.Printf("failed to delete expired rate limits: %s", err)
+		}
+	}
+
+	count, err := s.queries.IncrementRateLimit(ctx, database.IncrementRateLimitParams{
+		Key:         key,
+		WindowStart: pgtype.Timestamp{Time: windowStart, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment rate limit: %w", err)
+	}
+	return int(count), nil
+}
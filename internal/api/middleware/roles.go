@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/asashakira/maitrack/internal/service"
+	"github.com/asashakira/maitrack/internal/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// RequireRole is Auth that additionally rejects users ranked below role.
+// Roles are read from the database on every request so revoking one
+// takes effect without waiting for tokens to expire.
+func (m *Middleware) RequireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return m.Auth(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+
+		userRole, err := m.queries.GetUserRoleByUserID(r.Context(), claims.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				utils.RespondWithError(w, utils.Unauthorized())
+				return
+			}
+			utils.RespondWithError(w, utils.Internal(fmt.Errorf("GetUserRoleByUserID: %w", err)))
+			return
+		}
+
+		if !service.HasRole(userRole, role) {
+			utils.RespondWithError(w, utils.Forbidden())
+			return
+		}
+
+		next(w, r)
+	})
+}
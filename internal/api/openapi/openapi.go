@@ -0,0 +1,77 @@
+// Package openapi holds the OpenAPI 3 description of the v1 API.
+//
+// openapi.json is maintained by hand next to the routes; cmd/openapigen
+// checks it against api.SetUpRoutes and generates pkg/maitrackapi from it,
+// so every route change must be reflected here.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+// the routes in the spec are relative to this prefix
+const BasePath = "/v1"
+
+type Document struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+func Load() (Document, error) {
+	var doc Document
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		return doc, fmt.Errorf("parsing openapi.json: %w", err)
+	}
+	return doc, nil
+}
+
+// CheckRoutes reports routes registered on router that the spec does not
+// document, and documented operations that are not routed.
+func CheckRoutes(router chi.Routes) error {
+	doc, err := Load()
+	if err != nil {
+		return err
+	}
+
+	documented := map[string]bool{}
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	var problems []string
+	err = chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path, ok := strings.CutPrefix(route, BasePath)
+		if !ok || method == http.MethodOptions {
+			return nil
+		}
+		key := method + " " + path
+		if !documented[key] {
+			problems = append(problems, "undocumented route "+key)
+		}
+		delete(documented, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for key := range documented {
+		problems = append(problems, "documented but not routed "+key)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("openapi.json is out of date:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
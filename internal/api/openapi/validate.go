@@ -0,0 +1,317 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is the subset of JSON Schema openapi.json is written in.
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	AllOf      []*Schema          `json:"allOf"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Nullable   bool               `json:"nullable"`
+	Enum       []any              `json:"enum"`
+	Items      *Schema            `json:"items"`
+	Properties map[string]*Schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+	MinLength  *int               `json:"minLength"`
+	MaxLength  *int               `json:"maxLength"`
+	Pattern    string             `json:"pattern"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type body struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]mediaType `json:"content"`
+}
+
+type operation struct {
+	RequestBody *body            `json:"requestBody"`
+	Responses   map[string]*body `json:"responses"`
+}
+
+// Validator checks request and response bodies against openapi.json.
+// Objects with properties are closed: a key the spec does not document is
+// an error, so fields cannot be added to a response without the spec.
+type Validator struct {
+	paths     map[string]map[string]*operation
+	schemas   map[string]*Schema
+	responses map[string]*body
+	patterns  map[string]*regexp.Regexp
+}
+
+func NewValidator() (*Validator, error) {
+	var doc struct {
+		Paths      map[string]map[string]*operation `json:"paths"`
+		Components struct {
+			Schemas   map[string]*Schema `json:"schemas"`
+			Responses map[string]*body   `json:"responses"`
+		} `json:"components"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(Spec))
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parsing openapi.json: %w", err)
+	}
+	return &Validator{
+		paths:     doc.Paths,
+		schemas:   doc.Components.Schemas,
+		responses: doc.Components.Responses,
+		patterns:  map[string]*regexp.Regexp{},
+	}, nil
+}
+
+func (v *Validator) operation(method, path string) (*operation, error) {
+	op, ok := v.paths[path][strings.ToLower(method)]
+	if !ok {
+		return nil, fmt.Errorf("%s %s is not documented", method, path)
+	}
+	return op, nil
+}
+
+// ValidateRequest checks a JSON request body of the operation at path,
+// the route as written in the spec, e.g. "/me/goals/{goalID}".
+func (v *Validator) ValidateRequest(method, path string, data []byte) error {
+	op, err := v.operation(method, path)
+	if err != nil {
+		return err
+	}
+	if op.RequestBody == nil {
+		return fmt.Errorf("%s %s takes no request body", method, path)
+	}
+	return v.validateBody(op.RequestBody, data)
+}
+
+// ValidateResponse checks a JSON response body the operation returned
+// with status.
+func (v *Validator) ValidateResponse(method, path string, status int, data []byte) error {
+	op, err := v.operation(method, path)
+	if err != nil {
+		return err
+	}
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		return fmt.Errorf("%s %s does not document status %d", method, path, status)
+	}
+	return v.validateBody(resp, data)
+}
+
+// ValidateSchema checks data against a schema of components.schemas.
+func (v *Validator) ValidateSchema(name string, data []byte) error {
+	return v.validateJSON(&Schema{Ref: "#/components/schemas/" + name}, data)
+}
+
+func (v *Validator) validateBody(b *body, data []byte) error {
+	if name, ok := strings.CutPrefix(b.Ref, "#/components/responses/"); ok {
+		resolved, ok := v.responses[name]
+		if !ok {
+			return fmt.Errorf("unknown response %s", b.Ref)
+		}
+		b = resolved
+	}
+	media, ok := b.Content["application/json"]
+	if !ok || media.Schema == nil {
+		if len(bytes.TrimSpace(data)) > 0 {
+			return fmt.Errorf("no body is documented, got %s", data)
+		}
+		return nil
+	}
+	return v.validateJSON(media.Schema, data)
+}
+
+func (v *Validator) validateJSON(s *Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	var errs []string
+	v.validate(s, value, "$", true, &errs)
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (v *Validator) resolve(s *Schema) *Schema {
+	for s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		resolved, ok := v.schemas[name]
+		if !ok {
+			return &Schema{Type: "unknown " + s.Ref}
+		}
+		s = resolved
+	}
+	return s
+}
+
+// properties s and the parts of its allOf document
+func (v *Validator) knownProperties(s *Schema, known map[string]bool) {
+	s = v.resolve(s)
+	for name := range s.Properties {
+		known[name] = true
+	}
+	for _, part := range s.AllOf {
+		v.knownProperties(part, known)
+	}
+}
+
+func (v *Validator) validate(s *Schema, value any, at string, closed bool, errs *[]string) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	// nullable may sit next to a $ref
+	nullable := s.Nullable
+	s = v.resolve(s)
+	if value == nil {
+		if !nullable && !s.Nullable && s.Type != "" {
+			fail("is null")
+		}
+		return
+	}
+
+	if len(s.AllOf) > 0 {
+		for _, part := range s.AllOf {
+			v.validate(part, value, at, false, errs)
+		}
+		// the parts only know their own properties
+		if obj, ok := value.(map[string]any); ok && closed {
+			known := map[string]bool{}
+			v.knownProperties(s, known)
+			for name := range obj {
+				if !known[name] {
+					fail("%s is not documented", name)
+				}
+			}
+		}
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		fail("%v is not one of %v", value, s.Enum)
+	}
+
+	switch s.Type {
+	case "":
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			fail("is not an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				fail("%s is required", name)
+			}
+		}
+		known := map[string]bool{}
+		v.knownProperties(s, known)
+		for name, child := range obj {
+			if prop, ok := s.Properties[name]; ok {
+				v.validate(prop, child, at+"."+name, true, errs)
+			} else if closed && len(known) > 0 && !known[name] {
+				fail("%s is not documented", name)
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			fail("is not an array")
+			return
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				v.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i), true, errs)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("is not a string")
+			return
+		}
+		v.validateString(s, str, fail)
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("is not a number")
+			return
+		}
+		f, err := n.Float64()
+		if err != nil {
+			fail("is not a number")
+			return
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			fail("%v is not an integer", n)
+		}
+		if s.Format == "int32" && (f < math.MinInt32 || f > math.MaxInt32) {
+			fail("%v does not fit int32", n)
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("%v is below %v", n, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("%v is above %v", n, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("is not a boolean")
+		}
+	default:
+		fail("has unsupported type %s", s.Type)
+	}
+}
+
+func (v *Validator) validateString(s *Schema, str string, fail func(string, ...any)) {
+	switch s.Format {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			fail("%q is not a uuid", str)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			fail("%q is not a date-time", str)
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			fail("%q is not a date", str)
+		}
+	}
+	length := len([]rune(str))
+	if s.MinLength != nil && length < *s.MinLength {
+		fail("is shorter than %d", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("is longer than %d", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, ok := v.patterns[s.Pattern]
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(s.Pattern); err != nil {
+				fail("has invalid pattern %s", s.Pattern)
+				return
+			}
+			v.patterns[s.Pattern] = re
+		}
+		if !re.MatchString(str) {
+			fail("%q does not match %s", str, s.Pattern)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package response
+
+import (
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// from and to are the JST dates the calendar covers, both inclusive
+type Activity struct {
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	TotalPlays    int64           `json:"totalPlays"`
+	ActiveDays    int             `json:"activeDays"`
+	Days          []ActivityDay   `json:"days"`
+	CurrentStreak *Streak         `json:"currentStreak"`
+	LongestStreak *Streak         `json:"longestStreak"`
+	MostPlayed    []PlayedBeatmap `json:"mostPlayed"`
+	Versions      []VersionPlays  `json:"versions"`
+}
+
+type ActivityDay struct {
+	Date  string `json:"date"`
+	Plays int64  `json:"plays"`
+}
+
+// consecutive JST days with plays, both inclusive
+type Streak struct {
+	Days  int64  `json:"days"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func NewStreak(s database.GetPlayStreaksByUserIDRow) *Streak {
+	return &Streak{Days: s.Days, Start: *date(s.FirstDay), End: *date(s.LastDay)}
+}
+
+type PlayedBeatmap struct {
+	BeatmapID  uuid.UUID `json:"beatmapID"`
+	SongID     uuid.UUID `json:"songID"`
+	Title      string    `json:"title"`
+	ImageUrl   string    `json:"imageUrl"`
+	Difficulty string    `json:"difficulty"`
+	Level      string    `json:"level"`
+	Type       string    `json:"type"`
+	Plays      int64     `json:"plays"`
+}
+
+func NewPlayedBeatmaps(beatmaps []database.GetMostPlayedBeatmapsByUserIDRow) []PlayedBeatmap {
+	out := make([]PlayedBeatmap, 0, len(beatmaps))
+	for _, b := range beatmaps {
+		out = append(out, PlayedBeatmap{
+			BeatmapID:  b.BeatmapID,
+			SongID:     b.SongID,
+			Title:      b.Title,
+			ImageUrl:   b.ImageUrl,
+			Difficulty: b.Difficulty,
+			Level:      b.Level,
+			Type:       b.Type,
+			Plays:      b.Plays,
+		})
+	}
+	return out
+}
+
+type VersionPlays struct {
+	Version string `json:"version"`
+	Plays   int64  `json:"plays"`
+}
+
+func NewVersionPlays(versions []database.GetPlayCountsByVersionByUserIDRow) []VersionPlays {
+	out := make([]VersionPlays, 0, len(versions))
+	for _, v := range versions {
+		out = append(out, VersionPlays{Version: v.Version, Plays: v.Plays})
+	}
+	return out
+}
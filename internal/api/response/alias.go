@@ -0,0 +1,34 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+type SongAlias struct {
+	ID        uuid.UUID  `json:"id"`
+	SongID    uuid.UUID  `json:"songID"`
+	Alias     string     `json:"alias"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+func NewSongAlias(a database.SongAlias) SongAlias {
+	return SongAlias{
+		ID:        a.ID,
+		SongID:    a.SongID,
+		Alias:     a.Alias,
+		CreatedAt: timestamp(a.CreatedAt),
+		UpdatedAt: timestamp(a.UpdatedAt),
+	}
+}
+
+func NewSongAliases(aliases []database.SongAlias) []SongAlias {
+	out := make([]SongAlias, 0, len(aliases))
+	for _, a := range aliases {
+		out = append(out, NewSongAlias(a))
+	}
+	return out
+}
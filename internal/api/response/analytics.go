@@ -0,0 +1,142 @@
+package response
+
+import (
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// rates are null when there is nothing to divide by
+type Analytics struct {
+	Plays             int64          `json:"plays"`
+	Judgements        JudgementRates `json:"judgements"`
+	BreakCriticalRate *float64       `json:"breakCriticalRate"`
+	FastLate          FastLate       `json:"fastLate"`
+	FastLateTrend     []FastLateWeek `json:"fastLateTrend"`
+	FastLateSlope     *float64       `json:"fastLateSlope"`
+	WeakSpots         []WeakSpot     `json:"weakSpots"`
+}
+
+type JudgementRates struct {
+	Tap   NoteTypeRates `json:"tap"`
+	Hold  NoteTypeRates `json:"hold"`
+	Slide NoteTypeRates `json:"slide"`
+	Touch NoteTypeRates `json:"touch"`
+	Break NoteTypeRates `json:"break"`
+}
+
+// counts and shares of the judgements of one note type
+type NoteTypeRates struct {
+	Notes        int64    `json:"notes"`
+	Critical     int64    `json:"critical"`
+	Perfect      int64    `json:"perfect"`
+	Great        int64    `json:"great"`
+	Good         int64    `json:"good"`
+	Miss         int64    `json:"miss"`
+	CriticalRate *float64 `json:"criticalRate"`
+	GreatRate    *float64 `json:"greatRate"`
+	GoodRate     *float64 `json:"goodRate"`
+	MissRate     *float64 `json:"missRate"`
+}
+
+func NewNoteTypeRates(critical, perfect, great, good, miss int64) NoteTypeRates {
+	notes := critical + perfect + great + good + miss
+	return NoteTypeRates{
+		Notes:        notes,
+		Critical:     critical,
+		Perfect:      perfect,
+		Great:        great,
+		Good:         good,
+		Miss:         miss,
+		CriticalRate: rate(critical, notes),
+		GreatRate:    rate(great, notes),
+		GoodRate:     rate(good, notes),
+		MissRate:     rate(miss, notes),
+	}
+}
+
+func NewJudgementRates(t database.GetJudgementTotalsByUserIDRow) JudgementRates {
+	return JudgementRates{
+		Tap:   NewNoteTypeRates(t.TapCritical, t.TapPerfect, t.TapGreat, t.TapGood, t.TapMiss),
+		Hold:  NewNoteTypeRates(t.HoldCritical, t.HoldPerfect, t.HoldGreat, t.HoldGood, t.HoldMiss),
+		Slide: NewNoteTypeRates(t.SlideCritical, t.SlidePerfect, t.SlideGreat, t.SlideGood, t.SlideMiss),
+		Touch: NewNoteTypeRates(t.TouchCritical, t.TouchPerfect, t.TouchGreat, t.TouchGood, t.TouchMiss),
+		Break: NewNoteTypeRates(t.BreakCritical, t.BreakPerfect, t.BreakGreat, t.BreakGood, t.BreakMiss),
+	}
+}
+
+// bias is (late - fast) / (fast + late): -1 all fast, 1 all late
+type FastLate struct {
+	Fast int64    `json:"fast"`
+	Late int64    `json:"late"`
+	Bias *float64 `json:"bias"`
+}
+
+func NewFastLate(fast, late int64) FastLate {
+	return FastLate{Fast: fast, Late: late, Bias: FastLateBias(fast, late)}
+}
+
+func FastLateBias(fast, late int64) *float64 {
+	return rate(late-fast, fast+late)
+}
+
+// week is the monday the JST week starts on
+type FastLateWeek struct {
+	Week  string `json:"week"`
+	Plays int64  `json:"plays"`
+	FastLate
+}
+
+func NewFastLateWeek(w database.GetFastLateTrendByUserIDRow) FastLateWeek {
+	week := ""
+	if w.Week.Valid {
+		week = w.Week.Time.Format("2006-01-02")
+	}
+	return FastLateWeek{Week: week, Plays: w.Plays, FastLate: NewFastLate(w.Fast, w.Late)}
+}
+
+// notes counts every note of every play, the rates are per note
+type WeakSpot struct {
+	BeatmapID          uuid.UUID `json:"beatmapID"`
+	SongID             uuid.UUID `json:"songID"`
+	Title              string    `json:"title"`
+	ImageUrl           string    `json:"imageUrl"`
+	Difficulty         string    `json:"difficulty"`
+	Level              string    `json:"level"`
+	InternalLevel      *float64  `json:"internalLevel"`
+	Type               string    `json:"type"`
+	Plays              int64     `json:"plays"`
+	AverageAchievement float64   `json:"averageAchievement"`
+	Notes              int64     `json:"notes"`
+	GreatRate          *float64  `json:"greatRate"`
+	GoodRate           *float64  `json:"goodRate"`
+	MissRate           *float64  `json:"missRate"`
+	FastLate
+}
+
+func NewWeakSpot(s database.GetWeakSpotsByUserIDRow) WeakSpot {
+	return WeakSpot{
+		BeatmapID:          s.BeatmapID,
+		SongID:             s.SongID,
+		Title:              s.Title,
+		ImageUrl:           s.ImageUrl,
+		Difficulty:         s.Difficulty,
+		Level:              s.Level,
+		InternalLevel:      numeric(s.InternalLevel),
+		Type:               s.Type,
+		Plays:              s.Plays,
+		AverageAchievement: s.AverageAchievement,
+		Notes:              s.Notes,
+		GreatRate:          rate(s.Great, s.Notes),
+		GoodRate:           rate(s.Good, s.Notes),
+		MissRate:           rate(s.Miss, s.Notes),
+		FastLate:           NewFastLate(s.Fast, s.Late),
+	}
+}
+
+func rate(n, total int64) *float64 {
+	if total == 0 {
+		return nil
+	}
+	r := float64(n) / float64(total)
+	return &r
+}
@@ -0,0 +1,103 @@
+package response
+
+import (
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// song fields are only set when the beatmap was queried with its song
+type Beatmap struct {
+	ID            uuid.UUID `json:"id"`
+	SongID        uuid.UUID `json:"songID"`
+	Difficulty    string    `json:"difficulty"`
+	Level         string    `json:"level"`
+	InternalLevel *float64  `json:"internalLevel"`
+	Type          string    `json:"type"`
+	TotalNotes    int32     `json:"totalNotes"`
+	Tap           int32     `json:"tap"`
+	Hold          int32     `json:"hold"`
+	Slide         int32     `json:"slide"`
+	Touch         int32     `json:"touch"`
+	Break         int32     `json:"break"`
+	NoteDesigner  string    `json:"noteDesigner"`
+	MaxDxScore    int32     `json:"maxDxScore"`
+	Title         string    `json:"title,omitempty"`
+	Artist        string    `json:"artist,omitempty"`
+	Genre         string    `json:"genre,omitempty"`
+	Bpm           string    `json:"bpm,omitempty"`
+	ImageUrl      string    `json:"imageUrl,omitempty"`
+	Version       string    `json:"version,omitempty"`
+}
+
+func NewBeatmap(b database.Beatmap) Beatmap {
+	return Beatmap{
+		ID:            b.ID,
+		SongID:        b.SongID,
+		Difficulty:    b.Difficulty,
+		Level:         b.Level,
+		InternalLevel: numeric(b.InternalLevel),
+		Type:          b.Type,
+		TotalNotes:    b.TotalNotes,
+		Tap:           b.Tap,
+		Hold:          b.Hold,
+		Slide:         b.Slide,
+		Touch:         b.Touch,
+		Break:         b.Break,
+		NoteDesigner:  b.NoteDesigner,
+		MaxDxScore:    b.MaxDxScore,
+	}
+}
+
+func NewBeatmapWithSong(b database.GetBeatmapByBeatmapIDRow) Beatmap {
+	return Beatmap{
+		ID:            b.ID,
+		SongID:        b.SongID,
+		Difficulty:    b.Difficulty,
+		Level:         b.Level,
+		InternalLevel: numeric(b.InternalLevel),
+		Type:          b.Type,
+		TotalNotes:    b.TotalNotes,
+		Tap:           b.Tap,
+		Hold:          b.Hold,
+		Slide:         b.Slide,
+		Touch:         b.Touch,
+		Break:         b.Break,
+		NoteDesigner:  b.NoteDesigner,
+		MaxDxScore:    b.MaxDxScore,
+		Title:         b.Title,
+		Artist:        b.Artist,
+		Genre:         b.Genre,
+		Bpm:           b.Bpm,
+		ImageUrl:      b.ImageUrl,
+		Version:       b.Version,
+	}
+}
+
+func NewBeatmapsWithSong(beatmaps []database.ListBeatmapsRow) []Beatmap {
+	out := make([]Beatmap, 0, len(beatmaps))
+	for _, b := range beatmaps {
+		out = append(out, NewBeatmapWithSong(database.GetBeatmapByBeatmapIDRow{
+			ID:            b.ID,
+			SongID:        b.SongID,
+			Difficulty:    b.Difficulty,
+			Level:         b.Level,
+			InternalLevel: b.InternalLevel,
+			Type:          b.Type,
+			TotalNotes:    b.TotalNotes,
+			Tap:           b.Tap,
+			Hold:          b.Hold,
+			Slide:         b.Slide,
+			Touch:         b.Touch,
+			Break:         b.Break,
+			NoteDesigner:  b.NoteDesigner,
+			MaxDxScore:    b.MaxDxScore,
+			Title:         b.Title,
+			Artist:        b.Artist,
+			Genre:         b.Genre,
+			Bpm:           b.Bpm,
+			ImageUrl:      b.ImageUrl,
+			Version:       b.Version,
+		}))
+	}
+	return out
+}
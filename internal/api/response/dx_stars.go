@@ -0,0 +1,19 @@
+package response
+
+// played charts whose max DX score is not known count towards played and
+// unknown but not towards dxStars
+type DxStarStats struct {
+	Charts       int                `json:"charts"`
+	Played       int                `json:"played"`
+	Unknown      int                `json:"unknown"`
+	DxStars      []LevelTableCount  `json:"dxStars"`
+	Difficulties []DxStarDifficulty `json:"difficulties"`
+	Below        *int               `json:"below"`
+	BelowCharts  []LevelTableChart  `json:"belowCharts"`
+}
+
+type DxStarDifficulty struct {
+	Difficulty string            `json:"difficulty"`
+	Played     int               `json:"played"`
+	DxStars    []LevelTableCount `json:"dxStars"`
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// kind is "personal_best", "rank", "lamp" or "rating_milestone".
+// The score and beatmap fields are null for rating milestones, rating is
+// only set for them. previousAccuracy is the best before the play, null on
+// a first play.
+type FeedItem struct {
+	ID               uuid.UUID  `json:"id"`
+	Kind             string     `json:"kind"`
+	UserID           string     `json:"userID"`
+	DisplayName      string     `json:"displayName"`
+	ScoreID          *uuid.UUID `json:"scoreID"`
+	BeatmapID        *uuid.UUID `json:"beatmapID"`
+	SongID           *uuid.UUID `json:"songID"`
+	Title            *string    `json:"title"`
+	ImageUrl         *string    `json:"imageUrl"`
+	Difficulty       *string    `json:"difficulty"`
+	Level            *string    `json:"level"`
+	Type             *string    `json:"type"`
+	Accuracy         *string    `json:"accuracy"`
+	PreviousAccuracy *string    `json:"previousAccuracy"`
+	Rank             *string    `json:"rank"`
+	Lamp             *string    `json:"lamp"`
+	Rating           *int32     `json:"rating"`
+	CreatedAt        *time.Time `json:"createdAt"`
+}
+
+func NewFeedItems(rows []database.GetFeedByUserIDRow) []FeedItem {
+	out := make([]FeedItem, 0, len(rows))
+	for _, f := range rows {
+		out = append(out, FeedItem{
+			ID:               f.ID,
+			Kind:             f.Kind,
+			UserID:           f.UserID,
+			DisplayName:      f.DisplayName,
+			ScoreID:          uuidOrNil(f.ScoreID),
+			BeatmapID:        uuidOrNil(f.BeatmapID),
+			SongID:           uuidOrNil(f.SongID),
+			Title:            text(f.Title),
+			ImageUrl:         text(f.ImageUrl),
+			Difficulty:       text(f.Difficulty),
+			Level:            text(f.Level),
+			Type:             text(f.Type),
+			Accuracy:         text(f.Accuracy),
+			PreviousAccuracy: text(f.PreviousAccuracy),
+			Rank:             text(f.Rank),
+			Lamp:             text(f.Lamp),
+			Rating:           int4(f.Rating),
+			CreatedAt:        timestamp(f.CreatedAt),
+		})
+	}
+	return out
+}
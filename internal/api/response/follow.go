@@ -0,0 +1,45 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// mutual follows are friends
+type Follow struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      string     `json:"userID"`
+	DisplayName string     `json:"displayName"`
+	Mutual      bool       `json:"mutual"`
+	FollowedAt  *time.Time `json:"followedAt"`
+}
+
+func NewFollowing(rows []database.GetFollowingByUserIDRow) []Follow {
+	out := make([]Follow, 0, len(rows))
+	for _, f := range rows {
+		out = append(out, Follow{
+			ID:          f.ID,
+			UserID:      f.UserID,
+			DisplayName: f.DisplayName,
+			Mutual:      f.Mutual,
+			FollowedAt:  timestamp(f.CreatedAt),
+		})
+	}
+	return out
+}
+
+func NewFollowers(rows []database.GetFollowersByUserIDRow) []Follow {
+	out := make([]Follow, 0, len(rows))
+	for _, f := range rows {
+		out = append(out, Follow{
+			ID:          f.ID,
+			UserID:      f.UserID,
+			DisplayName: f.DisplayName,
+			Mutual:      f.Mutual,
+			FollowedAt:  timestamp(f.CreatedAt),
+		})
+	}
+	return out
+}
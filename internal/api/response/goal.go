@@ -0,0 +1,67 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// progress and target are as of evaluatedAt: the rating for rating
+// goals, 0 or 1 for chart goals and the number of charts reaching the
+// target for chart_set and count goals
+type Goal struct {
+	ID           uuid.UUID  `json:"id"`
+	Kind         string     `json:"kind"`
+	Title        string     `json:"title"`
+	TargetRating *int32     `json:"targetRating"`
+	BeatmapID    *uuid.UUID `json:"beatmapID"`
+	TargetRank   *string    `json:"targetRank"`
+	TargetLamp   *string    `json:"targetLamp"`
+	Difficulty   *string    `json:"difficulty"`
+	Type         *string    `json:"type"`
+	Level        *string    `json:"level"`
+	LevelMin     *float64   `json:"levelMin"`
+	LevelMax     *float64   `json:"levelMax"`
+	TargetCount  *int32     `json:"targetCount"`
+	Progress     int32      `json:"progress"`
+	Target       int32      `json:"target"`
+	Completed    bool       `json:"completed"`
+	CompletedAt  *time.Time `json:"completedAt"`
+	EvaluatedAt  *time.Time `json:"evaluatedAt"`
+	UpdatedAt    *time.Time `json:"updatedAt"`
+	CreatedAt    *time.Time `json:"createdAt"`
+}
+
+func NewGoal(g database.Goal) Goal {
+	return Goal{
+		ID:           g.ID,
+		Kind:         g.Kind,
+		Title:        g.Title,
+		TargetRating: int4(g.TargetRating),
+		BeatmapID:    uuidOrNil(g.BeatmapID),
+		TargetRank:   text(g.TargetRank),
+		TargetLamp:   text(g.TargetLamp),
+		Difficulty:   text(g.Difficulty),
+		Type:         text(g.Type),
+		Level:        text(g.Level),
+		LevelMin:     numeric(g.LevelMin),
+		LevelMax:     numeric(g.LevelMax),
+		TargetCount:  int4(g.TargetCount),
+		Progress:     g.Progress,
+		Target:       g.Target,
+		Completed:    g.CompletedAt.Valid,
+		CompletedAt:  timestamp(g.CompletedAt),
+		EvaluatedAt:  timestamp(g.EvaluatedAt),
+		UpdatedAt:    timestamp(g.UpdatedAt),
+		CreatedAt:    timestamp(g.CreatedAt),
+	}
+}
+
+func NewGoals(goals []database.Goal) []Goal {
+	out := make([]Goal, 0, len(goals))
+	for _, g := range goals {
+		out = append(out, NewGoal(g))
+	}
+	return out
+}
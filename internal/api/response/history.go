@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+// points are oldest first, date is the JST day or the Monday of the JST
+// week the point stands for
+type RatingHistory struct {
+	Bucket string               `json:"bucket"`
+	Points []RatingHistoryPoint `json:"points"`
+}
+
+// deltas are against the previous point, null for the user's first
+// snapshot
+type RatingHistoryPoint struct {
+	Date                 string    `json:"date"`
+	RecordedAt           time.Time `json:"recordedAt"`
+	Rating               int32     `json:"rating"`
+	SeasonPlayCount      int32     `json:"seasonPlayCount"`
+	TotalPlayCount       int32     `json:"totalPlayCount"`
+	RatingDelta          *int32    `json:"ratingDelta"`
+	SeasonPlayCountDelta *int32    `json:"seasonPlayCountDelta"`
+	TotalPlayCountDelta  *int32    `json:"totalPlayCountDelta"`
+}
@@ -0,0 +1,83 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// users with the same achievement, dx score and play date share a rank
+type BeatmapLeaderboardEntry struct {
+	Rank        int64      `json:"rank"`
+	UserID      string     `json:"userID"`
+	DisplayName string     `json:"displayName"`
+	ScoreID     uuid.UUID  `json:"scoreID"`
+	Accuracy    string     `json:"accuracy"`
+	DxScore     int32      `json:"dxScore"`
+	PlayedAt    *time.Time `json:"playedAt"`
+	IsMe        bool       `json:"isMe"`
+}
+
+func NewBeatmapLeaderboardEntry(e database.GetBeatmapLeaderboardEntryRow, viewerID string) BeatmapLeaderboardEntry {
+	return BeatmapLeaderboardEntry{
+		Rank:        e.Rank,
+		UserID:      e.UserID,
+		DisplayName: e.DisplayName,
+		ScoreID:     e.ID,
+		Accuracy:    e.Accuracy,
+		DxScore:     e.DxScore,
+		PlayedAt:    timestamp(e.PlayedAt),
+		IsMe:        e.UserID == viewerID,
+	}
+}
+
+func NewBeatmapLeaderboardEntries(entries []database.GetBeatmapLeaderboardRow, viewerID string) []BeatmapLeaderboardEntry {
+	out := make([]BeatmapLeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, NewBeatmapLeaderboardEntry(database.GetBeatmapLeaderboardEntryRow{
+			ID:          e.ID,
+			UserID:      e.UserID,
+			DisplayName: e.DisplayName,
+			Accuracy:    e.Accuracy,
+			Achievement: e.Achievement,
+			DxScore:     e.DxScore,
+			PlayedAt:    e.PlayedAt,
+			Rank:        e.Rank,
+		}, viewerID))
+	}
+	return out
+}
+
+// users with the same rating share a rank
+type RatingLeaderboardEntry struct {
+	Rank        int64  `json:"rank"`
+	UserID      string `json:"userID"`
+	DisplayName string `json:"displayName"`
+	Rating      int32  `json:"rating"`
+	IsMe        bool   `json:"isMe"`
+}
+
+func NewRatingLeaderboardEntry(e database.GetRatingLeaderboardEntryRow, viewerID string) RatingLeaderboardEntry {
+	return RatingLeaderboardEntry{
+		Rank:        e.Rank,
+		UserID:      e.UserID,
+		DisplayName: e.DisplayName,
+		Rating:      e.Rating,
+		IsMe:        e.UserID == viewerID,
+	}
+}
+
+func NewRatingLeaderboardEntries(entries []database.GetRatingLeaderboardRow, viewerID string) []RatingLeaderboardEntry {
+	out := make([]RatingLeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, NewRatingLeaderboardEntry(database.GetRatingLeaderboardEntryRow{
+			ID:          e.ID,
+			UserID:      e.UserID,
+			DisplayName: e.DisplayName,
+			Rating:      e.Rating,
+			Rank:        e.Rank,
+		}, viewerID))
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package response
+
+import (
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// charts are grouped into tiers by internal level, hardest first. Charts
+// without an internal level come last in a tier whose internalLevel is null.
+type LevelTable struct {
+	Level    *string           `json:"level"`
+	LevelMin *float64          `json:"levelMin"`
+	LevelMax *float64          `json:"levelMax"`
+	Charts   int               `json:"charts"`
+	Played   int               `json:"played"`
+	Ranks    []LevelTableCount `json:"ranks"`
+	Lamps    []LevelTableCount `json:"lamps"`
+	DxStars  []LevelTableCount `json:"dxStars"`
+	Tiers    []LevelTier       `json:"tiers"`
+}
+
+// charts whose best is exactly this rank, lamp or number of stars.
+// Unplayed charts are not counted.
+type LevelTableCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type LevelTier struct {
+	InternalLevel *float64          `json:"internalLevel"`
+	Charts        []LevelTableChart `json:"charts"`
+}
+
+// the user's bests are null when unplayed, dxStars also when the max DX
+// score of the chart is not known
+type LevelTableChart struct {
+	BeatmapID     uuid.UUID `json:"beatmapID"`
+	SongID        uuid.UUID `json:"songID"`
+	Title         string    `json:"title"`
+	ImageUrl      string    `json:"imageUrl"`
+	Difficulty    string    `json:"difficulty"`
+	Level         string    `json:"level"`
+	InternalLevel *float64  `json:"internalLevel"`
+	Type          string    `json:"type"`
+	MaxDxScore    int32     `json:"maxDxScore"`
+	Accuracy      *string   `json:"accuracy"`
+	Achievement   *float64  `json:"achievement"`
+	Rank          *string   `json:"rank"`
+	Lamp          *string   `json:"lamp"`
+	DxScore       *int32    `json:"dxScore"`
+	DxStars       *int      `json:"dxStars"`
+}
+
+func NewLevelTableChart(c database.GetLevelTableByUserIDRow) LevelTableChart {
+	chart := LevelTableChart{
+		BeatmapID:     c.BeatmapID,
+		SongID:        c.SongID,
+		Title:         c.Title,
+		ImageUrl:      c.ImageUrl,
+		Difficulty:    c.Difficulty,
+		Level:         c.Level,
+		InternalLevel: numeric(c.InternalLevel),
+		Type:          c.Type,
+		MaxDxScore:    c.MaxDxScore,
+		Accuracy:      text(c.Accuracy),
+		DxScore:       int4(c.DxScore),
+	}
+	if c.Achievement.Valid {
+		achievement := c.Achievement.Float64
+		rank := calculator.RankOf(achievement).Name
+		chart.Achievement, chart.Rank = &achievement, &rank
+	}
+	if c.Lamp.Valid && calculator.Lamp(c.Lamp.Int32) != calculator.LampNone {
+		lamp := calculator.Lamp(c.Lamp.Int32).String()
+		chart.Lamp = &lamp
+	}
+	if c.DxScore.Valid {
+		chart.DxStars = dxStars(c.DxScore.Int32, c.MaxDxScore)
+	}
+	return chart
+}
@@ -0,0 +1,76 @@
+package response
+
+import (
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// cleared and remaining count charts, both are null for plates whose
+// progress is not tracked
+type PlateSummary struct {
+	Name        string `json:"name"`
+	Requirement string `json:"requirement"`
+	Tracked     bool   `json:"tracked"`
+	Cleared     *int   `json:"cleared"`
+	Remaining   *int   `json:"remaining"`
+	Complete    bool   `json:"complete"`
+}
+
+type VersionPlates struct {
+	Version string         `json:"version"`
+	Charts  int            `json:"charts"`
+	Plates  []PlateSummary `json:"plates"`
+}
+
+type PlateProgress struct {
+	PlateSummary
+	ByDifficulty    []PlateDifficulty `json:"byDifficulty"`
+	RemainingCharts []PlateChart      `json:"remainingCharts"`
+}
+
+type PlateDifficulty struct {
+	Difficulty string `json:"difficulty"`
+	Cleared    int    `json:"cleared"`
+	Total      int    `json:"total"`
+}
+
+type VersionPlateProgress struct {
+	Version string          `json:"version"`
+	Charts  int             `json:"charts"`
+	Plates  []PlateProgress `json:"plates"`
+}
+
+// accuracy and lamp are the user's best, null when unplayed or without a lamp
+type PlateChart struct {
+	BeatmapID     uuid.UUID `json:"beatmapID"`
+	SongID        uuid.UUID `json:"songID"`
+	Title         string    `json:"title"`
+	ImageUrl      string    `json:"imageUrl"`
+	Difficulty    string    `json:"difficulty"`
+	Level         string    `json:"level"`
+	InternalLevel *float64  `json:"internalLevel"`
+	Type          string    `json:"type"`
+	Accuracy      *string   `json:"accuracy"`
+	Lamp          *string   `json:"lamp"`
+}
+
+func NewPlateChart(c database.GetPlateChartsByUserIDRow) PlateChart {
+	var lamp *string
+	if c.Lamp.Valid && calculator.Lamp(c.Lamp.Int32) != calculator.LampNone {
+		name := calculator.Lamp(c.Lamp.Int32).String()
+		lamp = &name
+	}
+	return PlateChart{
+		BeatmapID:     c.BeatmapID,
+		SongID:        c.SongID,
+		Title:         c.Title,
+		ImageUrl:      c.ImageUrl,
+		Difficulty:    c.Difficulty,
+		Level:         c.Level,
+		InternalLevel: numeric(c.InternalLevel),
+		Type:          c.Type,
+		Accuracy:      text(c.Accuracy),
+		Lamp:          lamp,
+	}
+}
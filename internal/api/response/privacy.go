@@ -0,0 +1,19 @@
+package response
+
+import (
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+)
+
+type Privacy struct {
+	ProfileVisibility       string `json:"profileVisibility"`
+	ScoresVisibility        string `json:"scoresVisibility"`
+	RatingHistoryVisibility string `json:"ratingHistoryVisibility"`
+}
+
+func NewPrivacy(p database.GetUserPrivacyByUserIDRow) Privacy {
+	return Privacy{
+		ProfileVisibility:       p.ProfileVisibility,
+		ScoresVisibility:        p.ScoresVisibility,
+		RatingHistoryVisibility: p.RatingHistoryVisibility,
+	}
+}
@@ -0,0 +1,62 @@
+package response
+
+import (
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// rating is the total of the user's current rating frame
+type Recommendations struct {
+	Rating          int32            `json:"rating"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+// reachRate is the share of recent plays at a similar level that reached
+// requiredAchievement, null without any
+type Recommendation struct {
+	BeatmapID           uuid.UUID `json:"beatmapID"`
+	SongID              uuid.UUID `json:"songID"`
+	Title               string    `json:"title"`
+	ImageUrl            string    `json:"imageUrl"`
+	Difficulty          string    `json:"difficulty"`
+	Level               string    `json:"level"`
+	InternalLevel       float64   `json:"internalLevel"`
+	Type                string    `json:"type"`
+	IsNew               bool      `json:"isNew"`
+	Accuracy            string    `json:"accuracy"`
+	Rank                string    `json:"rank"`
+	Rating              int32     `json:"rating"`
+	TargetRank          string    `json:"targetRank"`
+	RequiredAchievement float64   `json:"requiredAchievement"`
+	TargetRating        int32     `json:"targetRating"`
+	Gain                int32     `json:"gain"`
+	ReachRate           *float64  `json:"reachRate"`
+	Samples             int       `json:"samples"`
+}
+
+func NewRecommendation(best database.GetPersonalBestsByUserIDRow, r calculator.Recommendation) Recommendation {
+	rec := Recommendation{
+		BeatmapID:           best.BeatmapID,
+		SongID:              best.SongID,
+		Title:               best.Title,
+		ImageUrl:            best.ImageUrl,
+		Difficulty:          best.Difficulty,
+		Level:               best.Level,
+		InternalLevel:       r.Chart.InternalLevel,
+		Type:                best.Type,
+		IsNew:               best.IsNew,
+		Accuracy:            best.Accuracy,
+		Rank:                r.Rank.Name,
+		Rating:              r.Chart.Rating(),
+		TargetRank:          r.Target.Name,
+		RequiredAchievement: r.Target.Achievement,
+		TargetRating:        calculator.Rating(r.Chart.InternalLevel, r.Target.Achievement),
+		Gain:                r.Gain,
+		Samples:             r.Samples,
+	}
+	if r.Samples > 0 {
+		rec.ReachRate = &r.ReachRate
+	}
+	return rec
+}
@@ -0,0 +1,71 @@
+// Package response defines the JSON shapes returned by the API.
+//
+// Handlers must convert sqlc rows into these types instead of serializing
+// database structs directly, so columns such as password hashes or
+// encrypted SEGA credentials can never leak into a response and the API
+// contract does not change whenever a query does.
+package response
+
+import (
+	"time"
+
+	"github.com/asashakira/maitrack/internal/calculator"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func text(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func timestamp(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time.UTC()
+	return &t
+}
+
+// dates are rendered as "2006-01-02"
+func date(d pgtype.Date) *string {
+	if !d.Valid {
+		return nil
+	}
+	s := d.Time.Format("2006-01-02")
+	return &s
+}
+
+func int4(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func numeric(n pgtype.Numeric) *float64 {
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+func uuidOrNil(u pgtype.UUID) *uuid.UUID {
+	if !u.Valid {
+		return nil
+	}
+	id := uuid.UUID(u.Bytes)
+	return &id
+}
+
+// nil when the max DX score of the chart is not known
+func dxStars(dxScore, maxDxScore int32) *int {
+	stars, ok := calculator.DxStars(dxScore, maxDxScore)
+	if !ok {
+		return nil
+	}
+	return &stars
+}
@@ -0,0 +1,104 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+type Rival struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      string     `json:"userID"`
+	DisplayName string     `json:"displayName"`
+	Rating      *int32     `json:"rating"`
+	AddedAt     *time.Time `json:"addedAt"`
+}
+
+func NewRivals(rivals []database.GetRivalsByUserIDRow) []Rival {
+	out := make([]Rival, 0, len(rivals))
+	for _, r := range rivals {
+		out = append(out, Rival{
+			ID:          r.ID,
+			UserID:      r.UserID,
+			DisplayName: r.DisplayName,
+			Rating:      int4(r.Rating),
+			AddedAt:     timestamp(r.CreatedAt),
+		})
+	}
+	return out
+}
+
+// charts are sorted by internal level, hardest first
+type HeadToHead struct {
+	UserID       string            `json:"userID"`
+	RivalID      string            `json:"rivalID"`
+	Summary      HeadToHeadCounts  `json:"summary"`
+	ByDifficulty []HeadToHeadGroup `json:"byDifficulty"`
+	ByLevel      []HeadToHeadGroup `json:"byLevel"`
+	Charts       []ChartComparison `json:"charts"`
+}
+
+// wins and losses are from the user's side, onlyUser and onlyRival count
+// the charts only one of them has played
+type HeadToHeadCounts struct {
+	Wins      int `json:"wins"`
+	Losses    int `json:"losses"`
+	Ties      int `json:"ties"`
+	OnlyUser  int `json:"onlyUser"`
+	OnlyRival int `json:"onlyRival"`
+}
+
+type HeadToHeadGroup struct {
+	Group string `json:"group"`
+	HeadToHeadCounts
+}
+
+// leader is "user", "rival" or "tie" when both played the chart, margin
+// is the user's achievement minus the rival's
+type ChartComparison struct {
+	BeatmapID     uuid.UUID     `json:"beatmapID"`
+	SongID        uuid.UUID     `json:"songID"`
+	Title         string        `json:"title"`
+	ImageUrl      string        `json:"imageUrl"`
+	Difficulty    string        `json:"difficulty"`
+	Level         string        `json:"level"`
+	InternalLevel *float64      `json:"internalLevel"`
+	Type          string        `json:"type"`
+	User          *PersonalBest `json:"user"`
+	Rival         *PersonalBest `json:"rival"`
+	Leader        *string       `json:"leader"`
+	Margin        *float64      `json:"margin"`
+}
+
+// dxStars is null when the max DX score of the chart is not known
+type PersonalBest struct {
+	ScoreID  uuid.UUID  `json:"scoreID"`
+	Accuracy string     `json:"accuracy"`
+	DxScore  int32      `json:"dxScore"`
+	DxStars  *int       `json:"dxStars"`
+	PlayedAt *time.Time `json:"playedAt"`
+}
+
+func NewPersonalBest(b database.GetPersonalBestsByUserIDRow) *PersonalBest {
+	return &PersonalBest{
+		ScoreID:  b.ID,
+		Accuracy: b.Accuracy,
+		DxScore:  b.DxScore,
+		DxStars:  dxStars(b.DxScore, b.MaxDxScore),
+		PlayedAt: timestamp(b.PlayedAt),
+	}
+}
+
+func NewChartComparison(b database.GetPersonalBestsByUserIDRow) ChartComparison {
+	return ChartComparison{
+		BeatmapID:     b.BeatmapID,
+		SongID:        b.SongID,
+		Title:         b.Title,
+		ImageUrl:      b.ImageUrl,
+		Difficulty:    b.Difficulty,
+		Level:         b.Level,
+		InternalLevel: numeric(b.InternalLevel),
+		Type:          b.Type,
+	}
+}
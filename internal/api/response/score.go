@@ -0,0 +1,170 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// beatmap and song fields are only set when the score was queried with them
+type Score struct {
+	ID            uuid.UUID  `json:"id"`
+	BeatmapID     uuid.UUID  `json:"beatmapID"`
+	SongID        uuid.UUID  `json:"songID"`
+	Accuracy      string     `json:"accuracy"`
+	MaxCombo      int32      `json:"maxCombo"`
+	DxScore       int32      `json:"dxScore"`
+	TapCritical   int32      `json:"tapCritical"`
+	TapPerfect    int32      `json:"tapPerfect"`
+	TapGreat      int32      `json:"tapGreat"`
+	TapGood       int32      `json:"tapGood"`
+	TapMiss       int32      `json:"tapMiss"`
+	HoldCritical  int32      `json:"holdCritical"`
+	HoldPerfect   int32      `json:"holdPerfect"`
+	HoldGreat     int32      `json:"holdGreat"`
+	HoldGood      int32      `json:"holdGood"`
+	HoldMiss      int32      `json:"holdMiss"`
+	SlideCritical int32      `json:"slideCritical"`
+	SlidePerfect  int32      `json:"slidePerfect"`
+	SlideGreat    int32      `json:"slideGreat"`
+	SlideGood     int32      `json:"slideGood"`
+	SlideMiss     int32      `json:"slideMiss"`
+	TouchCritical int32      `json:"touchCritical"`
+	TouchPerfect  int32      `json:"touchPerfect"`
+	TouchGreat    int32      `json:"touchGreat"`
+	TouchGood     int32      `json:"touchGood"`
+	TouchMiss     int32      `json:"touchMiss"`
+	BreakCritical int32      `json:"breakCritical"`
+	BreakPerfect  int32      `json:"breakPerfect"`
+	BreakGreat    int32      `json:"breakGreat"`
+	BreakGood     int32      `json:"breakGood"`
+	BreakMiss     int32      `json:"breakMiss"`
+	Fast          int32      `json:"fast"`
+	Late          int32      `json:"late"`
+	PlayedAt      *time.Time `json:"playedAt"`
+	FlagReason    *string    `json:"flagReason,omitempty"`
+	Track         *int32     `json:"track"`
+	SessionID     *uuid.UUID `json:"sessionID"`
+	Title         string     `json:"title,omitempty"`
+	Artist        string     `json:"artist,omitempty"`
+	Genre         string     `json:"genre,omitempty"`
+	ImageUrl      string     `json:"imageUrl,omitempty"`
+	Version       string     `json:"version,omitempty"`
+	Difficulty    string     `json:"difficulty,omitempty"`
+	Level         string     `json:"level,omitempty"`
+	InternalLevel *float64   `json:"internalLevel,omitempty"`
+	Type          string     `json:"type,omitempty"`
+	MaxDxScore    *int32     `json:"maxDxScore,omitempty"`
+	DxStars       *int       `json:"dxStars,omitempty"`
+}
+
+func NewScore(s database.Score) Score {
+	return Score{
+		ID:            s.ID,
+		BeatmapID:     s.BeatmapID,
+		SongID:        s.SongID,
+		Accuracy:      s.Accuracy,
+		MaxCombo:      s.MaxCombo,
+		DxScore:       s.DxScore,
+		TapCritical:   s.TapCritical,
+		TapPerfect:    s.TapPerfect,
+		TapGreat:      s.TapGreat,
+		TapGood:       s.TapGood,
+		TapMiss:       s.TapMiss,
+		HoldCritical:  s.HoldCritical,
+		HoldPerfect:   s.HoldPerfect,
+		HoldGreat:     s.HoldGreat,
+		HoldGood:      s.HoldGood,
+		HoldMiss:      s.HoldMiss,
+		SlideCritical: s.SlideCritical,
+		SlidePerfect:  s.SlidePerfect,
+		SlideGreat:    s.SlideGreat,
+		SlideGood:     s.SlideGood,
+		SlideMiss:     s.SlideMiss,
+		TouchCritical: s.TouchCritical,
+		TouchPerfect:  s.TouchPerfect,
+		TouchGreat:    s.TouchGreat,
+		TouchGood:     s.TouchGood,
+		TouchMiss:     s.TouchMiss,
+		BreakCritical: s.BreakCritical,
+		BreakPerfect:  s.BreakPerfect,
+		BreakGreat:    s.BreakGreat,
+		BreakGood:     s.BreakGood,
+		BreakMiss:     s.BreakMiss,
+		Fast:          s.Fast,
+		Late:          s.Late,
+		PlayedAt:      timestamp(s.PlayedAt),
+		FlagReason:    text(s.FlagReason),
+		Track:         int4(s.Track),
+		SessionID:     uuidOrNil(s.SessionID),
+	}
+}
+
+func NewScoreWithBeatmap(s database.GetScoresByUserIDRow) Score {
+	score := NewScore(database.Score{
+		ID:            s.ID,
+		BeatmapID:     s.BeatmapID,
+		SongID:        s.SongID,
+		Accuracy:      s.Accuracy,
+		MaxCombo:      s.MaxCombo,
+		DxScore:       s.DxScore,
+		TapCritical:   s.TapCritical,
+		TapPerfect:    s.TapPerfect,
+		TapGreat:      s.TapGreat,
+		TapGood:       s.TapGood,
+		TapMiss:       s.TapMiss,
+		HoldCritical:  s.HoldCritical,
+		HoldPerfect:   s.HoldPerfect,
+		HoldGreat:     s.HoldGreat,
+		HoldGood:      s.HoldGood,
+		HoldMiss:      s.HoldMiss,
+		SlideCritical: s.SlideCritical,
+		SlidePerfect:  s.SlidePerfect,
+		SlideGreat:    s.SlideGreat,
+		SlideGood:     s.SlideGood,
+		SlideMiss:     s.SlideMiss,
+		TouchCritical: s.TouchCritical,
+		TouchPerfect:  s.TouchPerfect,
+		TouchGreat:    s.TouchGreat,
+		TouchGood:     s.TouchGood,
+		TouchMiss:     s.TouchMiss,
+		BreakCritical: s.BreakCritical,
+		BreakPerfect:  s.BreakPerfect,
+		BreakGreat:    s.BreakGreat,
+		BreakGood:     s.BreakGood,
+		BreakMiss:     s.BreakMiss,
+		Fast:          s.Fast,
+		Late:          s.Late,
+		PlayedAt:      s.PlayedAt,
+		FlagReason:    s.FlagReason,
+		Track:         s.Track,
+		SessionID:     s.SessionID,
+	})
+	score.Title = s.Title
+	score.Artist = s.Artist
+	score.Genre = s.Genre
+	score.ImageUrl = s.ImageUrl
+	score.Version = s.Version
+	score.Difficulty = s.Difficulty
+	score.Level = s.Level
+	score.InternalLevel = numeric(s.InternalLevel)
+	score.Type = s.Type
+	return score.WithDxStars(s.MaxDxScore)
+}
+
+// WithDxStars sets the DX stars the score earns on a chart with the given
+// max DX score
+func (s Score) WithDxStars(maxDxScore int32) Score {
+	s.MaxDxScore = &maxDxScore
+	s.DxStars = dxStars(s.DxScore, maxDxScore)
+	return s
+}
+
+func NewScoresWithBeatmap(scores []database.GetScoresByUserIDRow) []Score {
+	out := make([]Score, 0, len(scores))
+	for _, s := range scores {
+		out = append(out, NewScoreWithBeatmap(s))
+	}
+	return out
+}
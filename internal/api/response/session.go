@@ -0,0 +1,67 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// duration is from the first to the last play, ratings are the closest
+// snapshots before and after the session
+type Session struct {
+	ID              uuid.UUID  `json:"id"`
+	StartedAt       *time.Time `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationSeconds int64      `json:"durationSeconds"`
+	Plays           int32      `json:"plays"`
+	Credits         int32      `json:"credits"`
+	Charts          int32      `json:"charts"`
+	PersonalBests   int32      `json:"personalBests"`
+	RatingBefore    *int32     `json:"ratingBefore"`
+	RatingAfter     *int32     `json:"ratingAfter"`
+	RatingChange    *int32     `json:"ratingChange"`
+}
+
+func NewSession(s database.Session) Session {
+	session := Session{
+		ID:            s.ID,
+		StartedAt:     timestamp(s.StartedAt),
+		EndedAt:       timestamp(s.EndedAt),
+		Plays:         s.Plays,
+		Credits:       s.Credits,
+		Charts:        s.Charts,
+		PersonalBests: s.PersonalBests,
+		RatingBefore:  int4(s.RatingBefore),
+		RatingAfter:   int4(s.RatingAfter),
+	}
+	if s.StartedAt.Valid && s.EndedAt.Valid {
+		session.DurationSeconds = int64(s.EndedAt.Time.Sub(s.StartedAt.Time).Seconds())
+	}
+	if s.RatingBefore.Valid && s.RatingAfter.Valid {
+		change := s.RatingAfter.Int32 - s.RatingBefore.Int32
+		session.RatingChange = &change
+	}
+	return session
+}
+
+// WithoutRatings hides the rating change of a session from viewers who
+// may see the scores but not the rating history
+func (s Session) WithoutRatings() Session {
+	s.RatingBefore, s.RatingAfter, s.RatingChange = nil, nil, nil
+	return s
+}
+
+func NewSessions(sessions []database.Session) []Session {
+	out := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		out = append(out, NewSession(s))
+	}
+	return out
+}
+
+// a session with its plays oldest first
+type SessionDetail struct {
+	Session
+	Scores []Score `json:"scores"`
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"github.com/asashakira/maitrack/internal/calculator"
+	"github.com/google/uuid"
+)
+
+type NoteCounts struct {
+	Tap   int32 `json:"tap"`
+	Hold  int32 `json:"hold"`
+	Slide int32 `json:"slide"`
+	Touch int32 `json:"touch"`
+	Break int32 `json:"break"`
+}
+
+func NewNoteCounts(n calculator.Notes) NoteCounts {
+	return NoteCounts{Tap: n.Tap, Hold: n.Hold, Slide: n.Slide, Touch: n.Touch, Break: n.Break}
+}
+
+// achievement is a range because maimai DX NET does not tell break
+// grades apart, rank is earned by achievementMin
+type Simulation struct {
+	BeatmapID      uuid.UUID    `json:"beatmapID"`
+	AchievementMin float64      `json:"achievementMin"`
+	AchievementMax float64      `json:"achievementMax"`
+	Rank           string       `json:"rank"`
+	DxScore        int32        `json:"dxScore"`
+	MaxDxScore     int32        `json:"maxDxScore"`
+	Budgets        []RankBudget `json:"budgets"`
+}
+
+// how many more of each judgement a play can take and keep a rank
+type RankBudget struct {
+	Rank         string     `json:"rank"`
+	Achievement  float64    `json:"achievement"`
+	Great        NoteCounts `json:"great"`
+	Good         NoteCounts `json:"good"`
+	Miss         NoteCounts `json:"miss"`
+	BreakPerfect int32      `json:"breakPerfect"`
+}
+
+func NewRankBudget(r calculator.Rank, b calculator.Budget) RankBudget {
+	return RankBudget{
+		Rank:         r.Name,
+		Achievement:  r.Achievement,
+		Great:        NewNoteCounts(b.Great),
+		Good:         NewNoteCounts(b.Good),
+		Miss:         NewNoteCounts(b.Miss),
+		BreakPerfect: b.BreakPerfect,
+	}
+}
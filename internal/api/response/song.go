@@ -0,0 +1,172 @@
+package response
+
+import (
+	"encoding/json"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+type Song struct {
+	ID           uuid.UUID     `json:"id"`
+	Title        string        `json:"title"`
+	TitleKana    string        `json:"titleKana"`
+	TitleRomaji  string        `json:"titleRomaji"`
+	TitleEnglish *string       `json:"titleEnglish"`
+	Artist       string        `json:"artist"`
+	ArtistRomaji string        `json:"artistRomaji"`
+	Genre        string        `json:"genre"`
+	Bpm          string        `json:"bpm"`
+	ImageUrl     string        `json:"imageUrl"`
+	Version      string        `json:"version"`
+	IsUtage      bool          `json:"isUtage"`
+	IsAvailable  bool          `json:"isAvailable"`
+	IsNew        bool          `json:"isNew"`
+	ReleaseDate  *string       `json:"releaseDate"`
+	DeleteDate   *string       `json:"deleteDate"`
+	Beatmaps     []SongBeatmap `json:"beatmaps,omitempty"`
+}
+
+// beatmap nested in a song
+type SongBeatmap struct {
+	BeatmapID     uuid.UUID `json:"beatmapID"`
+	Difficulty    string    `json:"difficulty"`
+	Level         string    `json:"level"`
+	InternalLevel *float64  `json:"internalLevel"`
+	Type          string    `json:"type"`
+	TotalNotes    int32     `json:"totalNotes"`
+	Tap           int32     `json:"tap"`
+	Hold          int32     `json:"hold"`
+	Slide         int32     `json:"slide"`
+	Touch         int32     `json:"touch"`
+	Break         int32     `json:"break"`
+	NoteDesigner  string    `json:"noteDesigner"`
+	MaxDxScore    int32     `json:"maxDxScore"`
+}
+
+func NewSong(s database.Song) Song {
+	return Song{
+		ID:           s.ID,
+		Title:        s.Title,
+		TitleKana:    s.TitleKana,
+		TitleRomaji:  s.TitleRomaji,
+		TitleEnglish: text(s.TitleEnglish),
+		Artist:       s.Artist,
+		ArtistRomaji: s.ArtistRomaji,
+		Genre:        s.Genre,
+		Bpm:          s.Bpm,
+		ImageUrl:     s.ImageUrl,
+		Version:      s.Version,
+		IsUtage:      s.IsUtage,
+		IsAvailable:  s.IsAvailable,
+		IsNew:        s.IsNew,
+		ReleaseDate:  date(s.ReleaseDate),
+		DeleteDate:   date(s.DeleteDate),
+	}
+}
+
+func NewSongs(songs []database.Song) []Song {
+	out := make([]Song, 0, len(songs))
+	for _, s := range songs {
+		out = append(out, NewSong(s))
+	}
+	return out
+}
+
+func NewSongWithBeatmaps(s database.GetSongByIDRow) Song {
+	return Song{
+		ID:           s.ID,
+		Title:        s.Title,
+		TitleKana:    s.TitleKana,
+		TitleRomaji:  s.TitleRomaji,
+		TitleEnglish: text(s.TitleEnglish),
+		Artist:       s.Artist,
+		ArtistRomaji: s.ArtistRomaji,
+		Genre:        s.Genre,
+		Bpm:          s.Bpm,
+		ImageUrl:     s.ImageUrl,
+		Version:      s.Version,
+		IsUtage:      s.IsUtage,
+		IsAvailable:  s.IsAvailable,
+		IsNew:        s.IsNew,
+		ReleaseDate:  date(s.ReleaseDate),
+		DeleteDate:   date(s.DeleteDate),
+		Beatmaps:     songBeatmaps(s.Beatmaps),
+	}
+}
+
+func NewSongsWithBeatmaps(songs []database.ListSongsRow) []Song {
+	out := make([]Song, 0, len(songs))
+	for _, s := range songs {
+		out = append(out, NewSongWithBeatmaps(database.GetSongByIDRow{
+			ID:           s.ID,
+			Title:        s.Title,
+			TitleKana:    s.TitleKana,
+			TitleRomaji:  s.TitleRomaji,
+			TitleEnglish: s.TitleEnglish,
+			Artist:       s.Artist,
+			ArtistRomaji: s.ArtistRomaji,
+			Genre:        s.Genre,
+			Bpm:          s.Bpm,
+			ImageUrl:     s.ImageUrl,
+			Version:      s.Version,
+			IsUtage:      s.IsUtage,
+			IsAvailable:  s.IsAvailable,
+			IsNew:        s.IsNew,
+			ReleaseDate:  s.ReleaseDate,
+			DeleteDate:   s.DeleteDate,
+			Beatmaps:     s.Beatmaps,
+		}))
+	}
+	return out
+}
+
+// beatmaps are aggregated with json_agg in the song queries
+// so re-decode them into a typed slice
+func songBeatmaps(v any) []SongBeatmap {
+	beatmaps := []SongBeatmap{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return beatmaps
+	}
+	if err := json.Unmarshal(data, &beatmaps); err != nil {
+		return []SongBeatmap{}
+	}
+	return beatmaps
+}
+
+// song found by search, score is in [0, 1]
+type SongSearchResult struct {
+	Song
+	Score     float64 `json:"score"`
+	MatchedBy string  `json:"matchedBy"`
+}
+
+func NewSongSearchResults(songs []database.SearchSongsRow) []SongSearchResult {
+	out := make([]SongSearchResult, 0, len(songs))
+	for _, s := range songs {
+		out = append(out, SongSearchResult{
+			Song: Song{
+				ID:           s.ID,
+				Title:        s.Title,
+				TitleKana:    s.TitleKana,
+				TitleRomaji:  s.TitleRomaji,
+				TitleEnglish: text(s.TitleEnglish),
+				Artist:       s.Artist,
+				ArtistRomaji: s.ArtistRomaji,
+				Genre:        s.Genre,
+				Bpm:          s.Bpm,
+				ImageUrl:     s.ImageUrl,
+				Version:      s.Version,
+				IsUtage:      s.IsUtage,
+				IsAvailable:  s.IsAvailable,
+				IsNew:        s.IsNew,
+				ReleaseDate:  date(s.ReleaseDate),
+				DeleteDate:   date(s.DeleteDate),
+			},
+			Score:     s.Score,
+			MatchedBy: s.MatchedBy,
+		})
+	}
+	return out
+}
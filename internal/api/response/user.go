@@ -0,0 +1,95 @@
+package response
+
+import (
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+)
+
+// entry of the users list
+type UserSummary struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       string     `json:"userID"`
+	DisplayName  string     `json:"displayName"`
+	LastPlayedAt *time.Time `json:"lastPlayedAt"`
+	Rating       *int32     `json:"rating"`
+}
+
+func NewUserSummary(u database.ListUsersRow) UserSummary {
+	return UserSummary{
+		ID:           u.ID,
+		UserID:       u.UserID,
+		DisplayName:  u.DisplayName,
+		LastPlayedAt: timestamp(u.LastPlayedAt),
+		Rating:       int4(u.Rating),
+	}
+}
+
+func NewUserSummaries(users []database.ListUsersRow) []UserSummary {
+	out := make([]UserSummary, 0, len(users))
+	for _, u := range users {
+		out = append(out, NewUserSummary(u))
+	}
+	return out
+}
+
+type User struct {
+	ID              uuid.UUID  `json:"id"`
+	UserID          string     `json:"userID"`
+	DisplayName     string     `json:"displayName"`
+	LastPlayedAt    *time.Time `json:"lastPlayedAt"`
+	LastScrapedAt   *time.Time `json:"lastScrapedAt"`
+	ScrapeStatus    *string    `json:"scrapeStatus"`
+	Rating          int32      `json:"rating"`
+	SeasonPlayCount int32      `json:"seasonPlayCount"`
+	TotalPlayCount  int32      `json:"totalPlayCount"`
+	Bio             *string    `json:"bio"`
+	ProfileImageUrl *string    `json:"profileImageUrl"`
+	Location        *string    `json:"location"`
+	TwitterID       *string    `json:"twitterID"`
+}
+
+func NewUser(u database.GetUserByUserIDRow) User {
+	return User{
+		ID:              u.ID,
+		UserID:          u.UserID,
+		DisplayName:     u.DisplayName,
+		LastPlayedAt:    timestamp(u.LastPlayedAt),
+		LastScrapedAt:   timestamp(u.LastScrapedAt),
+		ScrapeStatus:    text(u.ScrapeStatus),
+		Rating:          u.Rating,
+		SeasonPlayCount: u.SeasonPlayCount,
+		TotalPlayCount:  u.TotalPlayCount,
+		Bio:             text(u.Bio),
+		ProfileImageUrl: text(u.ProfileImageUrl),
+		Location:        text(u.Location),
+		TwitterID:       text(u.TwitterID),
+	}
+}
+
+type Profile struct {
+	UserID          string  `json:"userID"`
+	DisplayName     string  `json:"displayName"`
+	Bio             *string `json:"bio"`
+	Location        *string `json:"location"`
+	TwitterID       *string `json:"twitterID"`
+	ProfileImageUrl *string `json:"profileImageUrl"`
+	Rating          int32   `json:"rating"`
+	SeasonPlayCount int32   `json:"seasonPlayCount"`
+	TotalPlayCount  int32   `json:"totalPlayCount"`
+}
+
+func NewProfile(u database.GetUserByUserIDRow) Profile {
+	return Profile{
+		UserID:          u.UserID,
+		DisplayName:     u.DisplayName,
+		Bio:             text(u.Bio),
+		Location:        text(u.Location),
+		TwitterID:       text(u.TwitterID),
+		ProfileImageUrl: text(u.ProfileImageUrl),
+		Rating:          u.Rating,
+		SeasonPlayCount: u.SeasonPlayCount,
+		TotalPlayCount:  u.TotalPlayCount,
+	}
+}
@@ -0,0 +1,108 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+)
+
+// populated returns a T with every exported field set, so a response
+// that copies a column cannot hide it behind omitempty
+func populated[T any]() T {
+	var v T
+	fill(reflect.ValueOf(&v).Elem())
+	return v
+}
+
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint8:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fill(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	}
+}
+
+// keys of a user that must never be serialized
+var secretKeys = []string{"passwordHash", "encryptedSegaID", "encryptedSegaPassword"}
+
+func jsonKeys(v any, keys map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			keys[k] = true
+			jsonKeys(child, keys)
+		}
+	case []any:
+		for _, child := range v {
+			jsonKeys(child, keys)
+		}
+	}
+}
+
+func TestUserResponsesHideSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"NewUser", NewUser(populated[database.GetUserByUserIDRow]())},
+		{"NewProfile", NewProfile(populated[database.GetUserByUserIDRow]())},
+		{"NewUserSummary", NewUserSummary(populated[database.ListUsersRow]())},
+		{"NewUserSummaries", NewUserSummaries([]database.ListUsersRow{populated[database.ListUsersRow]()})},
+		{"NewFollowing", NewFollowing([]database.GetFollowingByUserIDRow{populated[database.GetFollowingByUserIDRow]()})},
+		{"NewFollowers", NewFollowers([]database.GetFollowersByUserIDRow{populated[database.GetFollowersByUserIDRow]()})},
+		{"NewPrivacy", NewPrivacy(populated[database.GetUserPrivacyByUserIDRow]())},
+		{"NewRivals", NewRivals([]database.GetRivalsByUserIDRow{populated[database.GetRivalsByUserIDRow]()})},
+		{"NewBeatmapLeaderboardEntry", NewBeatmapLeaderboardEntry(populated[database.GetBeatmapLeaderboardEntryRow](), "x")},
+		{"NewRatingLeaderboardEntry", NewRatingLeaderboardEntry(populated[database.GetRatingLeaderboardEntryRow](), "x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var decoded any
+			if err := json.Unmarshal(b, &decoded); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			keys := map[string]bool{}
+			jsonKeys(decoded, keys)
+			if len(keys) == 0 {
+				t.Fatalf("no fields serialized: %s", b)
+			}
+			for _, secret := range secretKeys {
+				if keys[secret] {
+					t.Errorf("%s is serialized: %s", secret, b)
+				}
+			}
+			for k := range keys {
+				lower := strings.ToLower(k)
+				if strings.Contains(lower, "password") || strings.Contains(lower, "encrypted") {
+					t.Errorf("%s looks like a secret: %s", k, b)
+				}
+			}
+		})
+	}
+}
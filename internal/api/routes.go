@@ -1,18 +1,38 @@
 package api
 
 import (
+	"time"
+
 	"github.com/asashakira/maitrack/internal/api/handler"
 	"github.com/asashakira/maitrack/internal/api/middleware"
+	"github.com/asashakira/maitrack/internal/service"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 )
 
+var (
+	loginLimits = []middleware.RateLimit{
+		middleware.PerIP("login-ip", 20, time.Minute),
+		middleware.PerUserID("login-user", 10, time.Minute),
+	}
+
+	// these routes log in to maimai DX NET, keep them far below what SEGA would notice
+	registerLimits = []middleware.RateLimit{
+		middleware.PerIP("register-ip", 5, time.Hour),
+	}
+	segaLimits = []middleware.RateLimit{
+		middleware.PerCaller("sega-caller", 10, time.Hour),
+		middleware.PerTargetUserID("sega-user", 3, time.Hour),
+	}
+)
+
 func SetUpRoutes(r *chi.Mux, h *handler.Handler, m *middleware.Middleware) {
+	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://maitrack.asashakira.dev", "http://localhost:3000"},
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -21,36 +41,91 @@ func SetUpRoutes(r *chi.Mux, h *handler.Handler, m *middleware.Middleware) {
 
 	v1Router.Get("/healthz", handler.HealthCheck) 
 	v1Router.Get("/err", handler.ErrorCheck)
+	v1Router.Get("/openapi.json", handler.GetOpenAPISpec)
 
 	// auth routes
-	v1Router.Post("/auth/register", h.Register)
-	v1Router.Post("/auth/login", h.Login)
+	v1Router.Post("/auth/register", m.RateLimit(h.Register, registerLimits...))
+	v1Router.Post("/auth/login", m.RateLimit(h.Login, loginLimits...))
 	v1Router.Post("/auth/logout", h.Logout)
 	v1Router.Get("/auth/me", m.Auth(h.GetMe))
 	v1Router.Get("/users/healthz", m.Auth(h.GetUserHealthCheck))
 
+	// profile routes
+	v1Router.Get("/me/profile", m.Auth(h.GetMyProfile))
+	v1Router.Patch("/me/profile", m.Auth(h.UpdateMyProfile))
+	v1Router.Get("/users/{userID}/profile", m.OptionalAuth(h.GetUserProfile))
+
+	// privacy routes
+	v1Router.Get("/me/privacy", m.Auth(h.GetMyPrivacy))
+	v1Router.Patch("/me/privacy", m.Auth(h.UpdateMyPrivacy))
+
 	// user routes
 	v1Router.Get("/users", h.GetAllUsers)
-	v1Router.Get("/users/by-user-id/{userID}", h.GetUserByUserID)
-	v1Router.Post("/users/by-user-id/{userID}/update", h.UpdateUserByUserID)
+	v1Router.Get("/users/by-user-id/{userID}", m.OptionalAuth(h.GetUserByUserID))
+	v1Router.Post("/users/by-user-id/{userID}/update", m.OptionalAuth(m.RateLimit(h.UpdateUserByUserID, segaLimits...)))
 
 	// songs
 	v1Router.Get("/songs", h.GetAllSongs)
 	v1Router.Get("/songs/by-id/{id}", h.GetSongByID)
 	v1Router.Get("/songs/by-altkey/{altkey}", h.GetSongByAltKey)
 	v1Router.Get("/songs/by-title/{title}", h.GetSongsByTitle)
+	v1Router.Get("/songs/search", h.SearchSongs)
 	v1Router.Post("/songs", h.CreateSong)
 	v1Router.Patch("/songs", h.UpdateSong)
 
+	// song aliases
+	v1Router.Get("/songs/by-id/{id}/aliases", h.GetSongAliases)
+	v1Router.Post("/songs/by-id/{id}/aliases", m.RequireRole(service.RoleCurator, h.CreateSongAlias))
+	v1Router.Patch("/songs/by-id/{id}/title-english", m.RequireRole(service.RoleCurator, h.UpdateSongTitleEnglish))
+	v1Router.Patch("/aliases/{aliasID}", m.RequireRole(service.RoleCurator, h.UpdateSongAlias))
+	v1Router.Delete("/aliases/{aliasID}", m.RequireRole(service.RoleCurator, h.DeleteSongAlias))
+
 	// beatmaps
 	v1Router.Get("/beatmaps", h.GetAllBeatmaps)
 	v1Router.Get("/beatmaps/by-song-id/{songID}", h.GetBeatmapsBySongID)
 	v1Router.Post("/beatmaps", h.CreateBeatmap)
 	v1Router.Patch("/beatmaps", h.UpdateBeatmap)
+	v1Router.Post("/beatmaps/{id}/simulate", h.SimulateBeatmap)
+	v1Router.Get("/beatmaps/{id}/leaderboard", m.OptionalAuth(h.GetBeatmapLeaderboard))
 
 	// scores
-	v1Router.Get("/users/by-user-id/{userID}/scores", h.GetScoresByUserID)
+	v1Router.Get("/users/by-user-id/{userID}/scores", m.OptionalAuth(h.GetScoresByUserID))
 	v1Router.Post("/scores", h.CreateScore)
 
+	// sessions
+	v1Router.Get("/users/by-user-id/{userID}/sessions", m.OptionalAuth(h.GetSessionsByUserID))
+	v1Router.Get("/users/by-user-id/{userID}/sessions/{sessionID}", m.OptionalAuth(h.GetSession))
+
+	// user stats
+	v1Router.Get("/users/by-user-id/{userID}/recommendations", m.OptionalAuth(h.GetRecommendations))
+	v1Router.Get("/users/by-user-id/{userID}/history", m.OptionalAuth(h.GetRatingHistory))
+	v1Router.Get("/users/by-user-id/{userID}/analytics", m.OptionalAuth(h.GetAnalytics))
+	v1Router.Get("/users/by-user-id/{userID}/activity", m.OptionalAuth(h.GetActivity))
+	v1Router.Get("/users/by-user-id/{userID}/plates", m.OptionalAuth(h.GetPlates))
+	v1Router.Get("/users/by-user-id/{userID}/level-table", m.OptionalAuth(h.GetLevelTable))
+	v1Router.Get("/users/by-user-id/{userID}/dx-stars", m.OptionalAuth(h.GetDxStars))
+	v1Router.Get("/users/by-user-id/{userID}/plates/{version}", m.OptionalAuth(h.GetVersionPlates))
+	v1Router.Get("/leaderboards/rating", m.OptionalAuth(h.GetRatingLeaderboard))
+
+	// rivals
+	v1Router.Get("/users/by-user-id/{userID}/rivals", m.OptionalAuth(h.GetRivals))
+	v1Router.Get("/users/by-user-id/{userID}/compare/{rivalID}", m.OptionalAuth(h.CompareUsers))
+	v1Router.Post("/me/rivals", m.Auth(h.AddMyRival))
+	v1Router.Delete("/me/rivals/{userID}", m.Auth(h.RemoveMyRival))
+
+	// follows
+	v1Router.Get("/users/by-user-id/{userID}/following", m.OptionalAuth(h.GetFollowing))
+	v1Router.Get("/users/by-user-id/{userID}/followers", m.OptionalAuth(h.GetFollowers))
+	v1Router.Post("/me/follows", m.Auth(h.FollowUser))
+	v1Router.Delete("/me/follows/{userID}", m.Auth(h.UnfollowUser))
+	v1Router.Get("/me/feed", m.Auth(h.GetMyFeed))
+
+	// goals
+	v1Router.Get("/me/goals", m.Auth(h.GetMyGoals))
+	v1Router.Post("/me/goals", m.Auth(h.CreateMyGoal))
+	v1Router.Get("/me/goals/{goalID}", m.Auth(h.GetMyGoal))
+	v1Router.Patch("/me/goals/{goalID}", m.Auth(h.UpdateMyGoal))
+	v1Router.Delete("/me/goals/{goalID}", m.Auth(h.DeleteMyGoal))
+
 	r.Mount("/v1", v1Router)
 }
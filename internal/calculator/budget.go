@@ -0,0 +1,59 @@
+package calculator
+
+import "math"
+
+// Budget is how many more of a judgement a play can take and still reach
+// a target achievement. Each count is on its own, with every other note
+// left as it is, and at most the CRITICAL PERFECTs of that note type.
+type Budget struct {
+	Great Notes
+	Good  Notes
+	Miss  Notes
+	// PERFECT breaks of the lowest grade
+	BreakPerfect int32
+}
+
+// Budget returns what j can still afford while staying at or above
+// target, false when even j itself falls short of it. Break grades are
+// assumed to be the lowest so the budget is always safe.
+func (j Judgements) Budget(notes Notes, target float64) (Budget, bool) {
+	low, _ := j.Achievement()
+	margin := low - target
+	if margin < -epsilon {
+		return Budget{}, false
+	}
+
+	// achievement lost by turning one CRITICAL PERFECT of a note with
+	// this weight into a judgement worth base, and bonus if it is a break
+	perNote := 100 / float64(notes.weight())
+	perBreak := 0.0
+	if notes.Break > 0 {
+		perBreak = 1 / float64(notes.Break)
+	}
+	afford := func(loss float64, critical int32) int32 {
+		if loss <= 0 {
+			return critical
+		}
+		n := int32(math.Floor(margin/loss + epsilon))
+		return min(max(n, 0), critical)
+	}
+	budget := func(base float64) Notes {
+		return Notes{
+			Tap:   afford((1-base)*tapWeight*perNote, j.Tap.Critical),
+			Hold:  afford((1-base)*holdWeight*perNote, j.Hold.Critical),
+			Slide: afford((1-base)*slideWeight*perNote, j.Slide.Critical),
+			Touch: afford((1-base)*touchWeight*perNote, j.Touch.Critical),
+		}
+	}
+
+	b := Budget{
+		Great:        budget(greatBase),
+		Good:         budget(goodBase),
+		Miss:         budget(0),
+		BreakPerfect: afford((1-breakPerfectBonus[0])*perBreak, j.Break.Critical),
+	}
+	b.Great.Break = afford((1-breakGreatBase[0])*breakWeight*perNote+(1-breakGreatBonus)*perBreak, j.Break.Critical)
+	b.Good.Break = afford((1-breakGoodBase)*breakWeight*perNote+(1-breakGoodBonus)*perBreak, j.Break.Critical)
+	b.Miss.Break = afford(breakWeight*perNote+perBreak, j.Break.Critical)
+	return b, true
+}
@@ -0,0 +1,173 @@
+// Package calculator recomputes maimai DX achievement and DX score from
+// judgement counts and a beatmap's note counts.
+//
+// Every note is worth a multiple of a tap: hold 2, slide 3, break 5. The
+// notes' base score makes up 100% of the achievement and breaks share an
+// extra 1% bonus, so the maximum is 101%. maimai DX NET only shows five
+// judgement columns while breaks have finer grades (PERFECT is 2500 with
+// 75% or 50% bonus, GREAT is 2000, 1500 or 1250), so achievement can only
+// be recomputed as a range.
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+)
+
+// weight of each note type in taps
+const (
+	tapWeight   = 1
+	holdWeight  = 2
+	slideWeight = 3
+	touchWeight = 1
+	breakWeight = 5
+)
+
+// MaxAchievement is 100% base score plus the 1% break bonus.
+const MaxAchievement = 101
+
+type Notes struct {
+	Tap   int32
+	Hold  int32
+	Slide int32
+	Touch int32
+	Break int32
+}
+
+func (n Notes) Total() int32 {
+	return n.Tap + n.Hold + n.Slide + n.Touch + n.Break
+}
+
+// MaxDxScore is 3 points per note, all CRITICAL PERFECT.
+func (n Notes) MaxDxScore() int32 {
+	return 3 * n.Total()
+}
+
+// base score of a full CRITICAL PERFECT in taps
+func (n Notes) weight() int64 {
+	return int64(n.Tap)*tapWeight + int64(n.Hold)*holdWeight + int64(n.Slide)*slideWeight +
+		int64(n.Touch)*touchWeight + int64(n.Break)*breakWeight
+}
+
+// Judgement counts one note type, one field per column on maimai DX NET.
+type Judgement struct {
+	Critical int32
+	Perfect  int32
+	Great    int32
+	Good     int32
+	Miss     int32
+}
+
+func (j Judgement) Count() int32 {
+	return j.Critical + j.Perfect + j.Great + j.Good + j.Miss
+}
+
+// 3 per CRITICAL PERFECT, 2 per PERFECT and 1 per GREAT
+func (j Judgement) dxScore() int32 {
+	return 3*j.Critical + 2*j.Perfect + j.Great
+}
+
+type Judgements struct {
+	Tap   Judgement
+	Hold  Judgement
+	Slide Judgement
+	Touch Judgement
+	Break Judgement
+}
+
+func ScoreJudgements(s database.Score) Judgements {
+	return Judgements{
+		Tap:   Judgement{s.TapCritical, s.TapPerfect, s.TapGreat, s.TapGood, s.TapMiss},
+		Hold:  Judgement{s.HoldCritical, s.HoldPerfect, s.HoldGreat, s.HoldGood, s.HoldMiss},
+		Slide: Judgement{s.SlideCritical, s.SlidePerfect, s.SlideGreat, s.SlideGood, s.SlideMiss},
+		Touch: Judgement{s.TouchCritical, s.TouchPerfect, s.TouchGreat, s.TouchGood, s.TouchMiss},
+		Break: Judgement{s.BreakCritical, s.BreakPerfect, s.BreakGreat, s.BreakGood, s.BreakMiss},
+	}
+}
+
+// Notes are the note counts the judgements add up to.
+func (j Judgements) Notes() Notes {
+	return Notes{
+		Tap:   j.Tap.Count(),
+		Hold:  j.Hold.Count(),
+		Slide: j.Slide.Count(),
+		Touch: j.Touch.Count(),
+		Break: j.Break.Count(),
+	}
+}
+
+func (j Judgements) DxScore() int32 {
+	return j.Tap.dxScore() + j.Hold.dxScore() + j.Slide.dxScore() + j.Touch.dxScore() + j.Break.dxScore()
+}
+
+// score of a judgement as a fraction of a CRITICAL PERFECT, PERFECT
+// counts in full
+const (
+	greatBase = 0.8
+	goodBase  = 0.5
+	// breaks are scored differently and have a bonus on top
+	breakGoodBase   = 0.4
+	breakGreatBonus = 0.4
+	breakGoodBonus  = 0.3
+)
+
+// PERFECT and GREAT breaks have grades maimai DX NET does not tell apart,
+// index 0 is the lowest grade and 1 the highest
+var (
+	breakPerfectBonus = [2]float64{0.5, 0.75}
+	breakGreatBase    = [2]float64{0.5, 0.8}
+)
+
+// Achievement returns the lowest and highest achievement in percent the
+// judgements can be worth. They only differ when there are PERFECT or
+// GREAT breaks.
+func (j Judgements) Achievement() (low, high float64) {
+	notes := j.Notes()
+	if notes.Total() == 0 {
+		return 0, 0
+	}
+	return j.achievement(notes, 0), j.achievement(notes, 1)
+}
+
+// grade 0 picks the lowest break grades, 1 the highest
+func (j Judgements) achievement(notes Notes, grade int) float64 {
+	var base float64
+	for _, n := range []struct {
+		j      Judgement
+		weight float64
+	}{
+		{j.Tap, tapWeight},
+		{j.Hold, holdWeight},
+		{j.Slide, slideWeight},
+		{j.Touch, touchWeight},
+	} {
+		base += n.weight * (float64(n.j.Critical+n.j.Perfect) + greatBase*float64(n.j.Great) + goodBase*float64(n.j.Good))
+	}
+	b := j.Break
+	base += breakWeight * (float64(b.Critical+b.Perfect) + breakGreatBase[grade]*float64(b.Great) + breakGoodBase*float64(b.Good))
+
+	achievement := 100 * base / float64(notes.weight())
+	if notes.Break > 0 {
+		bonus := float64(b.Critical) + breakPerfectBonus[grade]*float64(b.Perfect) + breakGreatBonus*float64(b.Great) + breakGoodBonus*float64(b.Good)
+		achievement += bonus / float64(notes.Break)
+	}
+	return achievement
+}
+
+// ParseAchievement parses an achievement as shown in game, e.g. "100.5000%".
+func ParseAchievement(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || v < 0 || v > MaxAchievement {
+		return 0, fmt.Errorf("invalid achievement %q", s)
+	}
+	return v, nil
+}
+
+// Truncate cuts an achievement to the 4 decimals the game shows.
+func Truncate(achievement float64) float64 {
+	return math.Floor(achievement*10000+1e-6) / 10000
+}
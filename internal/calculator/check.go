@@ -0,0 +1,79 @@
+package calculator
+
+import "fmt"
+
+// Score is what a play claims, as shown on its record page.
+type Score struct {
+	Judgements Judgements
+	Accuracy   string
+	MaxCombo   int32
+	DxScore    int32
+}
+
+// Problem is one way a score does not add up. Field is the score field it
+// is about, named like in the API.
+type Problem struct {
+	Field   string
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Field + " " + p.Message
+}
+
+// parsed and recomputed achievements are floats, anything closer than
+// this is the same shown value
+const epsilon = 1e-9
+
+// Check recomputes achievement and DX score from the judgements and
+// compares them and the note counts to what the score claims. notes are the
+// beatmap's, zero when they are not known yet in which case the judgements
+// are trusted.
+func Check(s Score, notes Notes) []Problem {
+	var problems []Problem
+	judged := s.Judgements.Notes()
+
+	if notes.Total() == 0 {
+		notes = judged
+	}
+	for _, n := range []struct {
+		name          string
+		judged, chart int32
+	}{
+		{"tap", judged.Tap, notes.Tap},
+		{"hold", judged.Hold, notes.Hold},
+		{"slide", judged.Slide, notes.Slide},
+		{"touch", judged.Touch, notes.Touch},
+		{"break", judged.Break, notes.Break},
+	} {
+		if n.judged != n.chart {
+			problems = append(problems, Problem{"judgements", fmt.Sprintf("count %d %s notes but the beatmap has %d", n.judged, n.name, n.chart)})
+		}
+	}
+
+	if s.MaxCombo > notes.Total() {
+		problems = append(problems, Problem{"maxCombo", fmt.Sprintf("is higher than the %d notes of the beatmap", notes.Total())})
+	}
+
+	if s.DxScore > notes.MaxDxScore() {
+		problems = append(problems, Problem{"dxScore", fmt.Sprintf("is higher than the maximum of %d", notes.MaxDxScore())})
+	} else if dxScore := s.Judgements.DxScore(); s.DxScore != dxScore {
+		problems = append(problems, Problem{"dxScore", fmt.Sprintf("does not match the judgements, expected %d", dxScore)})
+	}
+
+	achievement, err := ParseAchievement(s.Accuracy)
+	if err != nil {
+		return append(problems, Problem{"accuracy", "is not an achievement"})
+	}
+	low, high := s.Judgements.Achievement()
+	low, high = Truncate(low), Truncate(high)
+	switch {
+	case achievement >= low-epsilon && achievement <= high+epsilon:
+	case low == high:
+		problems = append(problems, Problem{"accuracy", fmt.Sprintf("does not match the judgements, expected %.4f%%", low)})
+	default:
+		problems = append(problems, Problem{"accuracy", fmt.Sprintf("does not match the judgements, expected %.4f%% to %.4f%%", low, high)})
+	}
+
+	return problems
+}
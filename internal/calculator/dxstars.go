@@ -0,0 +1,22 @@
+package calculator
+
+// percent of the max DX score needed for each DX star
+var dxStarThresholds = []int64{85, 90, 93, 95, 97}
+
+// MaxDxStars is the most stars a play can earn.
+var MaxDxStars = len(dxStarThresholds)
+
+// DxStars returns the stars a DX score earns on a chart, false when the
+// max DX score of the chart is not known.
+func DxStars(dxScore, maxDxScore int32) (int, bool) {
+	if maxDxScore <= 0 {
+		return 0, false
+	}
+	stars := 0
+	for _, t := range dxStarThresholds {
+		if int64(dxScore)*100 >= t*int64(maxDxScore) {
+			stars++
+		}
+	}
+	return stars, true
+}
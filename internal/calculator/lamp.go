@@ -0,0 +1,50 @@
+package calculator
+
+// Lamp is the combo clear of a play, from worst to best: none, FC, FC+,
+// AP and AP+.
+type Lamp int
+
+const (
+	LampNone Lamp = iota
+	LampFC
+	LampFCPlus
+	LampAP
+	LampAPPlus
+)
+
+var lampNames = []string{"", "FC", "FC+", "AP", "AP+"}
+
+func (l Lamp) String() string {
+	return lampNames[l]
+}
+
+func ParseLamp(name string) (Lamp, bool) {
+	for i, n := range lampNames {
+		if n == name {
+			return Lamp(i), true
+		}
+	}
+	return LampNone, false
+}
+
+// Lamp of a play. GOOD keeps the combo, only MISS breaks it.
+func (j Judgements) Lamp() Lamp {
+	var great, good, miss, perfect int32
+	for _, n := range []Judgement{j.Tap, j.Hold, j.Slide, j.Touch, j.Break} {
+		perfect += n.Perfect
+		great += n.Great
+		good += n.Good
+		miss += n.Miss
+	}
+	switch {
+	case j.Notes().Total() == 0 || miss > 0:
+		return LampNone
+	case good > 0:
+		return LampFC
+	case great > 0:
+		return LampFCPlus
+	case perfect > 0:
+		return LampAP
+	}
+	return LampAPPlus
+}
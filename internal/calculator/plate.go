@@ -0,0 +1,32 @@
+package calculator
+
+// Plate is awarded for reaching Requirement on every BASIC to MASTER
+// chart of a version.
+type Plate struct {
+	Name        string
+	Requirement string
+	reached     func(achievement float64, lamp Lamp) bool
+}
+
+// Plates of a version. 舞舞 needs a FULL SYNC DX on every chart, sync is
+// not recorded so its progress cannot be told.
+var Plates = []Plate{
+	{"極", "FC", func(_ float64, lamp Lamp) bool { return lamp >= LampFC }},
+	{"将", "SSS", func(achievement float64, _ Lamp) bool { return RankOf(achievement).Achievement >= 100 }},
+	{"神", "AP", func(_ float64, lamp Lamp) bool { return lamp >= LampAP }},
+	{"舞舞", "FDX", nil},
+}
+
+// PlateDifficulties are the difficulties a plate covers.
+var PlateDifficulties = []string{"basic", "advanced", "expert", "master"}
+
+// Tracked tells whether the progress of the plate can be computed.
+func (p Plate) Tracked() bool {
+	return p.reached != nil
+}
+
+// Reached tells whether a chart's best achievement and lamp count towards
+// the plate, always false for untracked plates.
+func (p Plate) Reached(achievement float64, lamp Lamp) bool {
+	return p.reached != nil && p.reached(achievement, lamp)
+}
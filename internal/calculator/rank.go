@@ -0,0 +1,56 @@
+package calculator
+
+// Rank is earned from Achievement in percent up, Factor scales the
+// rating of charts played at this rank.
+type Rank struct {
+	Name        string
+	Achievement float64
+	Factor      float64
+}
+
+// Ranks from best to worst.
+var Ranks = []Rank{
+	{"SSS+", 100.5, 22.4},
+	{"SSS", 100, 21.6},
+	{"SS+", 99.5, 21.1},
+	{"SS", 99, 20.8},
+	{"S+", 98, 20.3},
+	{"S", 97, 20.0},
+	{"AAA", 94, 16.8},
+	{"AA", 90, 15.2},
+	{"A", 80, 13.6},
+	{"BBB", 75, 12.0},
+	{"BB", 70, 11.2},
+	{"B", 60, 9.6},
+	{"C", 50, 8.0},
+	{"D", 0, 0},
+}
+
+// RankOf returns the rank an achievement earns.
+func RankOf(achievement float64) Rank {
+	for _, r := range Ranks {
+		if achievement >= r.Achievement-epsilon {
+			return r
+		}
+	}
+	return Ranks[len(Ranks)-1]
+}
+
+func ParseRank(name string) (Rank, bool) {
+	for _, r := range Ranks {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rank{}, false
+}
+
+// NextRank returns the rank right above r, false for the best rank.
+func NextRank(r Rank) (Rank, bool) {
+	for i := range Ranks {
+		if Ranks[i].Name == r.Name && i > 0 {
+			return Ranks[i-1], true
+		}
+	}
+	return Rank{}, false
+}
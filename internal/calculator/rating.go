@@ -0,0 +1,112 @@
+package calculator
+
+import (
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// the rating frame counts the best charts of older versions and of the
+// current version separately
+const (
+	OldFrameSize = 35
+	NewFrameSize = 15
+)
+
+// Rating of one chart: internal level × achievement × the factor of its
+// rank, rounded down. Achievement above SSS+ does not count.
+func Rating(internalLevel, achievement float64) int32 {
+	achievement = min(achievement, Ranks[0].Achievement)
+	factor := RankOf(achievement).Factor
+	// in tenths of a level, 1/10000 of a percent and tenths of a factor
+	// so rounding down is exact
+	level := int64(math.Round(internalLevel * 10))
+	ach := int64(math.Round(achievement * 10000))
+	fac := int64(math.Round(factor * 10))
+	return int32(level * ach * fac / 100_000_000)
+}
+
+// Chart is a user's best play of a beatmap.
+type Chart struct {
+	BeatmapID     uuid.UUID
+	InternalLevel float64
+	Achievement   float64
+	IsNew         bool
+}
+
+func (c Chart) Rating() int32 {
+	return Rating(c.InternalLevel, c.Achievement)
+}
+
+// Frame is the charts that make up a user's rating, best first.
+type Frame struct {
+	Old []Chart
+	New []Chart
+}
+
+// NewFrame picks the best rated charts of each version group.
+func NewFrame(charts []Chart) Frame {
+	var f Frame
+	for _, c := range charts {
+		if c.IsNew {
+			f.New = append(f.New, c)
+		} else {
+			f.Old = append(f.Old, c)
+		}
+	}
+	f.Old = best(f.Old, OldFrameSize)
+	f.New = best(f.New, NewFrameSize)
+	return f
+}
+
+func best(charts []Chart, n int) []Chart {
+	slices.SortStableFunc(charts, func(a, b Chart) int {
+		return int(b.Rating() - a.Rating())
+	})
+	return charts[:min(n, len(charts))]
+}
+
+func (f Frame) Total() int32 {
+	var total int32
+	for _, c := range append(slices.Clone(f.Old), f.New...) {
+		total += c.Rating()
+	}
+	return total
+}
+
+// Gain is how much the frame's total would rise if c was played at
+// achievement.
+func (f Frame) Gain(c Chart, achievement float64) int32 {
+	group, size := f.Old, OldFrameSize
+	if c.IsNew {
+		group, size = f.New, NewFrameSize
+	}
+	improved := Rating(c.InternalLevel, achievement)
+
+	for _, in := range group {
+		if in.BeatmapID == c.BeatmapID {
+			return max(improved-in.Rating(), 0)
+		}
+	}
+	if len(group) < size {
+		return improved
+	}
+	// it would replace the worst chart of the frame
+	return max(improved-group[len(group)-1].Rating(), 0)
+}
+
+// ratings at which the rating plate changes color
+var RatingMilestones = []int32{1000, 2000, 4000, 7000, 10000, 12000, 13000, 14000, 14500, 15000}
+
+// RatingMilestone returns the highest milestone passed going from previous
+// to current, false if none was.
+func RatingMilestone(previous, current int32) (int32, bool) {
+	for i := len(RatingMilestones) - 1; i >= 0; i-- {
+		m := RatingMilestones[i]
+		if previous < m && m <= current {
+			return m, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package calculator
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// recent plays within this many levels of a chart's internal level tell
+// how often the user reaches an achievement on it
+const reachWindow = 0.3
+
+// Play is the internal level and achievement of one recent play.
+type Play struct {
+	InternalLevel float64
+	Achievement   float64
+}
+
+// Recommendation is a chart worth replaying: reaching Target from Rank
+// raises the rating frame by Gain. ReachRate is the share of the Samples
+// recent plays at a similar level that reached Target.
+type Recommendation struct {
+	Chart     Chart
+	Rank      Rank
+	Target    Rank
+	Gain      int32
+	ReachRate float64
+	Samples   int
+}
+
+// expected gain of one more play
+func (r Recommendation) expected() float64 {
+	return float64(r.Gain) * r.ReachRate
+}
+
+// Recommend lists the charts in bests whose next rank would raise the
+// rating frame, most likely gain first. Charts never played are left out,
+// there is no achievement to improve on.
+func Recommend(bests []Chart, recent []Play) []Recommendation {
+	frame := NewFrame(slices.Clone(bests))
+
+	var out []Recommendation
+	for _, c := range bests {
+		rank := RankOf(c.Achievement)
+		target, ok := NextRank(rank)
+		if !ok {
+			continue
+		}
+		gain := frame.Gain(c, target.Achievement)
+		if gain <= 0 {
+			continue
+		}
+		reached, samples := 0, 0
+		for _, p := range recent {
+			if math.Abs(p.InternalLevel-c.InternalLevel) > reachWindow+epsilon {
+				continue
+			}
+			samples++
+			if p.Achievement >= target.Achievement {
+				reached++
+			}
+		}
+		rec := Recommendation{Chart: c, Rank: rank, Target: target, Gain: gain, Samples: samples}
+		if samples > 0 {
+			rec.ReachRate = float64(reached) / float64(samples)
+		}
+		out = append(out, rec)
+	}
+
+	slices.SortStableFunc(out, func(a, b Recommendation) int {
+		return cmp.Or(
+			cmp.Compare(b.expected(), a.expected()),
+			cmp.Compare(b.Gain, a.Gain),
+			cmp.Compare(a.Target.Achievement-a.Chart.Achievement, b.Target.Achievement-b.Chart.Achievement),
+		)
+	})
+	return out
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+create table user_privacy (
+    user_uuid uuid primary key references users (id) on delete cascade,
+    profile_visibility text not null default 'public',
+    scores_visibility text not null default 'public',
+    rating_history_visibility text not null default 'public',
+    updated_at timestamp default now(),
+    created_at timestamp default now(),
+    check (profile_visibility in ('public', 'friends', 'private')),
+    check (scores_visibility in ('public', 'friends', 'private')),
+    check (rating_history_visibility in ('public', 'friends', 'private'))
+);
+
+-- existing users keep their data public
+insert into user_privacy (user_uuid)
+select id from users;
+
+-- +goose Down
+drop table if exists user_privacy;
@@ -0,0 +1,11 @@
+-- +goose Up
+create table rate_limits (
+    key text primary key,
+    window_start timestamp not null,
+    count int not null default 0
+);
+create index idx_rate_limits_window_start on rate_limits (window_start);
+
+-- +goose Down
+drop index if exists idx_rate_limits_window_start;
+drop table if exists rate_limits;
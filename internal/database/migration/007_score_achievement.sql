@@ -0,0 +1,12 @@
+-- +goose Up
+-- the accuracy as a number ("100.5000%" is 100.5000) so queries can sort,
+-- filter and page on it without parsing the text of every row
+alter table scores add column achievement numeric
+    generated always as (nullif(regexp_replace(accuracy, '[^0-9.]', '', 'g'), '')::numeric) stored;
+
+-- the achievement sort and range filter of a user's score history
+create index idx_scores_user_uuid_achievement on scores (user_uuid, achievement);
+
+-- +goose Down
+drop index if exists idx_scores_user_uuid_achievement;
+alter table scores drop column if exists achievement;
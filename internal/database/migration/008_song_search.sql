@@ -0,0 +1,41 @@
+-- +goose Up
+create extension if not exists pg_trgm;
+
+-- reading from the official feed, e.g. "ウミユリカイテイタン"
+alter table songs add column title_kana text not null default '';
+
+-- community-maintained nicknames such as "oshama" for "OSHAMA Scramble!"
+create table song_aliases (
+    id uuid primary key,
+    song_id uuid not null references songs (id) on delete cascade,
+    alias text not null,
+    created_by uuid references users (id) on delete set null,
+    updated_at timestamp default now(),
+    created_at timestamp default now(),
+    unique (song_id, alias)
+);
+create index idx_song_aliases_song_id on song_aliases (song_id);
+
+-- users without a row have no special role
+create table user_roles (
+    user_uuid uuid primary key references users (id) on delete cascade,
+    role text not null,
+    updated_at timestamp default now(),
+    created_at timestamp default now(),
+    check (role in ('curator', 'admin'))
+);
+
+create index idx_songs_title_trgm on songs using gin (title gin_trgm_ops);
+create index idx_songs_title_kana_trgm on songs using gin (title_kana gin_trgm_ops);
+create index idx_songs_artist_trgm on songs using gin (artist gin_trgm_ops);
+create index idx_song_aliases_alias_trgm on song_aliases using gin (alias gin_trgm_ops);
+create index idx_songs_fts on songs using gin (to_tsvector('simple', title || ' ' || artist));
+
+-- +goose Down
+drop index if exists idx_songs_fts;
+drop index if exists idx_songs_artist_trgm;
+drop index if exists idx_songs_title_kana_trgm;
+drop index if exists idx_songs_title_trgm;
+drop table if exists user_roles;
+drop table if exists song_aliases;
+alter table songs drop column if exists title_kana;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- generated from the title or kana reading by the song scraper,
+-- empty until its next run when the title has no reading
+alter table songs add column title_romaji text not null default '';
+alter table songs add column artist_romaji text not null default '';
+-- set by curators, null when there is no official or common English title
+alter table songs add column title_english text;
+
+create index idx_songs_title_romaji_trgm on songs using gin (title_romaji gin_trgm_ops);
+create index idx_songs_title_english_trgm on songs using gin (title_english gin_trgm_ops);
+create index idx_songs_artist_romaji_trgm on songs using gin (artist_romaji gin_trgm_ops);
+
+-- +goose Down
+drop index if exists idx_songs_artist_romaji_trgm;
+drop index if exists idx_songs_title_english_trgm;
+drop index if exists idx_songs_title_romaji_trgm;
+alter table songs drop column if exists title_english;
+alter table songs drop column if exists artist_romaji;
+alter table songs drop column if exists title_romaji;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- set on scraped scores whose judgements do not add up to what the
+-- record page shows, null when the score is consistent
+alter table scores add column flag_reason text;
+
+-- +goose Down
+alter table scores drop column if exists flag_reason;
@@ -0,0 +1,103 @@
+-- +goose Up
+-- a visit to the arcade: plays of a user less than 30 minutes apart.
+-- the summary columns are refreshed whenever a play is added.
+create table sessions (
+    id uuid primary key,
+    user_uuid uuid not null references users (id) on delete cascade,
+    started_at timestamp not null,
+    ended_at timestamp not null,
+    plays int not null default 0,
+    -- plays on TRACK 01
+    credits int not null default 0,
+    charts int not null default 0,
+    -- plays better than every earlier play of the beatmap, first plays included
+    personal_bests int not null default 0,
+    -- closest user_data snapshots before and after the session
+    rating_before int,
+    rating_after int,
+    updated_at timestamp default now(),
+    created_at timestamp default now()
+);
+create index idx_sessions_user_uuid_started_at on sessions (user_uuid, started_at);
+
+-- track number from the record page, null for scores added before it was kept
+alter table scores add column track int;
+alter table scores add column session_id uuid references sessions (id) on delete set null;
+create index idx_scores_session_id on scores (session_id);
+
+-- group the existing scores
+create temporary table score_sessions on commit drop as
+select
+    id as score_id,
+    user_uuid,
+    sum(new_session) over (partition by user_uuid order by played_at, id) as n
+from (
+    select
+        id,
+        user_uuid,
+        played_at,
+        case
+            when played_at - lag(played_at) over (partition by user_uuid order by played_at, id) <= interval '30 minutes'
+            then 0 else 1
+        end as new_session
+    from scores
+) as s;
+
+create temporary table new_sessions on commit drop as
+select gen_random_uuid() as id, user_uuid, n
+from score_sessions
+group by user_uuid, n;
+
+insert into sessions (id, user_uuid, started_at, ended_at)
+select new_sessions.id, new_sessions.user_uuid, min(scores.played_at), max(scores.played_at)
+from new_sessions
+inner join score_sessions using (user_uuid, n)
+inner join scores on scores.id = score_sessions.score_id
+group by new_sessions.id, new_sessions.user_uuid;
+
+update scores set session_id = new_sessions.id
+from score_sessions
+inner join new_sessions using (user_uuid, n)
+where scores.id = score_sessions.score_id;
+
+update sessions set
+    plays = s.plays,
+    charts = s.charts,
+    personal_bests = s.personal_bests,
+    rating_before = (
+        select user_data.rating from user_data
+        where user_data.user_uuid = sessions.user_uuid and user_data.created_at <= sessions.started_at
+        order by user_data.created_at desc
+        limit 1
+    ),
+    rating_after = (
+        select user_data.rating from user_data
+        where user_data.user_uuid = sessions.user_uuid and user_data.created_at >= sessions.ended_at
+        order by user_data.created_at
+        limit 1
+    )
+from (
+    select
+        scores.session_id,
+        count(*) as plays,
+        count(distinct scores.beatmap_id) as charts,
+        count(*) filter (
+            where not exists (
+                select 1 from scores as earlier
+                where
+                    earlier.user_uuid = scores.user_uuid
+                    and earlier.beatmap_id = scores.beatmap_id
+                    and earlier.played_at < scores.played_at
+                    and earlier.achievement >= scores.achievement
+            )
+        ) as personal_bests
+    from scores
+    where scores.session_id is not null
+    group by scores.session_id
+) as s
+where sessions.id = s.session_id;
+
+-- +goose Down
+alter table scores drop column if exists session_id;
+alter table scores drop column if exists track;
+drop table if exists sessions;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- per-user time range scans (activity calendar, sessions, history filters),
+-- also covers the lookups idx_scores_user_uuid served
+create index idx_scores_user_uuid_played_at on scores (user_uuid, played_at);
+drop index if exists idx_scores_user_uuid;
+
+-- +goose Down
+create index if not exists idx_scores_user_uuid on scores (user_uuid);
+drop index if exists idx_scores_user_uuid_played_at;
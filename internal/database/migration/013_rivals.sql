@@ -0,0 +1,12 @@
+-- +goose Up
+-- users a user compares their scores against, one way
+create table rivals (
+    user_uuid uuid not null references users (id) on delete cascade,
+    rival_uuid uuid not null references users (id) on delete cascade,
+    created_at timestamp default now(),
+    primary key (user_uuid, rival_uuid),
+    check (user_uuid <> rival_uuid)
+);
+
+-- +goose Down
+drop table if exists rivals;
@@ -0,0 +1,50 @@
+-- +goose Up
+-- users following each other both ways are friends
+create table follows (
+    follower_uuid uuid not null references users (id) on delete cascade,
+    followee_uuid uuid not null references users (id) on delete cascade,
+    created_at timestamp default now(),
+    primary key (follower_uuid, followee_uuid),
+    check (follower_uuid <> followee_uuid)
+);
+
+create index idx_follows_followee_uuid on follows (followee_uuid);
+
+-- whether viewer_id and the owner follow each other, for 'friends'
+-- visibility in list queries
+-- +goose StatementBegin
+create function is_friend(owner_uuid uuid, viewer_id text) returns boolean
+language sql stable
+as $$
+    select exists (
+        select 1
+        from users v
+        inner join follows a on a.follower_uuid = v.id and a.followee_uuid = owner_uuid
+        inner join follows b on b.follower_uuid = owner_uuid and b.followee_uuid = v.id
+        where v.user_id = viewer_id
+    )
+$$;
+-- +goose StatementEnd
+
+-- notable events of a user, shown in the feed of their followers.
+-- score_id and beatmap_id are null for rating milestones.
+create table feed_items (
+    id uuid primary key,
+    user_uuid uuid not null references users (id) on delete cascade,
+    kind text not null check (kind in ('personal_best', 'rank', 'lamp', 'rating_milestone')),
+    score_id uuid references scores (id) on delete cascade,
+    beatmap_id uuid references beatmaps (id) on delete cascade,
+    accuracy text,
+    previous_accuracy text,
+    rank text,
+    lamp text,
+    rating int,
+    created_at timestamp not null default now()
+);
+
+create index idx_feed_items_user_uuid_created_at on feed_items (user_uuid, created_at);
+
+-- +goose Down
+drop table if exists feed_items;
+drop function if exists is_friend;
+drop table if exists follows;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- targets a user sets for themselves, evaluated after every scrape.
+--   rating     reach target_rating
+--   chart      reach target_rank and/or target_lamp on beatmap_id
+--   chart_set  reach the target on every chart matching the filters
+--   count      reach the target on target_count charts matching the filters
+-- level is the level shown in game, level_min and level_max bound the
+-- internal level. progress and target are as of evaluated_at.
+create table goals (
+    id uuid primary key,
+    user_uuid uuid not null references users (id) on delete cascade,
+    kind text not null check (kind in ('rating', 'chart', 'chart_set', 'count')),
+    title text not null default '',
+    target_rating int,
+    beatmap_id uuid references beatmaps (id) on delete cascade,
+    target_rank text,
+    target_lamp text,
+    difficulty text,
+    type text,
+    level text,
+    level_min numeric,
+    level_max numeric,
+    target_count int,
+    progress int not null default 0,
+    target int not null default 0,
+    completed_at timestamp,
+    evaluated_at timestamp,
+    updated_at timestamp default now(),
+    created_at timestamp default now(),
+    check (kind <> 'rating' or target_rating is not null),
+    check (kind <> 'chart' or beatmap_id is not null),
+    check (kind = 'rating' or target_rank is not null or target_lamp is not null),
+    check (kind <> 'count' or target_count is not null)
+);
+
+create index idx_goals_user_uuid on goals (user_uuid);
+
+-- +goose Down
+drop table if exists goals;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- lamp of a score, ordered like calculator.Lamp: 0 none, 1 FC, 2 FC+,
+-- 3 AP and 4 AP+. Must agree with Judgements.Lamp: a score without any
+-- judgements has no lamp and GOOD keeps the combo.
+-- +goose StatementBegin
+create function score_lamp(s scores) returns int
+language sql immutable
+as $$
+    select case
+        when s.tap_critical + s.tap_perfect + s.tap_great + s.tap_good + s.tap_miss
+            + s.hold_critical + s.hold_perfect + s.hold_great + s.hold_good + s.hold_miss
+            + s.slide_critical + s.slide_perfect + s.slide_great + s.slide_good + s.slide_miss
+            + s.touch_critical + s.touch_perfect + s.touch_great + s.touch_good + s.touch_miss
+            + s.break_critical + s.break_perfect + s.break_great + s.break_good + s.break_miss = 0 then 0
+        when s.tap_miss + s.hold_miss + s.slide_miss + s.touch_miss + s.break_miss > 0 then 0
+        when s.tap_good + s.hold_good + s.slide_good + s.touch_good + s.break_good > 0 then 1
+        when s.tap_great + s.hold_great + s.slide_great + s.touch_great + s.break_great > 0 then 2
+        when s.tap_perfect + s.hold_perfect + s.slide_perfect + s.touch_perfect + s.break_perfect > 0 then 3
+        else 4
+    end
+$$;
+-- +goose StatementEnd
+
+-- the best accuracy, achievement, lamp and DX score of a user on a chart
+-- over their unflagged scores. One row, all null when the chart is unplayed.
+-- +goose StatementBegin
+create function chart_best(chart_id uuid, owner_uuid uuid)
+returns table (accuracy text, achievement float8, lamp int, dx_score int)
+language sql stable
+as $$
+    select
+        (array_agg(s.accuracy order by s.achievement desc nulls last))[1]::text,
+        max(s.achievement)::float8,
+        max(score_lamp(s))::int,
+        max(s.dx_score)::int
+    from scores s
+    where
+        s.beatmap_id = chart_id
+        and s.user_uuid = owner_uuid
+        and s.flag_reason is null
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+drop function if exists chart_best(uuid, uuid);
+drop function if exists score_lamp(scores);
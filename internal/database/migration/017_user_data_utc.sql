@@ -0,0 +1,12 @@
+-- +goose Up
+-- user_data snapshots are compared with scores.played_at, which is UTC.
+-- now() on a timestamp column is the local time of the database, so store
+-- snapshots in UTC and move the existing ones from the database time zone.
+alter table user_data alter column created_at set default (now() at time zone 'utc');
+update user_data
+set created_at = (created_at at time zone current_setting('TimeZone')) at time zone 'utc';
+
+-- +goose Down
+update user_data
+set created_at = (created_at at time zone 'utc') at time zone current_setting('TimeZone');
+alter table user_data alter column created_at set default now();
@@ -19,30 +19,94 @@ values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 returning *;
 
 
--- name: GetAllBeatmaps :many
+-- name: ListBeatmaps :many
+-- keyset pagination over (sort_number, id) or (sort_value, id), see
+-- handler/pagination.go
 select
-    beatmaps.id,
-    beatmaps.song_id,
-    beatmaps.difficulty,
-    beatmaps.level,
-    beatmaps.internal_level,
-    beatmaps.type,
-    beatmaps.total_notes,
-    beatmaps.tap,
-    beatmaps.hold,
-    beatmaps.slide,
-    beatmaps.touch,
-    beatmaps.break,
-    beatmaps.note_designer,
-    beatmaps.max_dx_score,
-    songs.title,
-    songs.artist,
-    songs.genre,
-    songs.bpm,
-    songs.image_url,
-    songs.version
-from beatmaps
-inner join songs on beatmaps.song_id = songs.id;
+    b.id,
+    b.song_id,
+    b.difficulty,
+    b.level,
+    b.internal_level,
+    b.type,
+    b.total_notes,
+    b.tap,
+    b.hold,
+    b.slide,
+    b.touch,
+    b.break,
+    b.note_designer,
+    b.max_dx_score,
+    b.title,
+    b.artist,
+    b.genre,
+    b.bpm,
+    b.image_url,
+    b.version,
+    b.sort_number,
+    b.sort_value
+from (
+    select
+        beatmaps.id,
+        beatmaps.song_id,
+        beatmaps.difficulty,
+        beatmaps.level,
+        beatmaps.internal_level,
+        beatmaps.type,
+        beatmaps.total_notes,
+        beatmaps.tap,
+        beatmaps.hold,
+        beatmaps.slide,
+        beatmaps.touch,
+        beatmaps.break,
+        beatmaps.note_designer,
+        beatmaps.max_dx_score,
+        songs.title,
+        songs.artist,
+        songs.genre,
+        songs.bpm,
+        songs.image_url,
+        songs.version,
+        (case when sqlc.arg(sort)::text = 'level' then coalesce(beatmaps.internal_level, 0) end)::numeric as sort_number,
+        (case sqlc.arg(sort)::text
+            when 'title' then songs.title
+            when 'releaseDate' then coalesce(to_char(songs.release_date, 'YYYY-MM-DD'), '') || lpad(songs.sort, 10, '0')
+        end)::text as sort_value
+    from beatmaps
+    inner join songs on beatmaps.song_id = songs.id
+    where
+        (sqlc.narg(version)::text is null or songs.version = sqlc.narg(version)::text)
+        and (sqlc.narg(genre)::text is null or songs.genre = sqlc.narg(genre)::text)
+        and (sqlc.narg(difficulty)::text is null or beatmaps.difficulty = sqlc.narg(difficulty)::text)
+        and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+        and (sqlc.narg(level)::text is null or beatmaps.level = sqlc.narg(level)::text)
+        and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+        and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric)
+) as b
+where
+    sqlc.narg(cursor_value)::text is null
+    or (
+        sqlc.arg(descending)::bool
+        and (
+            (b.sort_number, b.id) < (sqlc.narg(cursor_number)::numeric, sqlc.arg(cursor_id)::uuid)
+            or (b.sort_value, b.id) < (sqlc.narg(cursor_value)::text, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+    or (
+        not sqlc.arg(descending)::bool
+        and (
+            (b.sort_number, b.id) > (sqlc.narg(cursor_number)::numeric, sqlc.arg(cursor_id)::uuid)
+            or (b.sort_value, b.id) > (sqlc.narg(cursor_value)::text, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+order by
+    case when sqlc.arg(descending)::bool then b.sort_number end desc,
+    case when sqlc.arg(descending)::bool then b.sort_value end desc,
+    case when sqlc.arg(descending)::bool then b.id end desc,
+    case when not sqlc.arg(descending)::bool then b.sort_number end asc,
+    case when not sqlc.arg(descending)::bool then b.sort_value end asc,
+    case when not sqlc.arg(descending)::bool then b.id end asc
+limit sqlc.arg(page_size);
 
 
 -- name: GetBeatmapByBeatmapID :one
@@ -95,7 +159,7 @@ select
     songs.image_url,
     songs.version
 from beatmaps
-inner join songs on beatmaps.song_id = songs.song_id
+inner join songs on beatmaps.song_id = songs.id
 where beatmaps.song_id = $1;
 
 -- name: GetBeatmapBySongIDDifficultyAndType :one
@@ -122,3 +186,37 @@ set
     updated_at = now()
 where id = $1
 returning *;
+
+-- name: GetLevelTableByUserID :many
+-- charts of available songs in a level bucket with the user's best
+-- achievement, lamp and DX score, each null when unplayed. level is the
+-- level shown in game, level_min and level_max bound the internal level.
+-- lamp is ordered like calculator.Lamp. Hardest first.
+select
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.internal_level,
+    beatmaps.type,
+    beatmaps.max_dx_score,
+    b.accuracy,
+    b.achievement,
+    b.lamp,
+    b.dx_score
+from beatmaps
+inner join songs on beatmaps.song_id = songs.id
+cross join users
+left join lateral chart_best(beatmaps.id, users.id) as b on true
+where
+    users.user_id = sqlc.arg(user_id)
+    and songs.is_available
+    and beatmaps.difficulty <> 'utage'
+    and (sqlc.narg(level)::text is null or beatmaps.level = sqlc.narg(level)::text)
+    and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+    and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric)
+    and (sqlc.narg(difficulty)::text is null or beatmaps.difficulty = sqlc.narg(difficulty)::text)
+    and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+order by beatmaps.internal_level desc nulls last, songs.sort, beatmaps.difficulty, beatmaps.type;
@@ -0,0 +1,72 @@
+-- name: CreateFeedItem :one
+insert into feed_items (
+    id,
+    user_uuid,
+    kind,
+    score_id,
+    beatmap_id,
+    accuracy,
+    previous_accuracy,
+    rank,
+    lamp,
+    rating,
+    created_at
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+returning *;
+
+-- name: GetFeedByUserID :many
+-- feed items of the users user_id follows, newest first by default.
+-- Score items follow the scores visibility of their owner and rating
+-- milestones the rating history visibility, 'friends' needs a follow back.
+-- keyset pagination over (created_at, id), see handler/pagination.go
+select
+    feed_items.id,
+    feed_items.kind,
+    u.user_id,
+    u.display_name,
+    feed_items.score_id,
+    feed_items.beatmap_id,
+    beatmaps.song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.type,
+    feed_items.accuracy,
+    feed_items.previous_accuracy,
+    feed_items.rank,
+    feed_items.lamp,
+    feed_items.rating,
+    feed_items.created_at
+from feed_items
+inner join users u on feed_items.user_uuid = u.id
+inner join follows on follows.followee_uuid = u.id
+inner join users viewer on follows.follower_uuid = viewer.id
+left join user_privacy p on u.id = p.user_uuid
+left join beatmaps on feed_items.beatmap_id = beatmaps.id
+left join songs on beatmaps.song_id = songs.id
+where
+    viewer.user_id = sqlc.arg(user_id)
+    and (sqlc.narg(kind)::text is null or feed_items.kind = sqlc.narg(kind)::text)
+    and case
+        when feed_items.kind = 'rating_milestone' then coalesce(p.rating_history_visibility, 'public')
+        else coalesce(p.scores_visibility, 'public')
+    end in ('public', case when is_friend(u.id, viewer.user_id) then 'friends' end)
+    and (
+        sqlc.narg(cursor_time)::timestamp is null
+        or (
+            sqlc.arg(descending)::bool
+            and (feed_items.created_at, feed_items.id) < (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+        )
+        or (
+            not sqlc.arg(descending)::bool
+            and (feed_items.created_at, feed_items.id) > (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+order by
+    case when sqlc.arg(descending)::bool then feed_items.created_at end desc,
+    case when sqlc.arg(descending)::bool then feed_items.id end desc,
+    case when not sqlc.arg(descending)::bool then feed_items.created_at end asc,
+    case when not sqlc.arg(descending)::bool then feed_items.id end asc
+limit sqlc.arg(page_size);
@@ -0,0 +1,75 @@
+-- name: CreateFollow :one
+insert into follows (follower_uuid, followee_uuid)
+values ($1, $2)
+returning *;
+
+-- name: DeleteFollow :execrows
+delete from follows
+using users follower, users followee
+where
+    follows.follower_uuid = follower.id
+    and follows.followee_uuid = followee.id
+    and follower.user_id = sqlc.arg(follower_id)
+    and followee.user_id = sqlc.arg(followee_id);
+
+-- name: AreFriends :one
+-- friends follow each other
+select exists (
+    select 1
+    from follows a
+    inner join follows b
+        on a.follower_uuid = b.followee_uuid and a.followee_uuid = b.follower_uuid
+    inner join users u on a.follower_uuid = u.id
+    inner join users o on a.followee_uuid = o.id
+    where u.user_id = sqlc.arg(user_id) and o.user_id = sqlc.arg(other_user_id)
+);
+
+-- name: GetFollowingByUserID :many
+-- users that user_id follows. Users whose profile is not public are only
+-- listed to the follower, to themselves (viewer_id) and to their friends.
+select
+    f.id,
+    f.user_id,
+    f.display_name,
+    exists (
+        select 1 from follows back
+        where back.follower_uuid = f.id and back.followee_uuid = u.id
+    ) as mutual,
+    follows.created_at
+from follows
+inner join users u on follows.follower_uuid = u.id
+inner join users f on follows.followee_uuid = f.id
+left join user_privacy p on f.id = p.user_uuid
+where
+    u.user_id = sqlc.arg(user_id)
+    and (
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or sqlc.narg(viewer_id)::text in (u.user_id, f.user_id)
+        or (p.profile_visibility = 'friends' and is_friend(f.id, sqlc.narg(viewer_id)::text))
+    )
+order by follows.created_at desc, f.user_id;
+
+-- name: GetFollowersByUserID :many
+-- users that follow user_id, with the same visibility rules as
+-- GetFollowingByUserID
+select
+    f.id,
+    f.user_id,
+    f.display_name,
+    exists (
+        select 1 from follows back
+        where back.follower_uuid = u.id and back.followee_uuid = f.id
+    ) as mutual,
+    follows.created_at
+from follows
+inner join users u on follows.followee_uuid = u.id
+inner join users f on follows.follower_uuid = f.id
+left join user_privacy p on f.id = p.user_uuid
+where
+    u.user_id = sqlc.arg(user_id)
+    and (
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or sqlc.narg(viewer_id)::text in (u.user_id, f.user_id)
+        or (p.profile_visibility = 'friends' and is_friend(f.id, sqlc.narg(viewer_id)::text))
+    )
+order by follows.created_at desc, f.user_id;
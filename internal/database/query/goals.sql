@@ -0,0 +1,122 @@
+-- name: CreateGoal :one
+insert into goals (
+    id,
+    user_uuid,
+    kind,
+    title,
+    target_rating,
+    beatmap_id,
+    target_rank,
+    target_lamp,
+    difficulty,
+    type,
+    level,
+    level_min,
+    level_max,
+    target_count
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+returning *;
+
+-- name: CountGoalsByUserUUID :one
+select count(*) from goals
+where user_uuid = $1;
+
+-- name: GetGoalsByUserID :many
+-- completed filters on whether completed_at is set
+select goals.*
+from goals
+inner join users on goals.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and (sqlc.narg(completed)::bool is null or (goals.completed_at is not null) = sqlc.narg(completed)::bool)
+order by goals.created_at, goals.id;
+
+-- name: GetGoalsByUserUUID :many
+select *
+from goals
+where user_uuid = $1
+order by created_at, id;
+
+-- name: GetGoalByUserID :one
+select goals.*
+from goals
+inner join users on goals.user_uuid = users.id
+where goals.id = sqlc.arg(id) and users.user_id = sqlc.arg(user_id);
+
+-- name: UpdateGoal :one
+-- the goal is evaluated again from scratch, so completion is reset
+update goals
+set
+    title = $2,
+    target_rating = $3,
+    beatmap_id = $4,
+    target_rank = $5,
+    target_lamp = $6,
+    difficulty = $7,
+    type = $8,
+    level = $9,
+    level_min = $10,
+    level_max = $11,
+    target_count = $12,
+    completed_at = null,
+    updated_at = now()
+where id = $1
+returning *;
+
+-- name: UpdateGoalProgress :one
+-- completed_at is kept once set, even if progress drops again
+update goals
+set
+    progress = sqlc.arg(progress)::int,
+    target = sqlc.arg(target)::int,
+    completed_at = case
+        when sqlc.arg(progress)::int >= sqlc.arg(target)::int then coalesce(completed_at, now())
+        else completed_at
+    end,
+    evaluated_at = now()
+where id = sqlc.arg(id)
+returning *;
+
+-- name: DeleteGoal :execrows
+delete from goals
+using users
+where
+    goals.user_uuid = users.id
+    and goals.id = sqlc.arg(id)
+    and users.user_id = sqlc.arg(user_id);
+
+-- name: GetChartRecordsByUserUUID :many
+-- the best achievement and lamp of every chart the user played, flagged
+-- scores left out. lamp is ordered like calculator.Lamp, see score_lamp.
+select
+    beatmaps.id as beatmap_id,
+    beatmaps.difficulty,
+    beatmaps.type,
+    beatmaps.level,
+    beatmaps.internal_level,
+    songs.is_available,
+    coalesce(max(scores.achievement), 0)::float8 as achievement,
+    max(score_lamp(scores))::int as lamp
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join songs on beatmaps.song_id = songs.id
+where scores.user_uuid = $1 and scores.flag_reason is null
+group by beatmaps.id, songs.id;
+
+-- name: CountGoalCharts :one
+-- charts a chart_set goal covers: available charts matching the filters,
+-- utage only when asked for
+select count(*)
+from beatmaps
+inner join songs on beatmaps.song_id = songs.id
+where
+    songs.is_available
+    and (
+        beatmaps.difficulty = sqlc.narg(difficulty)::text
+        or (sqlc.narg(difficulty)::text is null and beatmaps.difficulty <> 'utage')
+    )
+    and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+    and (sqlc.narg(level)::text is null or beatmaps.level = sqlc.narg(level)::text)
+    and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+    and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric);
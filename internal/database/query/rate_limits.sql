@@ -0,0 +1,44 @@
+-- name: IncrementRateLimit :one
+-- counts a hit in the current fixed window, resetting the counter when a new window starts
+insert into rate_limits (key, window_start, count)
+values ($1, $2, 1)
+on conflict (key) do update
+set
+    count = case
+        when rate_limits.window_start = excluded.window_start then rate_limits.count + 1
+        else 1
+    end,
+    window_start = excluded.window_start
+returning count;
+
+
+-- name: DeleteExpiredRateLimits :exec
+delete from rate_limits
+where window_start < $1;
+
+
+-- name: RecordLoginFailure :one
+-- counts a failed login at window_start, starting over when the previous
+-- one happened before forget_before
+insert into rate_limits (key, window_start, count)
+values (sqlc.arg(key), sqlc.arg(window_start), 1)
+on conflict (key) do update
+set
+    count = case
+        when rate_limits.window_start >= sqlc.arg(forget_before)::timestamp then rate_limits.count + 1
+        else 1
+    end,
+    window_start = excluded.window_start
+returning count;
+
+
+-- name: GetLoginFailures :one
+-- failed logins and when the last one happened
+select count, window_start
+from rate_limits
+where key = $1;
+
+
+-- name: DeleteLoginFailures :exec
+delete from rate_limits
+where key = $1;
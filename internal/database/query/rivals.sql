@@ -0,0 +1,46 @@
+-- name: CreateRival :one
+insert into rivals (user_uuid, rival_uuid)
+values ($1, $2)
+returning *;
+
+-- name: CountRivalsByUserUUID :one
+select count(*) from rivals
+where user_uuid = $1;
+
+-- name: GetRivalsByUserID :many
+-- rivals whose profile is not public are only listed to the user, to
+-- themselves (viewer_id) and, when it is friends only, to their friends
+select
+    r.id,
+    r.user_id,
+    r.display_name,
+    d.rating,
+    rivals.created_at
+from rivals
+inner join users u on rivals.user_uuid = u.id
+inner join users r on rivals.rival_uuid = r.id
+left join user_privacy p on r.id = p.user_uuid
+left join lateral (
+    select user_data.rating
+    from user_data
+    where user_data.user_uuid = r.id
+    order by user_data.created_at desc
+    limit 1
+) as d on true
+where
+    u.user_id = sqlc.arg(user_id)
+    and (
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or sqlc.narg(viewer_id)::text in (u.user_id, r.user_id)
+        or (p.profile_visibility = 'friends' and is_friend(r.id, sqlc.narg(viewer_id)::text))
+    )
+order by rivals.created_at, r.user_id;
+
+-- name: DeleteRival :execrows
+delete from rivals
+using users u, users r
+where
+    rivals.user_uuid = u.id
+    and rivals.rival_uuid = r.id
+    and u.user_id = sqlc.arg(user_id)
+    and r.user_id = sqlc.arg(rival_id);
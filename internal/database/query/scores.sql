@@ -34,7 +34,10 @@ insert into scores (
     break_miss,
     fast,
     late,
-    played_at
+    played_at,
+    flag_reason,
+    track,
+    session_id
 )
 values (
     $1, $2, $3, $4, $5, $6, $7,
@@ -43,61 +46,510 @@ values (
     $18, $19, $20, $21, $22,
     $23, $24, $25, $26, $27,
     $28, $29, $30, $31, $32,
-    $33, $34, $35
+    $33, $34, $35, $36, $37, $38
 )
 returning *;
 
 
 -- name: GetScoresByUserID :many
+-- keyset pagination over (sort_number, id) or (sort_time, id), see
+-- handler/pagination.go
+-- played_from and played_to are UTC bounds, played_to is exclusive
 select
+    s.id,
+    s.beatmap_id,
+    s.song_id,
+    s.user_uuid,
+    s.accuracy,
+    s.max_combo,
+    s.dx_score,
+    s.tap_critical,
+    s.tap_perfect,
+    s.tap_great,
+    s.tap_good,
+    s.tap_miss,
+    s.hold_critical,
+    s.hold_perfect,
+    s.hold_great,
+    s.hold_good,
+    s.hold_miss,
+    s.slide_critical,
+    s.slide_perfect,
+    s.slide_great,
+    s.slide_good,
+    s.slide_miss,
+    s.touch_critical,
+    s.touch_perfect,
+    s.touch_great,
+    s.touch_good,
+    s.touch_miss,
+    s.break_critical,
+    s.break_perfect,
+    s.break_great,
+    s.break_good,
+    s.break_miss,
+    s.fast,
+    s.late,
+    s.played_at,
+    s.flag_reason,
+    s.track,
+    s.session_id,
+    s.title,
+    s.artist,
+    s.genre,
+    s.image_url,
+    s.version,
+    s.difficulty,
+    s.level,
+    s.internal_level,
+    s.type,
+    s.max_dx_score,
+    s.sort_number,
+    s.sort_time
+from (
+    select
+        scores.id,
+        scores.beatmap_id,
+        scores.song_id,
+        scores.user_uuid,
+        scores.accuracy,
+        scores.max_combo,
+        scores.dx_score,
+        scores.tap_critical,
+        scores.tap_perfect,
+        scores.tap_great,
+        scores.tap_good,
+        scores.tap_miss,
+        scores.hold_critical,
+        scores.hold_perfect,
+        scores.hold_great,
+        scores.hold_good,
+        scores.hold_miss,
+        scores.slide_critical,
+        scores.slide_perfect,
+        scores.slide_great,
+        scores.slide_good,
+        scores.slide_miss,
+        scores.touch_critical,
+        scores.touch_perfect,
+        scores.touch_great,
+        scores.touch_good,
+        scores.touch_miss,
+        scores.break_critical,
+        scores.break_perfect,
+        scores.break_great,
+        scores.break_good,
+        scores.break_miss,
+        scores.fast,
+        scores.late,
+        scores.played_at,
+        scores.flag_reason,
+        scores.track,
+        scores.session_id,
+        songs.title,
+        songs.artist,
+        songs.genre,
+        songs.image_url,
+        songs.version,
+        beatmaps.difficulty,
+        beatmaps.level,
+        beatmaps.internal_level,
+        beatmaps.type,
+        beatmaps.max_dx_score,
+        (case sqlc.arg(sort)::text
+            when 'achievement' then coalesce(scores.achievement, 0)
+            when 'level' then coalesce(beatmaps.internal_level, 0)
+            when 'dxScore' then scores.dx_score
+        end)::numeric as sort_number,
+        (case when sqlc.arg(sort)::text = 'playedAt' then scores.played_at end)::timestamp as sort_time
+    from scores
+    inner join songs on scores.song_id = songs.id
+    inner join beatmaps on scores.beatmap_id = beatmaps.id
+    inner join users on scores.user_uuid = users.id
+    where
+        users.user_id = sqlc.arg(user_id)
+        and (sqlc.narg(difficulty)::text is null or beatmaps.difficulty = sqlc.narg(difficulty)::text)
+        and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+        and (sqlc.narg(version)::text is null or songs.version = sqlc.narg(version)::text)
+        and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+        and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric)
+        and (sqlc.narg(played_from)::timestamp is null or scores.played_at >= sqlc.narg(played_from)::timestamp)
+        and (sqlc.narg(played_to)::timestamp is null or scores.played_at < sqlc.narg(played_to)::timestamp)
+        and (
+            sqlc.narg(achievement_min)::numeric is null
+            or scores.achievement >= sqlc.narg(achievement_min)::numeric
+        )
+        and (
+            sqlc.narg(achievement_max)::numeric is null
+            or scores.achievement <= sqlc.narg(achievement_max)::numeric
+        )
+        and (sqlc.narg(session_id)::uuid is null or scores.session_id = sqlc.narg(session_id)::uuid)
+) as s
+where
+    sqlc.narg(cursor_value)::text is null
+    or (
+        sqlc.arg(descending)::bool
+        and (
+            (s.sort_number, s.id) < (sqlc.narg(cursor_number)::numeric, sqlc.arg(cursor_id)::uuid)
+            or (s.sort_time, s.id) < (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+    or (
+        not sqlc.arg(descending)::bool
+        and (
+            (s.sort_number, s.id) > (sqlc.narg(cursor_number)::numeric, sqlc.arg(cursor_id)::uuid)
+            or (s.sort_time, s.id) > (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+order by
+    case when sqlc.arg(descending)::bool then s.sort_number end desc,
+    case when sqlc.arg(descending)::bool then s.sort_time end desc,
+    case when sqlc.arg(descending)::bool then s.id end desc,
+    case when not sqlc.arg(descending)::bool then s.sort_number end asc,
+    case when not sqlc.arg(descending)::bool then s.sort_time end asc,
+    case when not sqlc.arg(descending)::bool then s.id end asc
+limit sqlc.arg(page_size);
+
+
+-- name: GetPersonalBestsByUserID :many
+-- the best score of every beatmap the user has played, utage and flagged
+-- scores excluded
+select distinct on (scores.beatmap_id)
     scores.id,
     scores.beatmap_id,
     scores.song_id,
-    scores.user_uuid,
     scores.accuracy,
-    scores.max_combo,
     scores.dx_score,
-    scores.tap_critical,
-    scores.tap_perfect,
-    scores.tap_great,
-    scores.tap_good,
-    scores.tap_miss,
-    scores.hold_critical,
-    scores.hold_perfect,
-    scores.hold_great,
-    scores.hold_good,
-    scores.hold_miss,
-    scores.slide_critical,
-    scores.slide_perfect,
-    scores.slide_great,
-    scores.slide_good,
-    scores.slide_miss,
-    scores.touch_critical,
-    scores.touch_perfect,
-    scores.touch_great,
-    scores.touch_good,
-    scores.touch_miss,
-    scores.break_critical,
-    scores.break_perfect,
-    scores.break_great,
-    scores.break_good,
-    scores.break_miss,
-    scores.fast,
-    scores.late,
     scores.played_at,
     songs.title,
-    songs.artist,
-    songs.genre,
     songs.image_url,
-    songs.version,
+    songs.is_new,
     beatmaps.difficulty,
     beatmaps.level,
     beatmaps.internal_level,
-    beatmaps.type
+    beatmaps.type,
+    beatmaps.max_dx_score
 from scores
 inner join songs on scores.song_id = songs.id
 inner join beatmaps on scores.beatmap_id = beatmaps.id
 inner join users on scores.user_uuid = users.id
-where users.user_id = $1
+where users.user_id = $1 and not songs.is_utage and scores.flag_reason is null
+order by
+    scores.beatmap_id,
+    scores.achievement desc nulls last,
+    scores.played_at asc;
+
+
+-- name: GetRecentAchievementsByUserID :many
+-- internal level and achievement of the user's latest unflagged plays of
+-- beatmaps with a known internal level
+select
+    beatmaps.internal_level::numeric as internal_level,
+    scores.accuracy
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and beatmaps.internal_level is not null
+    and scores.flag_reason is null
 order by scores.played_at desc
-limit $2 offset $3;
+limit sqlc.arg(max_plays);
+
+
+-- name: GetJudgementTotalsByUserID :one
+-- judgements of the user's plays summed up, filters as in GetScoresByUserID
+select
+    count(*) as plays,
+    coalesce(sum(scores.tap_critical), 0)::bigint as tap_critical,
+    coalesce(sum(scores.tap_perfect), 0)::bigint as tap_perfect,
+    coalesce(sum(scores.tap_great), 0)::bigint as tap_great,
+    coalesce(sum(scores.tap_good), 0)::bigint as tap_good,
+    coalesce(sum(scores.tap_miss), 0)::bigint as tap_miss,
+    coalesce(sum(scores.hold_critical), 0)::bigint as hold_critical,
+    coalesce(sum(scores.hold_perfect), 0)::bigint as hold_perfect,
+    coalesce(sum(scores.hold_great), 0)::bigint as hold_great,
+    coalesce(sum(scores.hold_good), 0)::bigint as hold_good,
+    coalesce(sum(scores.hold_miss), 0)::bigint as hold_miss,
+    coalesce(sum(scores.slide_critical), 0)::bigint as slide_critical,
+    coalesce(sum(scores.slide_perfect), 0)::bigint as slide_perfect,
+    coalesce(sum(scores.slide_great), 0)::bigint as slide_great,
+    coalesce(sum(scores.slide_good), 0)::bigint as slide_good,
+    coalesce(sum(scores.slide_miss), 0)::bigint as slide_miss,
+    coalesce(sum(scores.touch_critical), 0)::bigint as touch_critical,
+    coalesce(sum(scores.touch_perfect), 0)::bigint as touch_perfect,
+    coalesce(sum(scores.touch_great), 0)::bigint as touch_great,
+    coalesce(sum(scores.touch_good), 0)::bigint as touch_good,
+    coalesce(sum(scores.touch_miss), 0)::bigint as touch_miss,
+    coalesce(sum(scores.break_critical), 0)::bigint as break_critical,
+    coalesce(sum(scores.break_perfect), 0)::bigint as break_perfect,
+    coalesce(sum(scores.break_great), 0)::bigint as break_great,
+    coalesce(sum(scores.break_good), 0)::bigint as break_good,
+    coalesce(sum(scores.break_miss), 0)::bigint as break_miss,
+    coalesce(sum(scores.fast), 0)::bigint as fast,
+    coalesce(sum(scores.late), 0)::bigint as late
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and (sqlc.narg(difficulty)::text is null or beatmaps.difficulty = sqlc.narg(difficulty)::text)
+    and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+    and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+    and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric)
+    and (sqlc.narg(played_from)::timestamp is null or scores.played_at >= sqlc.narg(played_from)::timestamp)
+    and (sqlc.narg(played_to)::timestamp is null or scores.played_at < sqlc.narg(played_to)::timestamp);
+
+
+-- name: GetFastLateTrendByUserID :many
+-- fast and late per JST week, weeks start on monday
+select
+    date_trunc('week', scores.played_at + interval '9 hours')::date as week,
+    count(*) as plays,
+    coalesce(sum(scores.fast), 0)::bigint as fast,
+    coalesce(sum(scores.late), 0)::bigint as late
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and (sqlc.narg(difficulty)::text is null or beatmaps.difficulty = sqlc.narg(difficulty)::text)
+    and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+    and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+    and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric)
+    and (sqlc.narg(played_from)::timestamp is null or scores.played_at >= sqlc.narg(played_from)::timestamp)
+    and (sqlc.narg(played_to)::timestamp is null or scores.played_at < sqlc.narg(played_to)::timestamp)
+group by week
+order by week;
+
+
+-- name: GetWeakSpotsByUserID :many
+-- beatmaps the user played at least min_plays times, the most GOODs and
+-- MISSes per note first
+select
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.internal_level,
+    beatmaps.type,
+    count(*) as plays,
+    coalesce(avg(scores.achievement), 0)::float8 as average_achievement,
+    sum(scores.tap_great + scores.hold_great + scores.slide_great + scores.touch_great + scores.break_great)::bigint as great,
+    sum(scores.tap_good + scores.hold_good + scores.slide_good + scores.touch_good + scores.break_good)::bigint as good,
+    sum(scores.tap_miss + scores.hold_miss + scores.slide_miss + scores.touch_miss + scores.break_miss)::bigint as miss,
+    sum(beatmaps.tap + beatmaps.hold + beatmaps.slide + beatmaps.touch + beatmaps.break)::bigint as notes,
+    coalesce(sum(scores.fast), 0)::bigint as fast,
+    coalesce(sum(scores.late), 0)::bigint as late
+from scores
+inner join songs on scores.song_id = songs.id
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and (sqlc.narg(difficulty)::text is null or beatmaps.difficulty = sqlc.narg(difficulty)::text)
+    and (sqlc.narg(type)::text is null or beatmaps.type = sqlc.narg(type)::text)
+    and (sqlc.narg(level_min)::numeric is null or beatmaps.internal_level >= sqlc.narg(level_min)::numeric)
+    and (sqlc.narg(level_max)::numeric is null or beatmaps.internal_level <= sqlc.narg(level_max)::numeric)
+    and (sqlc.narg(played_from)::timestamp is null or scores.played_at >= sqlc.narg(played_from)::timestamp)
+    and (sqlc.narg(played_to)::timestamp is null or scores.played_at < sqlc.narg(played_to)::timestamp)
+group by beatmaps.id, songs.id
+having count(*) >= sqlc.arg(min_plays)
+order by
+    sum(scores.tap_good + scores.hold_good + scores.slide_good + scores.touch_good + scores.break_good
+        + scores.tap_miss + scores.hold_miss + scores.slide_miss + scores.touch_miss + scores.break_miss)::float8
+    / nullif(sum(beatmaps.tap + beatmaps.hold + beatmaps.slide + beatmaps.touch + beatmaps.break), 0) desc nulls last,
+    beatmaps.id
+limit sqlc.arg(max_results);
+
+
+-- name: GetDailyPlayCountsByUserID :many
+-- plays per JST day, days without plays are left out. played_from and
+-- played_to are UTC bounds, played_to is exclusive.
+select
+    (scores.played_at + interval '9 hours')::date as day,
+    count(*) as plays
+from scores
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and scores.played_at >= sqlc.arg(played_from)
+    and scores.played_at < sqlc.arg(played_to)
+group by day
+order by day;
+
+
+-- name: GetPlayStreaksByUserID :many
+-- the latest and the longest run of consecutive JST days with plays, one
+-- row when they are the same run. Latest first, ties of the longest go to
+-- the latest run.
+with streaks as (
+    select
+        min(d.day)::date as first_day,
+        max(d.day)::date as last_day,
+        count(*) as days
+    from (
+        select
+            days.day,
+            days.day - (row_number() over (order by days.day))::int as run
+        from (
+            select distinct (scores.played_at + interval '9 hours')::date as day
+            from scores
+            inner join users on scores.user_uuid = users.id
+            where users.user_id = $1
+        ) as days
+    ) as d
+    group by d.run
+)
+select first_day, last_day, days from (
+    (select * from streaks order by last_day desc limit 1)
+    union
+    (select * from streaks order by days desc, last_day desc limit 1)
+) as s
+order by last_day desc;
+
+
+-- name: GetMostPlayedBeatmapsByUserID :many
+-- played_from and played_to are UTC bounds, played_to is exclusive
+select
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.type,
+    count(*) as plays
+from scores
+inner join songs on scores.song_id = songs.id
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and scores.played_at >= sqlc.arg(played_from)
+    and scores.played_at < sqlc.arg(played_to)
+group by beatmaps.id, songs.id
+order by plays desc, beatmaps.id
+limit sqlc.arg(max_results);
+
+
+-- name: GetPlayCountsByVersionByUserID :many
+-- plays per version of the song played, most played first
+select
+    songs.version,
+    count(*) as plays
+from scores
+inner join songs on scores.song_id = songs.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and scores.played_at >= sqlc.arg(played_from)
+    and scores.played_at < sqlc.arg(played_to)
+group by songs.version
+order by plays desc, songs.version;
+
+
+-- name: GetBeatmapLeaderboard :many
+-- every user's best unflagged score of the beatmap, by achievement, then
+-- dx score, then the earliest play. users whose scores are not public
+-- only show up for themselves (viewer_id). keyset pagination over
+-- (achievement, dx_score, played_at, id), the descending keys negated so
+-- one row comparison follows the order.
+with bests as (
+    select distinct on (scores.user_uuid)
+        scores.id,
+        scores.user_uuid,
+        scores.accuracy,
+        scores.dx_score,
+        scores.played_at,
+        coalesce(scores.achievement, 0) as achievement
+    from scores
+    inner join users on scores.user_uuid = users.id
+    left join user_privacy p on users.id = p.user_uuid
+    where
+        scores.beatmap_id = sqlc.arg(beatmap_id)
+        and scores.flag_reason is null
+        and (
+            coalesce(p.scores_visibility, 'public') = 'public'
+            or users.user_id = sqlc.narg(viewer_id)::text
+            or (p.scores_visibility = 'friends' and is_friend(users.id, sqlc.narg(viewer_id)::text))
+        )
+    order by scores.user_uuid, achievement desc, scores.dx_score desc, scores.played_at
+),
+ranked as (
+    select
+        bests.id,
+        users.user_id,
+        users.display_name,
+        bests.accuracy,
+        bests.achievement,
+        bests.dx_score,
+        bests.played_at,
+        rank() over (order by bests.achievement desc, bests.dx_score desc, bests.played_at) as rank
+    from bests
+    inner join users on bests.user_uuid = users.id
+)
+select ranked.*
+from ranked
+where
+    sqlc.narg(cursor_achievement)::numeric is null
+    or (-ranked.achievement, -ranked.dx_score, ranked.played_at, ranked.id)
+    > (
+        -sqlc.narg(cursor_achievement)::numeric,
+        -sqlc.arg(cursor_dx_score)::int,
+        sqlc.arg(cursor_played_at)::timestamp,
+        sqlc.arg(cursor_id)::uuid
+    )
+order by ranked.achievement desc, ranked.dx_score desc, ranked.played_at, ranked.id
+limit sqlc.arg(page_size);
+
+
+-- name: GetBeatmapLeaderboardEntry :one
+-- the viewer's row of GetBeatmapLeaderboard
+with bests as (
+    select distinct on (scores.user_uuid)
+        scores.id,
+        scores.user_uuid,
+        scores.accuracy,
+        scores.dx_score,
+        scores.played_at,
+        coalesce(scores.achievement, 0) as achievement
+    from scores
+    inner join users on scores.user_uuid = users.id
+    left join user_privacy p on users.id = p.user_uuid
+    where
+        scores.beatmap_id = sqlc.arg(beatmap_id)
+        and scores.flag_reason is null
+        and (
+            coalesce(p.scores_visibility, 'public') = 'public'
+            or users.user_id = sqlc.narg(viewer_id)::text
+            or (p.scores_visibility = 'friends' and is_friend(users.id, sqlc.narg(viewer_id)::text))
+        )
+    order by scores.user_uuid, achievement desc, scores.dx_score desc, scores.played_at
+),
+ranked as (
+    select
+        bests.id,
+        users.user_id,
+        users.display_name,
+        bests.accuracy,
+        bests.achievement,
+        bests.dx_score,
+        bests.played_at,
+        rank() over (order by bests.achievement desc, bests.dx_score desc, bests.played_at) as rank
+    from bests
+    inner join users on bests.user_uuid = users.id
+)
+select ranked.*
+from ranked
+where ranked.user_id = sqlc.narg(viewer_id)::text;
+
+-- name: GetScoresByUserUUIDAndBeatmapID :many
+-- earlier plays the scraper compares a new score with, flagged scores
+-- do not count as records
+select *
+from scores
+where user_uuid = $1 and beatmap_id = $2 and flag_reason is null
+order by played_at;
@@ -0,0 +1,94 @@
+-- name: CreateSession :one
+insert into sessions (id, user_uuid, started_at, ended_at)
+values ($1, $2, $3, $4)
+returning *;
+
+-- name: GetLatestSessionByUserUUID :one
+select * from sessions
+where user_uuid = $1
+order by started_at desc
+limit 1;
+
+-- name: GetSessionByUserID :one
+select sessions.* from sessions
+inner join users on sessions.user_uuid = users.id
+where sessions.id = sqlc.arg(id) and users.user_id = sqlc.arg(user_id);
+
+-- name: UpdateSessionSummary :one
+-- recomputes the summary from the session's unflagged scores and the
+-- closest user_data snapshots, a credit starts on TRACK 01 and a personal
+-- best is a play better than every earlier unflagged play of the beatmap.
+-- played_at and user_data.created_at are both UTC.
+update sessions set
+    started_at = s.started_at,
+    ended_at = s.ended_at,
+    plays = s.plays,
+    credits = s.credits,
+    charts = s.charts,
+    personal_bests = s.personal_bests,
+    rating_before = (
+        select user_data.rating from user_data
+        where user_data.user_uuid = sessions.user_uuid and user_data.created_at <= s.started_at
+        order by user_data.created_at desc
+        limit 1
+    ),
+    rating_after = (
+        select user_data.rating from user_data
+        where user_data.user_uuid = sessions.user_uuid and user_data.created_at >= s.ended_at
+        order by user_data.created_at
+        limit 1
+    ),
+    updated_at = now()
+from (
+    select
+        scores.session_id,
+        min(scores.played_at) as started_at,
+        max(scores.played_at) as ended_at,
+        count(*) as plays,
+        count(*) filter (where scores.track = 1) as credits,
+        count(distinct scores.beatmap_id) as charts,
+        count(*) filter (
+            where not exists (
+                select 1 from scores as earlier
+                where
+                    earlier.user_uuid = scores.user_uuid
+                    and earlier.beatmap_id = scores.beatmap_id
+                    and earlier.played_at < scores.played_at
+                    and earlier.achievement >= scores.achievement
+                    and earlier.flag_reason is null
+            )
+        ) as personal_bests
+    from scores
+    where scores.session_id = sqlc.arg(id) and scores.flag_reason is null
+    group by scores.session_id
+) as s
+where sessions.id = s.session_id
+returning sessions.*;
+
+-- name: GetSessionsByUserID :many
+-- keyset pagination over (started_at, id), see handler/pagination.go
+-- started_from and started_to are UTC bounds, started_to is exclusive
+select sessions.*
+from sessions
+inner join users on sessions.user_uuid = users.id
+where
+    users.user_id = sqlc.arg(user_id)
+    and (sqlc.narg(started_from)::timestamp is null or sessions.started_at >= sqlc.narg(started_from)::timestamp)
+    and (sqlc.narg(started_to)::timestamp is null or sessions.started_at < sqlc.narg(started_to)::timestamp)
+    and (
+        sqlc.narg(cursor_time)::timestamp is null
+        or (
+            sqlc.arg(descending)::bool
+            and (sessions.started_at, sessions.id) < (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+        )
+        or (
+            not sqlc.arg(descending)::bool
+            and (sessions.started_at, sessions.id) > (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+order by
+    case when sqlc.arg(descending)::bool then sessions.started_at end desc,
+    case when sqlc.arg(descending)::bool then sessions.id end desc,
+    case when not sqlc.arg(descending)::bool then sessions.started_at end asc,
+    case when not sqlc.arg(descending)::bool then sessions.id end asc
+limit sqlc.arg(page_size);
@@ -0,0 +1,36 @@
+-- name: CreateSongAlias :one
+insert into song_aliases (
+    id,
+    song_id,
+    alias,
+    created_by
+)
+values ($1, $2, $3, $4)
+returning *;
+
+
+-- name: GetSongAliasesBySongID :many
+select *
+from song_aliases
+where song_id = $1
+order by alias;
+
+
+-- name: GetSongAliasByID :one
+select *
+from song_aliases
+where id = $1;
+
+
+-- name: UpdateSongAlias :one
+update song_aliases
+set
+    alias = $2,
+    updated_at = now()
+where id = $1
+returning *;
+
+
+-- name: DeleteSongAlias :execrows
+delete from song_aliases
+where id = $1;
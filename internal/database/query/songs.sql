@@ -13,58 +13,117 @@ insert into songs (
     is_new,
     sort,
     release_date,
-    delete_date
+    delete_date,
+    title_kana,
+    title_romaji,
+    artist_romaji,
+    title_english
 )
-values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 returning *;
 
--- name: GetAllSongs :many
+-- name: ListSongs :many
+-- keyset pagination over (sort_value, id), see handler/pagination.go
 select
-    songs.id,
-    songs.title,
-    songs.artist,
-    songs.genre,
-    songs.bpm,
-    songs.image_url,
-    songs.version,
-    songs.is_utage,
-    songs.is_available,
-    songs.is_new,
-    songs.release_date,
-    songs.delete_date,
-    coalesce(
-        (
-            select
-                json_agg(
-                    jsonb_build_object(
-                        'beatmap_id', beatmaps.id,
-                        'difficulty', beatmaps.difficulty,
-                        'level', beatmaps.level,
-                        'internal_level', beatmaps.internal_level,
-                        'type', beatmaps.type,
-                        'total_notes', beatmaps.total_notes,
-                        'tap', beatmaps.tap,
-                        'hold', beatmaps.hold,
-                        'slide', beatmaps.slide,
-                        'touch', beatmaps.touch,
-                        'break', beatmaps.break,
-                        'note_designer', beatmaps.note_designer,
-                        'max_dx_score', beatmaps.max_dx_score
+    s.id,
+    s.title,
+    s.title_kana,
+    s.title_romaji,
+    s.title_english,
+    s.artist,
+    s.artist_romaji,
+    s.genre,
+    s.bpm,
+    s.image_url,
+    s.version,
+    s.is_utage,
+    s.is_available,
+    s.is_new,
+    s.release_date,
+    s.delete_date,
+    s.beatmaps,
+    s.sort_value
+from (
+    select
+        songs.id,
+        songs.title,
+        songs.title_kana,
+        songs.title_romaji,
+        songs.title_english,
+        songs.artist,
+        songs.artist_romaji,
+        songs.genre,
+        songs.bpm,
+        songs.image_url,
+        songs.version,
+        songs.is_utage,
+        songs.is_available,
+        songs.is_new,
+        songs.release_date,
+        songs.delete_date,
+        coalesce(
+            (
+                select
+                    json_agg(
+                        jsonb_build_object(
+                            'beatmapID', beatmaps.id,
+                            'difficulty', beatmaps.difficulty,
+                            'level', beatmaps.level,
+                            'internalLevel', beatmaps.internal_level,
+                            'type', beatmaps.type,
+                            'totalNotes', beatmaps.total_notes,
+                            'tap', beatmaps.tap,
+                            'hold', beatmaps.hold,
+                            'slide', beatmaps.slide,
+                            'touch', beatmaps.touch,
+                            'break', beatmaps.break,
+                            'noteDesigner', beatmaps.note_designer,
+                            'maxDxScore', beatmaps.max_dx_score
+                        )
                     )
-                )
-            from beatmaps
-            where beatmaps.song_id = songs.id
-        ),
-        '[]'
-    ) as beatmaps
-from songs
-order by songs.release_date desc, songs.sort desc;
+                from beatmaps
+                where beatmaps.song_id = songs.id
+            ),
+            '[]'
+        ) as beatmaps,
+        (case sqlc.arg(sort)::text
+            when 'title' then songs.title
+            when 'romaji' then lower(coalesce(nullif(songs.title_romaji, ''), songs.title))
+            when 'english' then lower(coalesce(songs.title_english, nullif(songs.title_romaji, ''), songs.title))
+            when 'artist' then songs.artist
+            else coalesce(to_char(songs.release_date, 'YYYY-MM-DD'), '') || lpad(songs.sort, 10, '0')
+        end)::text as sort_value
+    from songs
+    where
+        (sqlc.narg(version)::text is null or songs.version = sqlc.narg(version)::text)
+        and (sqlc.narg(genre)::text is null or songs.genre = sqlc.narg(genre)::text)
+) as s
+where
+    sqlc.narg(cursor_value)::text is null
+    or (
+        sqlc.arg(descending)::bool
+        and (s.sort_value, s.id) < (sqlc.narg(cursor_value)::text, sqlc.arg(cursor_id)::uuid)
+    )
+    or (
+        not sqlc.arg(descending)::bool
+        and (s.sort_value, s.id) > (sqlc.narg(cursor_value)::text, sqlc.arg(cursor_id)::uuid)
+    )
+order by
+    case when sqlc.arg(descending)::bool then s.sort_value end desc,
+    case when sqlc.arg(descending)::bool then s.id end desc,
+    case when not sqlc.arg(descending)::bool then s.sort_value end asc,
+    case when not sqlc.arg(descending)::bool then s.id end asc
+limit sqlc.arg(page_size);
 
 -- name: GetSongByID :one
 select
     songs.id,
     songs.title,
+    songs.title_kana,
+    songs.title_romaji,
+    songs.title_english,
     songs.artist,
+    songs.artist_romaji,
     songs.genre,
     songs.bpm,
     songs.image_url,
@@ -133,6 +192,189 @@ set
     sort = $11,
     release_date = $12,
     delete_date = $13,
+    title_kana = $15,
+    title_romaji = $16,
+    artist_romaji = $17,
+    title_english = $18,
     updated_at = now()
 where id = $14
 returning *;
+
+-- name: SearchSongs :many
+-- ranks songs by their best match over title, kana reading, aliases,
+-- romaji and English titles and artist: exact > prefix > substring >
+-- trigram word similarity, with full-text matches on title and artist as
+-- a fallback.
+-- query_pattern is query with LIKE wildcards escaped, kana_query is query
+-- normalized like title_kana (see utils.NormalizeKana), letters only.
+select
+    songs.id,
+    songs.title,
+    songs.title_kana,
+    songs.title_romaji,
+    songs.title_english,
+    songs.artist,
+    songs.artist_romaji,
+    songs.genre,
+    songs.bpm,
+    songs.image_url,
+    songs.version,
+    songs.is_utage,
+    songs.is_available,
+    songs.is_new,
+    songs.release_date,
+    songs.delete_date,
+    m.score,
+    m.matched_by
+from songs
+inner join (
+    select distinct on (c.song_id)
+        c.song_id,
+        c.score,
+        c.matched_by
+    from (
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.title) = lower(sqlc.arg(query)::text) then 1.0
+                when strpos(lower(songs.title), lower(sqlc.arg(query)::text)) = 1 then 0.9
+                when strpos(lower(songs.title), lower(sqlc.arg(query)::text)) > 1 then 0.8
+                else word_similarity(sqlc.arg(query)::text, songs.title) * 0.7
+            end)::float8 as score,
+            'title'::text as matched_by
+        from songs
+        where songs.title ilike '%' || sqlc.arg(query_pattern)::text || '%' or sqlc.arg(query)::text <% songs.title
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when songs.title_kana = sqlc.arg(kana_query)::text then 1.0
+                when strpos(songs.title_kana, sqlc.arg(kana_query)::text) = 1 then 0.9
+                when strpos(songs.title_kana, sqlc.arg(kana_query)::text) > 1 then 0.8
+                else word_similarity(sqlc.arg(kana_query)::text, songs.title_kana) * 0.7
+            end)::float8 as score,
+            'titleKana'::text as matched_by
+        from songs
+        where
+            sqlc.arg(kana_query)::text <> ''
+            and (
+                songs.title_kana like '%' || sqlc.arg(kana_query)::text || '%'
+                or sqlc.arg(kana_query)::text <% songs.title_kana
+            )
+        union all
+        select
+            song_aliases.song_id as song_id,
+            (case
+                when lower(song_aliases.alias) = lower(sqlc.arg(query)::text) then 1.0
+                when strpos(lower(song_aliases.alias), lower(sqlc.arg(query)::text)) = 1 then 0.9
+                when strpos(lower(song_aliases.alias), lower(sqlc.arg(query)::text)) > 1 then 0.8
+                else word_similarity(sqlc.arg(query)::text, song_aliases.alias) * 0.7
+            end)::float8 as score,
+            'alias'::text as matched_by
+        from song_aliases
+        where song_aliases.alias ilike '%' || sqlc.arg(query_pattern)::text || '%' or sqlc.arg(query)::text <% song_aliases.alias
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.title_romaji) = lower(sqlc.arg(query)::text) then 1.0
+                when strpos(lower(songs.title_romaji), lower(sqlc.arg(query)::text)) = 1 then 0.9
+                when strpos(lower(songs.title_romaji), lower(sqlc.arg(query)::text)) > 1 then 0.8
+                else word_similarity(sqlc.arg(query)::text, songs.title_romaji) * 0.7
+            end)::float8 as score,
+            'titleRomaji'::text as matched_by
+        from songs
+        where songs.title_romaji ilike '%' || sqlc.arg(query_pattern)::text || '%' or sqlc.arg(query)::text <% songs.title_romaji
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.title_english) = lower(sqlc.arg(query)::text) then 1.0
+                when strpos(lower(songs.title_english), lower(sqlc.arg(query)::text)) = 1 then 0.9
+                when strpos(lower(songs.title_english), lower(sqlc.arg(query)::text)) > 1 then 0.8
+                else word_similarity(sqlc.arg(query)::text, songs.title_english) * 0.7
+            end)::float8 as score,
+            'titleEnglish'::text as matched_by
+        from songs
+        where songs.title_english ilike '%' || sqlc.arg(query_pattern)::text || '%' or sqlc.arg(query)::text <% songs.title_english
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.artist) = lower(sqlc.arg(query)::text) then 1.0
+                when strpos(lower(songs.artist), lower(sqlc.arg(query)::text)) = 1 then 0.9
+                when strpos(lower(songs.artist), lower(sqlc.arg(query)::text)) > 1 then 0.8
+                else word_similarity(sqlc.arg(query)::text, songs.artist) * 0.7
+            end * 0.8)::float8 as score,
+            'artist'::text as matched_by
+        from songs
+        where songs.artist ilike '%' || sqlc.arg(query_pattern)::text || '%' or sqlc.arg(query)::text <% songs.artist
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.artist_romaji) = lower(sqlc.arg(query)::text) then 1.0
+                when strpos(lower(songs.artist_romaji), lower(sqlc.arg(query)::text)) = 1 then 0.9
+                when strpos(lower(songs.artist_romaji), lower(sqlc.arg(query)::text)) > 1 then 0.8
+                else word_similarity(sqlc.arg(query)::text, songs.artist_romaji) * 0.7
+            end * 0.8)::float8 as score,
+            'artistRomaji'::text as matched_by
+        from songs
+        where songs.artist_romaji ilike '%' || sqlc.arg(query_pattern)::text || '%' or sqlc.arg(query)::text <% songs.artist_romaji
+        union all
+        select
+            songs.id as song_id,
+            (ts_rank(
+                to_tsvector('simple', songs.title || ' ' || songs.artist),
+                plainto_tsquery('simple', sqlc.arg(query)::text)
+            ) * 0.5)::float8 as score,
+            'fullText'::text as matched_by
+        from songs
+        where to_tsvector('simple', songs.title || ' ' || songs.artist) @@ plainto_tsquery('simple', sqlc.arg(query)::text)
+    ) as c
+    order by c.song_id, c.score desc
+) as m on songs.id = m.song_id
+order by m.score desc, songs.title
+limit sqlc.arg(max_results);
+
+-- name: UpdateSongTitleEnglish :one
+update songs
+set
+    title_english = $2,
+    updated_at = now()
+where id = $1
+returning *;
+
+-- name: GetPlateChartsByUserID :many
+-- BASIC to MASTER charts of available songs with the user's best
+-- achievement and lamp, null when unplayed. lamp is ordered like
+-- calculator.Lamp. Versions are ordered by their first release.
+select
+    songs.version,
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.internal_level,
+    beatmaps.type,
+    b.accuracy,
+    b.achievement,
+    b.lamp
+from beatmaps
+inner join songs on beatmaps.song_id = songs.id
+cross join users
+left join lateral chart_best(beatmaps.id, users.id) as b on true
+where
+    users.user_id = sqlc.arg(user_id)
+    and songs.is_available
+    and not songs.is_utage
+    and beatmaps.difficulty in ('basic', 'advanced', 'expert', 'master')
+    and (sqlc.narg(version)::text is null or songs.version = sqlc.narg(version)::text)
+order by
+    min(songs.release_date) over (partition by songs.version) nulls last,
+    songs.version,
+    array_position(array['basic', 'advanced', 'expert', 'master'], beatmaps.difficulty),
+    songs.sort,
+    beatmaps.type;
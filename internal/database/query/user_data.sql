@@ -21,3 +21,116 @@ from user_data
 where user_uuid = $1
 order by created_at desc
 limit 1;
+
+-- name: GetUserDataHistoryByUserID :many
+-- snapshots oldest first, skipping the ones that did not change since the
+-- previous snapshot. created_before is an exclusive UTC bound.
+select
+    d.id,
+    d.rating,
+    d.season_play_count,
+    d.total_play_count,
+    d.created_at
+from (
+    select
+        user_data.id,
+        user_data.rating,
+        user_data.season_play_count,
+        user_data.total_play_count,
+        user_data.created_at,
+        lag(user_data.rating) over w as previous_rating,
+        lag(user_data.season_play_count) over w as previous_season_play_count,
+        lag(user_data.total_play_count) over w as previous_total_play_count
+    from user_data
+    inner join users on user_data.user_uuid = users.id
+    where
+        users.user_id = sqlc.arg(user_id)
+        and (
+            sqlc.narg(created_before)::timestamp is null
+            or user_data.created_at < sqlc.narg(created_before)::timestamp
+        )
+    window w as (order by user_data.created_at, user_data.id)
+) as d
+where
+    d.previous_rating is null
+    or (d.rating, d.season_play_count, d.total_play_count)
+    is distinct from (d.previous_rating, d.previous_season_play_count, d.previous_total_play_count)
+order by d.created_at, d.id;
+
+
+-- name: GetRatingLeaderboard :many
+-- users by the rating of their latest user_data snapshot. users whose
+-- profile is not public only show up for themselves (viewer_id). keyset
+-- pagination over (rating, user_id), the rating negated as it descends.
+with ratings as (
+    select
+        users.id,
+        users.user_id,
+        users.display_name,
+        d.rating
+    from users
+    left join user_privacy p on users.id = p.user_uuid
+    inner join lateral (
+        select user_data.rating
+        from user_data
+        where user_data.user_uuid = users.id
+        order by user_data.created_at desc
+        limit 1
+    ) as d on true
+    where
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or users.user_id = sqlc.narg(viewer_id)::text
+        or (p.profile_visibility = 'friends' and is_friend(users.id, sqlc.narg(viewer_id)::text))
+),
+ranked as (
+    select
+        ratings.id,
+        ratings.user_id,
+        ratings.display_name,
+        ratings.rating,
+        rank() over (order by ratings.rating desc) as rank
+    from ratings
+)
+select ranked.*
+from ranked
+where
+    sqlc.narg(cursor_rating)::int is null
+    or (-ranked.rating, ranked.user_id) > (-sqlc.narg(cursor_rating)::int, sqlc.arg(cursor_user_id)::text)
+order by ranked.rating desc, ranked.user_id
+limit sqlc.arg(page_size);
+
+
+-- name: GetRatingLeaderboardEntry :one
+-- the viewer's row of GetRatingLeaderboard
+with ratings as (
+    select
+        users.id,
+        users.user_id,
+        users.display_name,
+        d.rating
+    from users
+    left join user_privacy p on users.id = p.user_uuid
+    inner join lateral (
+        select user_data.rating
+        from user_data
+        where user_data.user_uuid = users.id
+        order by user_data.created_at desc
+        limit 1
+    ) as d on true
+    where
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or users.user_id = sqlc.narg(viewer_id)::text
+        or (p.profile_visibility = 'friends' and is_friend(users.id, sqlc.narg(viewer_id)::text))
+),
+ranked as (
+    select
+        ratings.id,
+        ratings.user_id,
+        ratings.display_name,
+        ratings.rating,
+        rank() over (order by ratings.rating desc) as rank
+    from ratings
+)
+select ranked.*
+from ranked
+where ranked.user_id = sqlc.narg(viewer_id)::text;
@@ -0,0 +1,39 @@
+-- name: CreateUserPrivacy :one
+insert into user_privacy (user_uuid)
+values ($1)
+returning *;
+
+
+-- name: GetUserPrivacyByUserUUID :one
+select *
+from user_privacy
+where user_uuid = $1;
+
+
+-- name: GetUserPrivacyByUserID :one
+select
+    u.id as user_uuid,
+    u.user_id,
+    coalesce(p.profile_visibility, 'public')::text as profile_visibility,
+    coalesce(p.scores_visibility, 'public')::text as scores_visibility,
+    coalesce(p.rating_history_visibility, 'public')::text as rating_history_visibility
+from users u
+left join user_privacy p on u.id = p.user_uuid
+where u.user_id = $1;
+
+
+-- name: UpsertUserPrivacy :one
+insert into user_privacy (
+    user_uuid,
+    profile_visibility,
+    scores_visibility,
+    rating_history_visibility
+)
+values ($1, $2, $3, $4)
+on conflict (user_uuid) do update
+set
+    profile_visibility = excluded.profile_visibility,
+    scores_visibility = excluded.scores_visibility,
+    rating_history_visibility = excluded.rating_history_visibility,
+    updated_at = now()
+returning *;
@@ -0,0 +1,22 @@
+-- name: GetUserRoleByUserID :one
+select coalesce(r.role, 'user')::text as role
+from users u
+left join user_roles r on u.id = r.user_uuid
+where u.user_id = $1;
+
+
+-- name: UpsertUserRoleByUserID :one
+insert into user_roles (user_uuid, role)
+select id, sqlc.arg(role)::text
+from users
+where user_id = sqlc.arg(user_id)
+on conflict (user_uuid) do update
+set
+    role = excluded.role,
+    updated_at = now()
+returning *;
+
+
+-- name: DeleteUserRoleByUserID :exec
+delete from user_roles
+where user_uuid = (select id from users where user_id = $1);
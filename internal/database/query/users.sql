@@ -26,6 +26,69 @@ select
 from users;
 
 
+-- name: ListUsers :many
+-- keyset pagination over (sort_number, id), (sort_time, id) or
+-- (sort_value, id), see handler/pagination.go
+select
+    u.id,
+    u.user_id,
+    u.display_name,
+    u.last_played_at,
+    u.rating,
+    u.sort_number,
+    u.sort_time,
+    u.sort_value
+from (
+    select
+        users.id,
+        users.user_id,
+        users.display_name,
+        users.last_played_at,
+        d.rating,
+        (case when sqlc.arg(sort)::text = 'rating' then coalesce(d.rating, 0) end)::numeric as sort_number,
+        (case when sqlc.arg(sort)::text = 'lastPlayedAt' then users.last_played_at end)::timestamp as sort_time,
+        (case when sqlc.arg(sort)::text = 'userID' then users.user_id end)::text as sort_value
+    from users
+    left join user_privacy p on users.id = p.user_uuid
+    left join lateral (
+        select user_data.rating
+        from user_data
+        where user_data.user_uuid = users.id
+        order by user_data.created_at desc
+        limit 1
+    ) as d on true
+    where coalesce(p.profile_visibility, 'public') = 'public'
+) as u
+where
+    sqlc.narg(cursor_value)::text is null
+    or (
+        sqlc.arg(descending)::bool
+        and (
+            (u.sort_number, u.id) < (sqlc.narg(cursor_number)::numeric, sqlc.arg(cursor_id)::uuid)
+            or (u.sort_time, u.id) < (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+            or (u.sort_value, u.id) < (sqlc.narg(cursor_value)::text, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+    or (
+        not sqlc.arg(descending)::bool
+        and (
+            (u.sort_number, u.id) > (sqlc.narg(cursor_number)::numeric, sqlc.arg(cursor_id)::uuid)
+            or (u.sort_time, u.id) > (sqlc.narg(cursor_time)::timestamp, sqlc.arg(cursor_id)::uuid)
+            or (u.sort_value, u.id) > (sqlc.narg(cursor_value)::text, sqlc.arg(cursor_id)::uuid)
+        )
+    )
+order by
+    case when sqlc.arg(descending)::bool then u.sort_number end desc,
+    case when sqlc.arg(descending)::bool then u.sort_time end desc,
+    case when sqlc.arg(descending)::bool then u.sort_value end desc,
+    case when sqlc.arg(descending)::bool then u.id end desc,
+    case when not sqlc.arg(descending)::bool then u.sort_number end asc,
+    case when not sqlc.arg(descending)::bool then u.sort_time end asc,
+    case when not sqlc.arg(descending)::bool then u.sort_value end asc,
+    case when not sqlc.arg(descending)::bool then u.id end asc
+limit sqlc.arg(page_size);
+
+
 -- name: GetUserByID :one
 select
     u.id,
@@ -98,6 +161,26 @@ from users
 where user_id = $1;
 
 
+-- name: GetSegaCredentialsBatch :many
+select
+    id,
+    encrypted_sega_id,
+    encrypted_sega_password
+from users
+where id > $1
+order by id
+limit $2;
+
+
+-- name: UpdateSegaCredentials :exec
+update users
+set
+    encrypted_sega_id = $2,
+    encrypted_sega_password = $3,
+    updated_at = now()
+where id = $1;
+
+
 -- name: UpdateUserByUUID :one
 update users
 set
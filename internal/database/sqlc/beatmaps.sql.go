@@ -89,96 +89,6 @@ func (q *Queries) CreateBeatmap(ctx context.Context, arg CreateBeatmapParams) (B
 	return i, err
 }
 
-const getAllBeatmaps = `-- name: GetAllBeatmaps :many
-select
-    beatmaps.id,
-    beatmaps.song_id,
-    beatmaps.difficulty,
-    beatmaps.level,
-    beatmaps.internal_level,
-    beatmaps.type,
-    beatmaps.total_notes,
-    beatmaps.tap,
-    beatmaps.hold,
-    beatmaps.slide,
-    beatmaps.touch,
-    beatmaps.break,
-    beatmaps.note_designer,
-    beatmaps.max_dx_score,
-    songs.title,
-    songs.artist,
-    songs.genre,
-    songs.bpm,
-    songs.image_url,
-    songs.version
-from beatmaps
-inner join songs on beatmaps.song_id = songs.id
-`
-
-type GetAllBeatmapsRow struct {
-	ID            uuid.UUID      `json:"id"`
-	SongID        uuid.UUID      `json:"songID"`
-	Difficulty    string         `json:"difficulty"`
-	Level         string         `json:"level"`
-	InternalLevel pgtype.Numeric `json:"internalLevel"`
-	Type          string         `json:"type"`
-	TotalNotes    int32          `json:"totalNotes"`
-	Tap           int32          `json:"tap"`
-	Hold          int32          `json:"hold"`
-	Slide         int32          `json:"slide"`
-	Touch         int32          `json:"touch"`
-	Break         int32          `json:"break"`
-	NoteDesigner  string         `json:"noteDesigner"`
-	MaxDxScore    int32          `json:"maxDxScore"`
-	Title         string         `json:"title"`
-	Artist        string         `json:"artist"`
-	Genre         string         `json:"genre"`
-	Bpm           string         `json:"bpm"`
-	ImageUrl      string         `json:"imageUrl"`
-	Version       string         `json:"version"`
-}
-
-func (q *Queries) GetAllBeatmaps(ctx context.Context) ([]GetAllBeatmapsRow, error) {
-	rows, err := q.db.Query(ctx, getAllBeatmaps)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllBeatmapsRow
-	for rows.Next() {
-		var i GetAllBeatmapsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.SongID,
-			&i.Difficulty,
-			&i.Level,
-			&i.InternalLevel,
-			&i.Type,
-			&i.TotalNotes,
-			&i.Tap,
-			&i.Hold,
-			&i.Slide,
-			&i.Touch,
-			&i.Break,
-			&i.NoteDesigner,
-			&i.MaxDxScore,
-			&i.Title,
-			&i.Artist,
-			&i.Genre,
-			&i.Bpm,
-			&i.ImageUrl,
-			&i.Version,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getBeatmapByBeatmapID = `-- name: GetBeatmapByBeatmapID :one
 select
     beatmaps.id,
@@ -316,7 +226,7 @@ select
     songs.image_url,
     songs.version
 from beatmaps
-inner join songs on beatmaps.song_id = songs.song_id
+inner join songs on beatmaps.song_id = songs.id
 where beatmaps.song_id = $1
 `
 
@@ -384,6 +294,295 @@ func (q *Queries) GetBeatmapsBySongID(ctx context.Context, songID uuid.UUID) ([]
 	return items, nil
 }
 
+const getLevelTableByUserID = `-- name: GetLevelTableByUserID :many
+select
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.internal_level,
+    beatmaps.type,
+    beatmaps.max_dx_score,
+    b.accuracy,
+    b.achievement,
+    b.lamp,
+    b.dx_score
+from beatmaps
+inner join songs on beatmaps.song_id = songs.id
+cross join users
+left join lateral chart_best(beatmaps.id, users.id) as b on true
+where
+    users.user_id = $1
+    and songs.is_available
+    and beatmaps.difficulty <> 'utage'
+    and ($2::text is null or beatmaps.level = $2::text)
+    and ($3::numeric is null or beatmaps.internal_level >= $3::numeric)
+    and ($4::numeric is null or beatmaps.internal_level <= $4::numeric)
+    and ($5::text is null or beatmaps.difficulty = $5::text)
+    and ($6::text is null or beatmaps.type = $6::text)
+order by beatmaps.internal_level desc nulls last, songs.sort, beatmaps.difficulty, beatmaps.type
+`
+
+type GetLevelTableByUserIDParams struct {
+	UserID     string         `json:"userID"`
+	Level      pgtype.Text    `json:"level"`
+	LevelMin   pgtype.Numeric `json:"levelMin"`
+	LevelMax   pgtype.Numeric `json:"levelMax"`
+	Difficulty pgtype.Text    `json:"difficulty"`
+	Type       pgtype.Text    `json:"type"`
+}
+
+type GetLevelTableByUserIDRow struct {
+	BeatmapID     uuid.UUID      `json:"beatmapID"`
+	SongID        uuid.UUID      `json:"songID"`
+	Title         string         `json:"title"`
+	ImageUrl      string         `json:"imageUrl"`
+	Difficulty    string         `json:"difficulty"`
+	Level         string         `json:"level"`
+	InternalLevel pgtype.Numeric `json:"internalLevel"`
+	Type          string         `json:"type"`
+	MaxDxScore    int32          `json:"maxDxScore"`
+	Accuracy      pgtype.Text    `json:"accuracy"`
+	Achievement   pgtype.Float8  `json:"achievement"`
+	Lamp          pgtype.Int4    `json:"lamp"`
+	DxScore       pgtype.Int4    `json:"dxScore"`
+}
+
+// charts of available songs in a level bucket with the user's best
+// achievement, lamp and DX score, each null when unplayed. level is the
+// level shown in game, level_min and level_max bound the internal level.
+// lamp is ordered like calculator.Lamp. Hardest first.
+func (q *Queries) GetLevelTableByUserID(ctx context.Context, arg GetLevelTableByUserIDParams) ([]GetLevelTableByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getLevelTableByUserID,
+		arg.UserID,
+		arg.Level,
+		arg.LevelMin,
+		arg.LevelMax,
+		arg.Difficulty,
+		arg.Type,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLevelTableByUserIDRow
+	for rows.Next() {
+		var i GetLevelTableByUserIDRow
+		if err := rows.Scan(
+			&i.BeatmapID,
+			&i.SongID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Difficulty,
+			&i.Level,
+			&i.InternalLevel,
+			&i.Type,
+			&i.MaxDxScore,
+			&i.Accuracy,
+			&i.Achievement,
+			&i.Lamp,
+			&i.DxScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBeatmaps = `-- name: ListBeatmaps :many
+select
+    b.id,
+    b.song_id,
+    b.difficulty,
+    b.level,
+    b.internal_level,
+    b.type,
+    b.total_notes,
+    b.tap,
+    b.hold,
+    b.slide,
+    b.touch,
+    b.break,
+    b.note_designer,
+    b.max_dx_score,
+    b.title,
+    b.artist,
+    b.genre,
+    b.bpm,
+    b.image_url,
+    b.version,
+    b.sort_number,
+    b.sort_value
+from (
+    select
+        beatmaps.id,
+        beatmaps.song_id,
+        beatmaps.difficulty,
+        beatmaps.level,
+        beatmaps.internal_level,
+        beatmaps.type,
+        beatmaps.total_notes,
+        beatmaps.tap,
+        beatmaps.hold,
+        beatmaps.slide,
+        beatmaps.touch,
+        beatmaps.break,
+        beatmaps.note_designer,
+        beatmaps.max_dx_score,
+        songs.title,
+        songs.artist,
+        songs.genre,
+        songs.bpm,
+        songs.image_url,
+        songs.version,
+        (case when $1::text = 'level' then coalesce(beatmaps.internal_level, 0) end)::numeric as sort_number,
+        (case $1::text
+            when 'title' then songs.title
+            when 'releaseDate' then coalesce(to_char(songs.release_date, 'YYYY-MM-DD'), '') || lpad(songs.sort, 10, '0')
+        end)::text as sort_value
+    from beatmaps
+    inner join songs on beatmaps.song_id = songs.id
+    where
+        ($2::text is null or songs.version = $2::text)
+        and ($3::text is null or songs.genre = $3::text)
+        and ($4::text is null or beatmaps.difficulty = $4::text)
+        and ($5::text is null or beatmaps.type = $5::text)
+        and ($6::text is null or beatmaps.level = $6::text)
+        and ($7::numeric is null or beatmaps.internal_level >= $7::numeric)
+        and ($8::numeric is null or beatmaps.internal_level <= $8::numeric)
+) as b
+where
+    $9::text is null
+    or (
+        $10::bool
+        and (
+            (b.sort_number, b.id) < ($11::numeric, $12::uuid)
+            or (b.sort_value, b.id) < ($9::text, $12::uuid)
+        )
+    )
+    or (
+        not $10::bool
+        and (
+            (b.sort_number, b.id) > ($11::numeric, $12::uuid)
+            or (b.sort_value, b.id) > ($9::text, $12::uuid)
+        )
+    )
+order by
+    case when $10::bool then b.sort_number end desc,
+    case when $10::bool then b.sort_value end desc,
+    case when $10::bool then b.id end desc,
+    case when not $10::bool then b.sort_number end asc,
+    case when not $10::bool then b.sort_value end asc,
+    case when not $10::bool then b.id end asc
+limit $13
+`
+
+type ListBeatmapsParams struct {
+	Sort         string         `json:"sort"`
+	Version      pgtype.Text    `json:"version"`
+	Genre        pgtype.Text    `json:"genre"`
+	Difficulty   pgtype.Text    `json:"difficulty"`
+	Type         pgtype.Text    `json:"type"`
+	Level        pgtype.Text    `json:"level"`
+	LevelMin     pgtype.Numeric `json:"levelMin"`
+	LevelMax     pgtype.Numeric `json:"levelMax"`
+	CursorValue  pgtype.Text    `json:"cursorValue"`
+	Descending   bool           `json:"descending"`
+	CursorNumber pgtype.Numeric `json:"cursorNumber"`
+	CursorID     uuid.UUID      `json:"cursorID"`
+	PageSize     int32          `json:"pageSize"`
+}
+
+type ListBeatmapsRow struct {
+	ID            uuid.UUID      `json:"id"`
+	SongID        uuid.UUID      `json:"songID"`
+	Difficulty    string         `json:"difficulty"`
+	Level         string         `json:"level"`
+	InternalLevel pgtype.Numeric `json:"internalLevel"`
+	Type          string         `json:"type"`
+	TotalNotes    int32          `json:"totalNotes"`
+	Tap           int32          `json:"tap"`
+	Hold          int32          `json:"hold"`
+	Slide         int32          `json:"slide"`
+	Touch         int32          `json:"touch"`
+	Break         int32          `json:"break"`
+	NoteDesigner  string         `json:"noteDesigner"`
+	MaxDxScore    int32          `json:"maxDxScore"`
+	Title         string         `json:"title"`
+	Artist        string         `json:"artist"`
+	Genre         string         `json:"genre"`
+	Bpm           string         `json:"bpm"`
+	ImageUrl      string         `json:"imageUrl"`
+	Version       string         `json:"version"`
+	SortNumber    pgtype.Numeric `json:"sortNumber"`
+	SortValue     pgtype.Text    `json:"sortValue"`
+}
+
+// keyset pagination over (sort_number, id) or (sort_value, id), see
+// handler/pagination.go
+func (q *Queries) ListBeatmaps(ctx context.Context, arg ListBeatmapsParams) ([]ListBeatmapsRow, error) {
+	rows, err := q.db.Query(ctx, listBeatmaps,
+		arg.Sort,
+		arg.Version,
+		arg.Genre,
+		arg.Difficulty,
+		arg.Type,
+		arg.Level,
+		arg.LevelMin,
+		arg.LevelMax,
+		arg.CursorValue,
+		arg.Descending,
+		arg.CursorNumber,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBeatmapsRow
+	for rows.Next() {
+		var i ListBeatmapsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SongID,
+			&i.Difficulty,
+			&i.Level,
+			&i.InternalLevel,
+			&i.Type,
+			&i.TotalNotes,
+			&i.Tap,
+			&i.Hold,
+			&i.Slide,
+			&i.Touch,
+			&i.Break,
+			&i.NoteDesigner,
+			&i.MaxDxScore,
+			&i.Title,
+			&i.Artist,
+			&i.Genre,
+			&i.Bpm,
+			&i.ImageUrl,
+			&i.Version,
+			&i.SortNumber,
+			&i.SortValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateBeatmap = `-- name: UpdateBeatmap :one
 update beatmaps
 set
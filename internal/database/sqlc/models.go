@@ -28,6 +28,61 @@ type Beatmap struct {
 	CreatedAt     pgtype.Timestamp `json:"createdAt"`
 }
 
+type FeedItem struct {
+	ID               uuid.UUID        `json:"id"`
+	UserUuid         uuid.UUID        `json:"userUuid"`
+	Kind             string           `json:"kind"`
+	ScoreID          pgtype.UUID      `json:"scoreID"`
+	BeatmapID        pgtype.UUID      `json:"beatmapID"`
+	Accuracy         pgtype.Text      `json:"accuracy"`
+	PreviousAccuracy pgtype.Text      `json:"previousAccuracy"`
+	Rank             pgtype.Text      `json:"rank"`
+	Lamp             pgtype.Text      `json:"lamp"`
+	Rating           pgtype.Int4      `json:"rating"`
+	CreatedAt        pgtype.Timestamp `json:"createdAt"`
+}
+
+type Follow struct {
+	FollowerUuid uuid.UUID        `json:"followerUuid"`
+	FolloweeUuid uuid.UUID        `json:"followeeUuid"`
+	CreatedAt    pgtype.Timestamp `json:"createdAt"`
+}
+
+type Goal struct {
+	ID           uuid.UUID        `json:"id"`
+	UserUuid     uuid.UUID        `json:"userUuid"`
+	Kind         string           `json:"kind"`
+	Title        string           `json:"title"`
+	TargetRating pgtype.Int4      `json:"targetRating"`
+	BeatmapID    pgtype.UUID      `json:"beatmapID"`
+	TargetRank   pgtype.Text      `json:"targetRank"`
+	TargetLamp   pgtype.Text      `json:"targetLamp"`
+	Difficulty   pgtype.Text      `json:"difficulty"`
+	Type         pgtype.Text      `json:"type"`
+	Level        pgtype.Text      `json:"level"`
+	LevelMin     pgtype.Numeric   `json:"levelMin"`
+	LevelMax     pgtype.Numeric   `json:"levelMax"`
+	TargetCount  pgtype.Int4      `json:"targetCount"`
+	Progress     int32            `json:"progress"`
+	Target       int32            `json:"target"`
+	CompletedAt  pgtype.Timestamp `json:"completedAt"`
+	EvaluatedAt  pgtype.Timestamp `json:"evaluatedAt"`
+	UpdatedAt    pgtype.Timestamp `json:"updatedAt"`
+	CreatedAt    pgtype.Timestamp `json:"createdAt"`
+}
+
+type RateLimit struct {
+	Key         string           `json:"key"`
+	WindowStart pgtype.Timestamp `json:"windowStart"`
+	Count       int32            `json:"count"`
+}
+
+type Rival struct {
+	UserUuid  uuid.UUID        `json:"userUuid"`
+	RivalUuid uuid.UUID        `json:"rivalUuid"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
+}
+
 type Score struct {
 	ID            uuid.UUID        `json:"id"`
 	BeatmapID     uuid.UUID        `json:"beatmapID"`
@@ -65,25 +120,57 @@ type Score struct {
 	Late          int32            `json:"late"`
 	PlayedAt      pgtype.Timestamp `json:"playedAt"`
 	CreatedAt     pgtype.Timestamp `json:"createdAt"`
+	Achievement   pgtype.Numeric   `json:"achievement"`
+	FlagReason    pgtype.Text      `json:"flagReason"`
+	Track         pgtype.Int4      `json:"track"`
+	SessionID     pgtype.UUID      `json:"sessionID"`
+}
+
+type Session struct {
+	ID            uuid.UUID        `json:"id"`
+	UserUuid      uuid.UUID        `json:"userUuid"`
+	StartedAt     pgtype.Timestamp `json:"startedAt"`
+	EndedAt       pgtype.Timestamp `json:"endedAt"`
+	Plays         int32            `json:"plays"`
+	Credits       int32            `json:"credits"`
+	Charts        int32            `json:"charts"`
+	PersonalBests int32            `json:"personalBests"`
+	RatingBefore  pgtype.Int4      `json:"ratingBefore"`
+	RatingAfter   pgtype.Int4      `json:"ratingAfter"`
+	UpdatedAt     pgtype.Timestamp `json:"updatedAt"`
+	CreatedAt     pgtype.Timestamp `json:"createdAt"`
 }
 
 type Song struct {
-	ID          uuid.UUID        `json:"id"`
-	AltKey      string           `json:"altKey"`
-	Title       string           `json:"title"`
-	Artist      string           `json:"artist"`
-	Genre       string           `json:"genre"`
-	Bpm         string           `json:"bpm"`
-	ImageUrl    string           `json:"imageUrl"`
-	Version     string           `json:"version"`
-	Sort        string           `json:"sort"`
-	IsUtage     bool             `json:"isUtage"`
-	IsAvailable bool             `json:"isAvailable"`
-	IsNew       bool             `json:"isNew"`
-	ReleaseDate pgtype.Date      `json:"releaseDate"`
-	DeleteDate  pgtype.Date      `json:"deleteDate"`
-	UpdatedAt   pgtype.Timestamp `json:"updatedAt"`
-	CreatedAt   pgtype.Timestamp `json:"createdAt"`
+	ID           uuid.UUID        `json:"id"`
+	AltKey       string           `json:"altKey"`
+	Title        string           `json:"title"`
+	Artist       string           `json:"artist"`
+	Genre        string           `json:"genre"`
+	Bpm          string           `json:"bpm"`
+	ImageUrl     string           `json:"imageUrl"`
+	Version      string           `json:"version"`
+	Sort         string           `json:"sort"`
+	IsUtage      bool             `json:"isUtage"`
+	IsAvailable  bool             `json:"isAvailable"`
+	IsNew        bool             `json:"isNew"`
+	ReleaseDate  pgtype.Date      `json:"releaseDate"`
+	DeleteDate   pgtype.Date      `json:"deleteDate"`
+	UpdatedAt    pgtype.Timestamp `json:"updatedAt"`
+	CreatedAt    pgtype.Timestamp `json:"createdAt"`
+	TitleKana    string           `json:"titleKana"`
+	TitleRomaji  string           `json:"titleRomaji"`
+	ArtistRomaji string           `json:"artistRomaji"`
+	TitleEnglish pgtype.Text      `json:"titleEnglish"`
+}
+
+type SongAlias struct {
+	ID        uuid.UUID        `json:"id"`
+	SongID    uuid.UUID        `json:"songID"`
+	Alias     string           `json:"alias"`
+	CreatedBy pgtype.UUID      `json:"createdBy"`
+	UpdatedAt pgtype.Timestamp `json:"updatedAt"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
 }
 
 type User struct {
@@ -121,3 +208,19 @@ type UserMetadatum struct {
 	UpdatedAt       pgtype.Timestamp `json:"updatedAt"`
 	CreatedAt       pgtype.Timestamp `json:"createdAt"`
 }
+
+type UserPrivacy struct {
+	UserUuid                uuid.UUID        `json:"userUuid"`
+	ProfileVisibility       string           `json:"profileVisibility"`
+	ScoresVisibility        string           `json:"scoresVisibility"`
+	RatingHistoryVisibility string           `json:"ratingHistoryVisibility"`
+	UpdatedAt               pgtype.Timestamp `json:"updatedAt"`
+	CreatedAt               pgtype.Timestamp `json:"createdAt"`
+}
+
+type UserRole struct {
+	UserUuid  uuid.UUID        `json:"userUuid"`
+	Role      string           `json:"role"`
+	UpdatedAt pgtype.Timestamp `json:"updatedAt"`
+	CreatedAt pgtype.Timestamp `json:"createdAt"`
+}
@@ -48,7 +48,10 @@ insert into scores (
     break_miss,
     fast,
     late,
-    played_at
+    played_at,
+    flag_reason,
+    track,
+    session_id
 )
 values (
     $1, $2, $3, $4, $5, $6, $7,
@@ -57,9 +60,9 @@ values (
     $18, $19, $20, $21, $22,
     $23, $24, $25, $26, $27,
     $28, $29, $30, $31, $32,
-    $33, $34, $35
+    $33, $34, $35, $36, $37, $38
 )
-returning id, beatmap_id, song_id, user_uuid, accuracy, max_combo, dx_score, tap_critical, tap_perfect, tap_great, tap_good, tap_miss, hold_critical, hold_perfect, hold_great, hold_good, hold_miss, slide_critical, slide_perfect, slide_great, slide_good, slide_miss, touch_critical, touch_perfect, touch_great, touch_good, touch_miss, break_critical, break_perfect, break_great, break_good, break_miss, fast, late, played_at, created_at
+returning id, beatmap_id, song_id, user_uuid, accuracy, max_combo, dx_score, tap_critical, tap_perfect, tap_great, tap_good, tap_miss, hold_critical, hold_perfect, hold_great, hold_good, hold_miss, slide_critical, slide_perfect, slide_great, slide_good, slide_miss, touch_critical, touch_perfect, touch_great, touch_good, touch_miss, break_critical, break_perfect, break_great, break_good, break_miss, fast, late, played_at, created_at, achievement, flag_reason, track, session_id
 `
 
 type CreateScoreParams struct {
@@ -98,6 +101,9 @@ type CreateScoreParams struct {
 	Fast          int32            `json:"fast"`
 	Late          int32            `json:"late"`
 	PlayedAt      pgtype.Timestamp `json:"playedAt"`
+	FlagReason    pgtype.Text      `json:"flagReason"`
+	Track         pgtype.Int4      `json:"track"`
+	SessionID     pgtype.UUID      `json:"sessionID"`
 }
 
 func (q *Queries) CreateScore(ctx context.Context, arg CreateScoreParams) (Score, error) {
@@ -137,6 +143,9 @@ func (q *Queries) CreateScore(ctx context.Context, arg CreateScoreParams) (Score
 		arg.Fast,
 		arg.Late,
 		arg.PlayedAt,
+		arg.FlagReason,
+		arg.Track,
+		arg.SessionID,
 	)
 	var i Score
 	err := row.Scan(
@@ -176,69 +185,931 @@ func (q *Queries) CreateScore(ctx context.Context, arg CreateScoreParams) (Score
 		&i.Late,
 		&i.PlayedAt,
 		&i.CreatedAt,
+		&i.Achievement,
+		&i.FlagReason,
+		&i.Track,
+		&i.SessionID,
 	)
 	return i, err
 }
 
-const getScoresByUserID = `-- name: GetScoresByUserID :many
+const getBeatmapLeaderboard = `-- name: GetBeatmapLeaderboard :many
+with bests as (
+    select distinct on (scores.user_uuid)
+        scores.id,
+        scores.user_uuid,
+        scores.accuracy,
+        scores.dx_score,
+        scores.played_at,
+        coalesce(scores.achievement, 0) as achievement
+    from scores
+    inner join users on scores.user_uuid = users.id
+    left join user_privacy p on users.id = p.user_uuid
+    where
+        scores.beatmap_id = $1
+        and scores.flag_reason is null
+        and (
+            coalesce(p.scores_visibility, 'public') = 'public'
+            or users.user_id = $2::text
+            or (p.scores_visibility = 'friends' and is_friend(users.id, $2::text))
+        )
+    order by scores.user_uuid, achievement desc, scores.dx_score desc, scores.played_at
+),
+ranked as (
+    select
+        bests.id,
+        users.user_id,
+        users.display_name,
+        bests.accuracy,
+        bests.achievement,
+        bests.dx_score,
+        bests.played_at,
+        rank() over (order by bests.achievement desc, bests.dx_score desc, bests.played_at) as rank
+    from bests
+    inner join users on bests.user_uuid = users.id
+)
+select ranked.id, ranked.user_id, ranked.display_name, ranked.accuracy, ranked.achievement, ranked.dx_score, ranked.played_at, ranked.rank
+from ranked
+where
+    $3::numeric is null
+    or (-ranked.achievement, -ranked.dx_score, ranked.played_at, ranked.id)
+    > (
+        -$3::numeric,
+        -$4::int,
+        $5::timestamp,
+        $6::uuid
+    )
+order by ranked.achievement desc, ranked.dx_score desc, ranked.played_at, ranked.id
+limit $7
+`
+
+type GetBeatmapLeaderboardParams struct {
+	BeatmapID         uuid.UUID        `json:"beatmapID"`
+	ViewerID          pgtype.Text      `json:"viewerID"`
+	CursorAchievement pgtype.Numeric   `json:"cursorAchievement"`
+	CursorDxScore     int32            `json:"cursorDxScore"`
+	CursorPlayedAt    pgtype.Timestamp `json:"cursorPlayedAt"`
+	CursorID          uuid.UUID        `json:"cursorID"`
+	PageSize          int32            `json:"pageSize"`
+}
+
+type GetBeatmapLeaderboardRow struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      string           `json:"userID"`
+	DisplayName string           `json:"displayName"`
+	Accuracy    string           `json:"accuracy"`
+	Achievement pgtype.Numeric   `json:"achievement"`
+	DxScore     int32            `json:"dxScore"`
+	PlayedAt    pgtype.Timestamp `json:"playedAt"`
+	Rank        int64            `json:"rank"`
+}
+
+// every user's best unflagged score of the beatmap, by achievement, then
+// dx score, then the earliest play. users whose scores are not public
+// only show up for themselves (viewer_id). keyset pagination over
+// (achievement, dx_score, played_at, id), the descending keys negated so
+// one row comparison follows the order.
+func (q *Queries) GetBeatmapLeaderboard(ctx context.Context, arg GetBeatmapLeaderboardParams) ([]GetBeatmapLeaderboardRow, error) {
+	rows, err := q.db.Query(ctx, getBeatmapLeaderboard,
+		arg.BeatmapID,
+		arg.ViewerID,
+		arg.CursorAchievement,
+		arg.CursorDxScore,
+		arg.CursorPlayedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBeatmapLeaderboardRow
+	for rows.Next() {
+		var i GetBeatmapLeaderboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.DisplayName,
+			&i.Accuracy,
+			&i.Achievement,
+			&i.DxScore,
+			&i.PlayedAt,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBeatmapLeaderboardEntry = `-- name: GetBeatmapLeaderboardEntry :one
+with bests as (
+    select distinct on (scores.user_uuid)
+        scores.id,
+        scores.user_uuid,
+        scores.accuracy,
+        scores.dx_score,
+        scores.played_at,
+        coalesce(scores.achievement, 0) as achievement
+    from scores
+    inner join users on scores.user_uuid = users.id
+    left join user_privacy p on users.id = p.user_uuid
+    where
+        scores.beatmap_id = $1
+        and scores.flag_reason is null
+        and (
+            coalesce(p.scores_visibility, 'public') = 'public'
+            or users.user_id = $2::text
+            or (p.scores_visibility = 'friends' and is_friend(users.id, $2::text))
+        )
+    order by scores.user_uuid, achievement desc, scores.dx_score desc, scores.played_at
+),
+ranked as (
+    select
+        bests.id,
+        users.user_id,
+        users.display_name,
+        bests.accuracy,
+        bests.achievement,
+        bests.dx_score,
+        bests.played_at,
+        rank() over (order by bests.achievement desc, bests.dx_score desc, bests.played_at) as rank
+    from bests
+    inner join users on bests.user_uuid = users.id
+)
+select ranked.id, ranked.user_id, ranked.display_name, ranked.accuracy, ranked.achievement, ranked.dx_score, ranked.played_at, ranked.rank
+from ranked
+where ranked.user_id = $2::text
+`
+
+type GetBeatmapLeaderboardEntryParams struct {
+	BeatmapID uuid.UUID   `json:"beatmapID"`
+	ViewerID  pgtype.Text `json:"viewerID"`
+}
+
+type GetBeatmapLeaderboardEntryRow struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      string           `json:"userID"`
+	DisplayName string           `json:"displayName"`
+	Accuracy    string           `json:"accuracy"`
+	Achievement pgtype.Numeric   `json:"achievement"`
+	DxScore     int32            `json:"dxScore"`
+	PlayedAt    pgtype.Timestamp `json:"playedAt"`
+	Rank        int64            `json:"rank"`
+}
+
+// the viewer's row of GetBeatmapLeaderboard
+func (q *Queries) GetBeatmapLeaderboardEntry(ctx context.Context, arg GetBeatmapLeaderboardEntryParams) (GetBeatmapLeaderboardEntryRow, error) {
+	row := q.db.QueryRow(ctx, getBeatmapLeaderboardEntry, arg.BeatmapID, arg.ViewerID)
+	var i GetBeatmapLeaderboardEntryRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DisplayName,
+		&i.Accuracy,
+		&i.Achievement,
+		&i.DxScore,
+		&i.PlayedAt,
+		&i.Rank,
+	)
+	return i, err
+}
+
+const getDailyPlayCountsByUserID = `-- name: GetDailyPlayCountsByUserID :many
+select
+    (scores.played_at + interval '9 hours')::date as day,
+    count(*) as plays
+from scores
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and scores.played_at >= $2
+    and scores.played_at < $3
+group by day
+order by day
+`
+
+type GetDailyPlayCountsByUserIDParams struct {
+	UserID     string           `json:"userID"`
+	PlayedFrom pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo   pgtype.Timestamp `json:"playedTo"`
+}
+
+type GetDailyPlayCountsByUserIDRow struct {
+	Day   pgtype.Date `json:"day"`
+	Plays int64       `json:"plays"`
+}
+
+// plays per JST day, days without plays are left out. played_from and
+// played_to are UTC bounds, played_to is exclusive.
+func (q *Queries) GetDailyPlayCountsByUserID(ctx context.Context, arg GetDailyPlayCountsByUserIDParams) ([]GetDailyPlayCountsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getDailyPlayCountsByUserID, arg.UserID, arg.PlayedFrom, arg.PlayedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDailyPlayCountsByUserIDRow
+	for rows.Next() {
+		var i GetDailyPlayCountsByUserIDRow
+		if err := rows.Scan(&i.Day, &i.Plays); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFastLateTrendByUserID = `-- name: GetFastLateTrendByUserID :many
 select
+    date_trunc('week', scores.played_at + interval '9 hours')::date as week,
+    count(*) as plays,
+    coalesce(sum(scores.fast), 0)::bigint as fast,
+    coalesce(sum(scores.late), 0)::bigint as late
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and ($2::text is null or beatmaps.difficulty = $2::text)
+    and ($3::text is null or beatmaps.type = $3::text)
+    and ($4::numeric is null or beatmaps.internal_level >= $4::numeric)
+    and ($5::numeric is null or beatmaps.internal_level <= $5::numeric)
+    and ($6::timestamp is null or scores.played_at >= $6::timestamp)
+    and ($7::timestamp is null or scores.played_at < $7::timestamp)
+group by week
+order by week
+`
+
+type GetFastLateTrendByUserIDParams struct {
+	UserID     string           `json:"userID"`
+	Difficulty pgtype.Text      `json:"difficulty"`
+	Type       pgtype.Text      `json:"type"`
+	LevelMin   pgtype.Numeric   `json:"levelMin"`
+	LevelMax   pgtype.Numeric   `json:"levelMax"`
+	PlayedFrom pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo   pgtype.Timestamp `json:"playedTo"`
+}
+
+type GetFastLateTrendByUserIDRow struct {
+	Week  pgtype.Date `json:"week"`
+	Plays int64       `json:"plays"`
+	Fast  int64       `json:"fast"`
+	Late  int64       `json:"late"`
+}
+
+// fast and late per JST week, weeks start on monday
+func (q *Queries) GetFastLateTrendByUserID(ctx context.Context, arg GetFastLateTrendByUserIDParams) ([]GetFastLateTrendByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getFastLateTrendByUserID,
+		arg.UserID,
+		arg.Difficulty,
+		arg.Type,
+		arg.LevelMin,
+		arg.LevelMax,
+		arg.PlayedFrom,
+		arg.PlayedTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFastLateTrendByUserIDRow
+	for rows.Next() {
+		var i GetFastLateTrendByUserIDRow
+		if err := rows.Scan(
+			&i.Week,
+			&i.Plays,
+			&i.Fast,
+			&i.Late,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getJudgementTotalsByUserID = `-- name: GetJudgementTotalsByUserID :one
+select
+    count(*) as plays,
+    coalesce(sum(scores.tap_critical), 0)::bigint as tap_critical,
+    coalesce(sum(scores.tap_perfect), 0)::bigint as tap_perfect,
+    coalesce(sum(scores.tap_great), 0)::bigint as tap_great,
+    coalesce(sum(scores.tap_good), 0)::bigint as tap_good,
+    coalesce(sum(scores.tap_miss), 0)::bigint as tap_miss,
+    coalesce(sum(scores.hold_critical), 0)::bigint as hold_critical,
+    coalesce(sum(scores.hold_perfect), 0)::bigint as hold_perfect,
+    coalesce(sum(scores.hold_great), 0)::bigint as hold_great,
+    coalesce(sum(scores.hold_good), 0)::bigint as hold_good,
+    coalesce(sum(scores.hold_miss), 0)::bigint as hold_miss,
+    coalesce(sum(scores.slide_critical), 0)::bigint as slide_critical,
+    coalesce(sum(scores.slide_perfect), 0)::bigint as slide_perfect,
+    coalesce(sum(scores.slide_great), 0)::bigint as slide_great,
+    coalesce(sum(scores.slide_good), 0)::bigint as slide_good,
+    coalesce(sum(scores.slide_miss), 0)::bigint as slide_miss,
+    coalesce(sum(scores.touch_critical), 0)::bigint as touch_critical,
+    coalesce(sum(scores.touch_perfect), 0)::bigint as touch_perfect,
+    coalesce(sum(scores.touch_great), 0)::bigint as touch_great,
+    coalesce(sum(scores.touch_good), 0)::bigint as touch_good,
+    coalesce(sum(scores.touch_miss), 0)::bigint as touch_miss,
+    coalesce(sum(scores.break_critical), 0)::bigint as break_critical,
+    coalesce(sum(scores.break_perfect), 0)::bigint as break_perfect,
+    coalesce(sum(scores.break_great), 0)::bigint as break_great,
+    coalesce(sum(scores.break_good), 0)::bigint as break_good,
+    coalesce(sum(scores.break_miss), 0)::bigint as break_miss,
+    coalesce(sum(scores.fast), 0)::bigint as fast,
+    coalesce(sum(scores.late), 0)::bigint as late
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and ($2::text is null or beatmaps.difficulty = $2::text)
+    and ($3::text is null or beatmaps.type = $3::text)
+    and ($4::numeric is null or beatmaps.internal_level >= $4::numeric)
+    and ($5::numeric is null or beatmaps.internal_level <= $5::numeric)
+    and ($6::timestamp is null or scores.played_at >= $6::timestamp)
+    and ($7::timestamp is null or scores.played_at < $7::timestamp)
+`
+
+type GetJudgementTotalsByUserIDParams struct {
+	UserID     string           `json:"userID"`
+	Difficulty pgtype.Text      `json:"difficulty"`
+	Type       pgtype.Text      `json:"type"`
+	LevelMin   pgtype.Numeric   `json:"levelMin"`
+	LevelMax   pgtype.Numeric   `json:"levelMax"`
+	PlayedFrom pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo   pgtype.Timestamp `json:"playedTo"`
+}
+
+type GetJudgementTotalsByUserIDRow struct {
+	Plays         int64 `json:"plays"`
+	TapCritical   int64 `json:"tapCritical"`
+	TapPerfect    int64 `json:"tapPerfect"`
+	TapGreat      int64 `json:"tapGreat"`
+	TapGood       int64 `json:"tapGood"`
+	TapMiss       int64 `json:"tapMiss"`
+	HoldCritical  int64 `json:"holdCritical"`
+	HoldPerfect   int64 `json:"holdPerfect"`
+	HoldGreat     int64 `json:"holdGreat"`
+	HoldGood      int64 `json:"holdGood"`
+	HoldMiss      int64 `json:"holdMiss"`
+	SlideCritical int64 `json:"slideCritical"`
+	SlidePerfect  int64 `json:"slidePerfect"`
+	SlideGreat    int64 `json:"slideGreat"`
+	SlideGood     int64 `json:"slideGood"`
+	SlideMiss     int64 `json:"slideMiss"`
+	TouchCritical int64 `json:"touchCritical"`
+	TouchPerfect  int64 `json:"touchPerfect"`
+	TouchGreat    int64 `json:"touchGreat"`
+	TouchGood     int64 `json:"touchGood"`
+	TouchMiss     int64 `json:"touchMiss"`
+	BreakCritical int64 `json:"breakCritical"`
+	BreakPerfect  int64 `json:"breakPerfect"`
+	BreakGreat    int64 `json:"breakGreat"`
+	BreakGood     int64 `json:"breakGood"`
+	BreakMiss     int64 `json:"breakMiss"`
+	Fast          int64 `json:"fast"`
+	Late          int64 `json:"late"`
+}
+
+// judgements of the user's plays summed up, filters as in GetScoresByUserID
+func (q *Queries) GetJudgementTotalsByUserID(ctx context.Context, arg GetJudgementTotalsByUserIDParams) (GetJudgementTotalsByUserIDRow, error) {
+	row := q.db.QueryRow(ctx, getJudgementTotalsByUserID,
+		arg.UserID,
+		arg.Difficulty,
+		arg.Type,
+		arg.LevelMin,
+		arg.LevelMax,
+		arg.PlayedFrom,
+		arg.PlayedTo,
+	)
+	var i GetJudgementTotalsByUserIDRow
+	err := row.Scan(
+		&i.Plays,
+		&i.TapCritical,
+		&i.TapPerfect,
+		&i.TapGreat,
+		&i.TapGood,
+		&i.TapMiss,
+		&i.HoldCritical,
+		&i.HoldPerfect,
+		&i.HoldGreat,
+		&i.HoldGood,
+		&i.HoldMiss,
+		&i.SlideCritical,
+		&i.SlidePerfect,
+		&i.SlideGreat,
+		&i.SlideGood,
+		&i.SlideMiss,
+		&i.TouchCritical,
+		&i.TouchPerfect,
+		&i.TouchGreat,
+		&i.TouchGood,
+		&i.TouchMiss,
+		&i.BreakCritical,
+		&i.BreakPerfect,
+		&i.BreakGreat,
+		&i.BreakGood,
+		&i.BreakMiss,
+		&i.Fast,
+		&i.Late,
+	)
+	return i, err
+}
+
+const getMostPlayedBeatmapsByUserID = `-- name: GetMostPlayedBeatmapsByUserID :many
+select
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.type,
+    count(*) as plays
+from scores
+inner join songs on scores.song_id = songs.id
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and scores.played_at >= $2
+    and scores.played_at < $3
+group by beatmaps.id, songs.id
+order by plays desc, beatmaps.id
+limit $4
+`
+
+type GetMostPlayedBeatmapsByUserIDParams struct {
+	UserID     string           `json:"userID"`
+	PlayedFrom pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo   pgtype.Timestamp `json:"playedTo"`
+	MaxResults int32            `json:"maxResults"`
+}
+
+type GetMostPlayedBeatmapsByUserIDRow struct {
+	BeatmapID  uuid.UUID `json:"beatmapID"`
+	SongID     uuid.UUID `json:"songID"`
+	Title      string    `json:"title"`
+	ImageUrl   string    `json:"imageUrl"`
+	Difficulty string    `json:"difficulty"`
+	Level      string    `json:"level"`
+	Type       string    `json:"type"`
+	Plays      int64     `json:"plays"`
+}
+
+// played_from and played_to are UTC bounds, played_to is exclusive
+func (q *Queries) GetMostPlayedBeatmapsByUserID(ctx context.Context, arg GetMostPlayedBeatmapsByUserIDParams) ([]GetMostPlayedBeatmapsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getMostPlayedBeatmapsByUserID,
+		arg.UserID,
+		arg.PlayedFrom,
+		arg.PlayedTo,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMostPlayedBeatmapsByUserIDRow
+	for rows.Next() {
+		var i GetMostPlayedBeatmapsByUserIDRow
+		if err := rows.Scan(
+			&i.BeatmapID,
+			&i.SongID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Difficulty,
+			&i.Level,
+			&i.Type,
+			&i.Plays,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPersonalBestsByUserID = `-- name: GetPersonalBestsByUserID :many
+select distinct on (scores.beatmap_id)
     scores.id,
     scores.beatmap_id,
     scores.song_id,
-    scores.user_uuid,
     scores.accuracy,
-    scores.max_combo,
     scores.dx_score,
-    scores.tap_critical,
-    scores.tap_perfect,
-    scores.tap_great,
-    scores.tap_good,
-    scores.tap_miss,
-    scores.hold_critical,
-    scores.hold_perfect,
-    scores.hold_great,
-    scores.hold_good,
-    scores.hold_miss,
-    scores.slide_critical,
-    scores.slide_perfect,
-    scores.slide_great,
-    scores.slide_good,
-    scores.slide_miss,
-    scores.touch_critical,
-    scores.touch_perfect,
-    scores.touch_great,
-    scores.touch_good,
-    scores.touch_miss,
-    scores.break_critical,
-    scores.break_perfect,
-    scores.break_great,
-    scores.break_good,
-    scores.break_miss,
-    scores.fast,
-    scores.late,
     scores.played_at,
     songs.title,
-    songs.artist,
-    songs.genre,
     songs.image_url,
-    songs.version,
+    songs.is_new,
     beatmaps.difficulty,
     beatmaps.level,
     beatmaps.internal_level,
-    beatmaps.type
+    beatmaps.type,
+    beatmaps.max_dx_score
 from scores
 inner join songs on scores.song_id = songs.id
 inner join beatmaps on scores.beatmap_id = beatmaps.id
 inner join users on scores.user_uuid = users.id
-where users.user_id = $1
+where users.user_id = $1 and not songs.is_utage and scores.flag_reason is null
+order by
+    scores.beatmap_id,
+    scores.achievement desc nulls last,
+    scores.played_at asc
+`
+
+type GetPersonalBestsByUserIDRow struct {
+	ID            uuid.UUID        `json:"id"`
+	BeatmapID     uuid.UUID        `json:"beatmapID"`
+	SongID        uuid.UUID        `json:"songID"`
+	Accuracy      string           `json:"accuracy"`
+	DxScore       int32            `json:"dxScore"`
+	PlayedAt      pgtype.Timestamp `json:"playedAt"`
+	Title         string           `json:"title"`
+	ImageUrl      string           `json:"imageUrl"`
+	IsNew         bool             `json:"isNew"`
+	Difficulty    string           `json:"difficulty"`
+	Level         string           `json:"level"`
+	InternalLevel pgtype.Numeric   `json:"internalLevel"`
+	Type          string           `json:"type"`
+	MaxDxScore    int32            `json:"maxDxScore"`
+}
+
+// the best score of every beatmap the user has played, utage and flagged
+// scores excluded
+func (q *Queries) GetPersonalBestsByUserID(ctx context.Context, userID string) ([]GetPersonalBestsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getPersonalBestsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPersonalBestsByUserIDRow
+	for rows.Next() {
+		var i GetPersonalBestsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BeatmapID,
+			&i.SongID,
+			&i.Accuracy,
+			&i.DxScore,
+			&i.PlayedAt,
+			&i.Title,
+			&i.ImageUrl,
+			&i.IsNew,
+			&i.Difficulty,
+			&i.Level,
+			&i.InternalLevel,
+			&i.Type,
+			&i.MaxDxScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayCountsByVersionByUserID = `-- name: GetPlayCountsByVersionByUserID :many
+select
+    songs.version,
+    count(*) as plays
+from scores
+inner join songs on scores.song_id = songs.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and scores.played_at >= $2
+    and scores.played_at < $3
+group by songs.version
+order by plays desc, songs.version
+`
+
+type GetPlayCountsByVersionByUserIDParams struct {
+	UserID     string           `json:"userID"`
+	PlayedFrom pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo   pgtype.Timestamp `json:"playedTo"`
+}
+
+type GetPlayCountsByVersionByUserIDRow struct {
+	Version string `json:"version"`
+	Plays   int64  `json:"plays"`
+}
+
+// plays per version of the song played, most played first
+func (q *Queries) GetPlayCountsByVersionByUserID(ctx context.Context, arg GetPlayCountsByVersionByUserIDParams) ([]GetPlayCountsByVersionByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getPlayCountsByVersionByUserID, arg.UserID, arg.PlayedFrom, arg.PlayedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayCountsByVersionByUserIDRow
+	for rows.Next() {
+		var i GetPlayCountsByVersionByUserIDRow
+		if err := rows.Scan(&i.Version, &i.Plays); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayStreaksByUserID = `-- name: GetPlayStreaksByUserID :many
+with streaks as (
+    select
+        min(d.day)::date as first_day,
+        max(d.day)::date as last_day,
+        count(*) as days
+    from (
+        select
+            days.day,
+            days.day - (row_number() over (order by days.day))::int as run
+        from (
+            select distinct (scores.played_at + interval '9 hours')::date as day
+            from scores
+            inner join users on scores.user_uuid = users.id
+            where users.user_id = $1
+        ) as days
+    ) as d
+    group by d.run
+)
+select first_day, last_day, days from (
+    (select * from streaks order by last_day desc limit 1)
+    union
+    (select * from streaks order by days desc, last_day desc limit 1)
+) as s
+order by last_day desc
+`
+
+type GetPlayStreaksByUserIDRow struct {
+	FirstDay pgtype.Date `json:"firstDay"`
+	LastDay  pgtype.Date `json:"lastDay"`
+	Days     int64       `json:"days"`
+}
+
+// the latest and the longest run of consecutive JST days with plays, one
+// row when they are the same run. Latest first, ties of the longest go to
+// the latest run.
+func (q *Queries) GetPlayStreaksByUserID(ctx context.Context, userID string) ([]GetPlayStreaksByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getPlayStreaksByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayStreaksByUserIDRow
+	for rows.Next() {
+		var i GetPlayStreaksByUserIDRow
+		if err := rows.Scan(&i.FirstDay, &i.LastDay, &i.Days); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentAchievementsByUserID = `-- name: GetRecentAchievementsByUserID :many
+select
+    beatmaps.internal_level::numeric as internal_level,
+    scores.accuracy
+from scores
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and beatmaps.internal_level is not null
+    and scores.flag_reason is null
 order by scores.played_at desc
-limit $2 offset $3
+limit $2
+`
+
+type GetRecentAchievementsByUserIDParams struct {
+	UserID   string `json:"userID"`
+	MaxPlays int32  `json:"maxPlays"`
+}
+
+type GetRecentAchievementsByUserIDRow struct {
+	InternalLevel pgtype.Numeric `json:"internalLevel"`
+	Accuracy      string         `json:"accuracy"`
+}
+
+// internal level and achievement of the user's latest unflagged plays of
+// beatmaps with a known internal level
+func (q *Queries) GetRecentAchievementsByUserID(ctx context.Context, arg GetRecentAchievementsByUserIDParams) ([]GetRecentAchievementsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getRecentAchievementsByUserID, arg.UserID, arg.MaxPlays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentAchievementsByUserIDRow
+	for rows.Next() {
+		var i GetRecentAchievementsByUserIDRow
+		if err := rows.Scan(&i.InternalLevel, &i.Accuracy); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScoresByUserID = `-- name: GetScoresByUserID :many
+select
+    s.id,
+    s.beatmap_id,
+    s.song_id,
+    s.user_uuid,
+    s.accuracy,
+    s.max_combo,
+    s.dx_score,
+    s.tap_critical,
+    s.tap_perfect,
+    s.tap_great,
+    s.tap_good,
+    s.tap_miss,
+    s.hold_critical,
+    s.hold_perfect,
+    s.hold_great,
+    s.hold_good,
+    s.hold_miss,
+    s.slide_critical,
+    s.slide_perfect,
+    s.slide_great,
+    s.slide_good,
+    s.slide_miss,
+    s.touch_critical,
+    s.touch_perfect,
+    s.touch_great,
+    s.touch_good,
+    s.touch_miss,
+    s.break_critical,
+    s.break_perfect,
+    s.break_great,
+    s.break_good,
+    s.break_miss,
+    s.fast,
+    s.late,
+    s.played_at,
+    s.flag_reason,
+    s.track,
+    s.session_id,
+    s.title,
+    s.artist,
+    s.genre,
+    s.image_url,
+    s.version,
+    s.difficulty,
+    s.level,
+    s.internal_level,
+    s.type,
+    s.max_dx_score,
+    s.sort_number,
+    s.sort_time
+from (
+    select
+        scores.id,
+        scores.beatmap_id,
+        scores.song_id,
+        scores.user_uuid,
+        scores.accuracy,
+        scores.max_combo,
+        scores.dx_score,
+        scores.tap_critical,
+        scores.tap_perfect,
+        scores.tap_great,
+        scores.tap_good,
+        scores.tap_miss,
+        scores.hold_critical,
+        scores.hold_perfect,
+        scores.hold_great,
+        scores.hold_good,
+        scores.hold_miss,
+        scores.slide_critical,
+        scores.slide_perfect,
+        scores.slide_great,
+        scores.slide_good,
+        scores.slide_miss,
+        scores.touch_critical,
+        scores.touch_perfect,
+        scores.touch_great,
+        scores.touch_good,
+        scores.touch_miss,
+        scores.break_critical,
+        scores.break_perfect,
+        scores.break_great,
+        scores.break_good,
+        scores.break_miss,
+        scores.fast,
+        scores.late,
+        scores.played_at,
+        scores.flag_reason,
+        scores.track,
+        scores.session_id,
+        songs.title,
+        songs.artist,
+        songs.genre,
+        songs.image_url,
+        songs.version,
+        beatmaps.difficulty,
+        beatmaps.level,
+        beatmaps.internal_level,
+        beatmaps.type,
+        beatmaps.max_dx_score,
+        (case $1::text
+            when 'achievement' then coalesce(scores.achievement, 0)
+            when 'level' then coalesce(beatmaps.internal_level, 0)
+            when 'dxScore' then scores.dx_score
+        end)::numeric as sort_number,
+        (case when $1::text = 'playedAt' then scores.played_at end)::timestamp as sort_time
+    from scores
+    inner join songs on scores.song_id = songs.id
+    inner join beatmaps on scores.beatmap_id = beatmaps.id
+    inner join users on scores.user_uuid = users.id
+    where
+        users.user_id = $2
+        and ($3::text is null or beatmaps.difficulty = $3::text)
+        and ($4::text is null or beatmaps.type = $4::text)
+        and ($5::text is null or songs.version = $5::text)
+        and ($6::numeric is null or beatmaps.internal_level >= $6::numeric)
+        and ($7::numeric is null or beatmaps.internal_level <= $7::numeric)
+        and ($8::timestamp is null or scores.played_at >= $8::timestamp)
+        and ($9::timestamp is null or scores.played_at < $9::timestamp)
+        and (
+            $10::numeric is null
+            or scores.achievement >= $10::numeric
+        )
+        and (
+            $11::numeric is null
+            or scores.achievement <= $11::numeric
+        )
+        and ($12::uuid is null or scores.session_id = $12::uuid)
+) as s
+where
+    $13::text is null
+    or (
+        $14::bool
+        and (
+            (s.sort_number, s.id) < ($15::numeric, $16::uuid)
+            or (s.sort_time, s.id) < ($17::timestamp, $16::uuid)
+        )
+    )
+    or (
+        not $14::bool
+        and (
+            (s.sort_number, s.id) > ($15::numeric, $16::uuid)
+            or (s.sort_time, s.id) > ($17::timestamp, $16::uuid)
+        )
+    )
+order by
+    case when $14::bool then s.sort_number end desc,
+    case when $14::bool then s.sort_time end desc,
+    case when $14::bool then s.id end desc,
+    case when not $14::bool then s.sort_number end asc,
+    case when not $14::bool then s.sort_time end asc,
+    case when not $14::bool then s.id end asc
+limit $18
 `
 
 type GetScoresByUserIDParams struct {
-	UserID string `json:"userID"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Sort           string           `json:"sort"`
+	UserID         string           `json:"userID"`
+	Difficulty     pgtype.Text      `json:"difficulty"`
+	Type           pgtype.Text      `json:"type"`
+	Version        pgtype.Text      `json:"version"`
+	LevelMin       pgtype.Numeric   `json:"levelMin"`
+	LevelMax       pgtype.Numeric   `json:"levelMax"`
+	PlayedFrom     pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo       pgtype.Timestamp `json:"playedTo"`
+	AchievementMin pgtype.Numeric   `json:"achievementMin"`
+	AchievementMax pgtype.Numeric   `json:"achievementMax"`
+	SessionID      pgtype.UUID      `json:"sessionID"`
+	CursorValue    pgtype.Text      `json:"cursorValue"`
+	Descending     bool             `json:"descending"`
+	CursorNumber   pgtype.Numeric   `json:"cursorNumber"`
+	CursorID       uuid.UUID        `json:"cursorID"`
+	CursorTime     pgtype.Timestamp `json:"cursorTime"`
+	PageSize       int32            `json:"pageSize"`
 }
 
 type GetScoresByUserIDRow struct {
@@ -277,6 +1148,9 @@ type GetScoresByUserIDRow struct {
 	Fast          int32            `json:"fast"`
 	Late          int32            `json:"late"`
 	PlayedAt      pgtype.Timestamp `json:"playedAt"`
+	FlagReason    pgtype.Text      `json:"flagReason"`
+	Track         pgtype.Int4      `json:"track"`
+	SessionID     pgtype.UUID      `json:"sessionID"`
 	Title         string           `json:"title"`
 	Artist        string           `json:"artist"`
 	Genre         string           `json:"genre"`
@@ -286,10 +1160,35 @@ type GetScoresByUserIDRow struct {
 	Level         string           `json:"level"`
 	InternalLevel pgtype.Numeric   `json:"internalLevel"`
 	Type          string           `json:"type"`
+	MaxDxScore    int32            `json:"maxDxScore"`
+	SortNumber    pgtype.Numeric   `json:"sortNumber"`
+	SortTime      pgtype.Timestamp `json:"sortTime"`
 }
 
+// keyset pagination over (sort_number, id) or (sort_time, id), see
+// handler/pagination.go
+// played_from and played_to are UTC bounds, played_to is exclusive
 func (q *Queries) GetScoresByUserID(ctx context.Context, arg GetScoresByUserIDParams) ([]GetScoresByUserIDRow, error) {
-	rows, err := q.db.Query(ctx, getScoresByUserID, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getScoresByUserID,
+		arg.Sort,
+		arg.UserID,
+		arg.Difficulty,
+		arg.Type,
+		arg.Version,
+		arg.LevelMin,
+		arg.LevelMax,
+		arg.PlayedFrom,
+		arg.PlayedTo,
+		arg.AchievementMin,
+		arg.AchievementMax,
+		arg.SessionID,
+		arg.CursorValue,
+		arg.Descending,
+		arg.CursorNumber,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -333,6 +1232,9 @@ func (q *Queries) GetScoresByUserID(ctx context.Context, arg GetScoresByUserIDPa
 			&i.Fast,
 			&i.Late,
 			&i.PlayedAt,
+			&i.FlagReason,
+			&i.Track,
+			&i.SessionID,
 			&i.Title,
 			&i.Artist,
 			&i.Genre,
@@ -342,6 +1244,204 @@ func (q *Queries) GetScoresByUserID(ctx context.Context, arg GetScoresByUserIDPa
 			&i.Level,
 			&i.InternalLevel,
 			&i.Type,
+			&i.MaxDxScore,
+			&i.SortNumber,
+			&i.SortTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScoresByUserUUIDAndBeatmapID = `-- name: GetScoresByUserUUIDAndBeatmapID :many
+select id, beatmap_id, song_id, user_uuid, accuracy, max_combo, dx_score, tap_critical, tap_perfect, tap_great, tap_good, tap_miss, hold_critical, hold_perfect, hold_great, hold_good, hold_miss, slide_critical, slide_perfect, slide_great, slide_good, slide_miss, touch_critical, touch_perfect, touch_great, touch_good, touch_miss, break_critical, break_perfect, break_great, break_good, break_miss, fast, late, played_at, created_at, achievement, flag_reason, track, session_id
+from scores
+where user_uuid = $1 and beatmap_id = $2 and flag_reason is null
+order by played_at
+`
+
+type GetScoresByUserUUIDAndBeatmapIDParams struct {
+	UserUuid  uuid.UUID `json:"userUuid"`
+	BeatmapID uuid.UUID `json:"beatmapID"`
+}
+
+// earlier plays the scraper compares a new score with, flagged scores
+// do not count as records
+func (q *Queries) GetScoresByUserUUIDAndBeatmapID(ctx context.Context, arg GetScoresByUserUUIDAndBeatmapIDParams) ([]Score, error) {
+	rows, err := q.db.Query(ctx, getScoresByUserUUIDAndBeatmapID, arg.UserUuid, arg.BeatmapID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Score
+	for rows.Next() {
+		var i Score
+		if err := rows.Scan(
+			&i.ID,
+			&i.BeatmapID,
+			&i.SongID,
+			&i.UserUuid,
+			&i.Accuracy,
+			&i.MaxCombo,
+			&i.DxScore,
+			&i.TapCritical,
+			&i.TapPerfect,
+			&i.TapGreat,
+			&i.TapGood,
+			&i.TapMiss,
+			&i.HoldCritical,
+			&i.HoldPerfect,
+			&i.HoldGreat,
+			&i.HoldGood,
+			&i.HoldMiss,
+			&i.SlideCritical,
+			&i.SlidePerfect,
+			&i.SlideGreat,
+			&i.SlideGood,
+			&i.SlideMiss,
+			&i.TouchCritical,
+			&i.TouchPerfect,
+			&i.TouchGreat,
+			&i.TouchGood,
+			&i.TouchMiss,
+			&i.BreakCritical,
+			&i.BreakPerfect,
+			&i.BreakGreat,
+			&i.BreakGood,
+			&i.BreakMiss,
+			&i.Fast,
+			&i.Late,
+			&i.PlayedAt,
+			&i.CreatedAt,
+			&i.Achievement,
+			&i.FlagReason,
+			&i.Track,
+			&i.SessionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWeakSpotsByUserID = `-- name: GetWeakSpotsByUserID :many
+select
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
+    songs.title,
+    songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.internal_level,
+    beatmaps.type,
+    count(*) as plays,
+    coalesce(avg(scores.achievement), 0)::float8 as average_achievement,
+    sum(scores.tap_great + scores.hold_great + scores.slide_great + scores.touch_great + scores.break_great)::bigint as great,
+    sum(scores.tap_good + scores.hold_good + scores.slide_good + scores.touch_good + scores.break_good)::bigint as good,
+    sum(scores.tap_miss + scores.hold_miss + scores.slide_miss + scores.touch_miss + scores.break_miss)::bigint as miss,
+    sum(beatmaps.tap + beatmaps.hold + beatmaps.slide + beatmaps.touch + beatmaps.break)::bigint as notes,
+    coalesce(sum(scores.fast), 0)::bigint as fast,
+    coalesce(sum(scores.late), 0)::bigint as late
+from scores
+inner join songs on scores.song_id = songs.id
+inner join beatmaps on scores.beatmap_id = beatmaps.id
+inner join users on scores.user_uuid = users.id
+where
+    users.user_id = $1
+    and ($2::text is null or beatmaps.difficulty = $2::text)
+    and ($3::text is null or beatmaps.type = $3::text)
+    and ($4::numeric is null or beatmaps.internal_level >= $4::numeric)
+    and ($5::numeric is null or beatmaps.internal_level <= $5::numeric)
+    and ($6::timestamp is null or scores.played_at >= $6::timestamp)
+    and ($7::timestamp is null or scores.played_at < $7::timestamp)
+group by beatmaps.id, songs.id
+having count(*) >= $8
+order by
+    sum(scores.tap_good + scores.hold_good + scores.slide_good + scores.touch_good + scores.break_good
+        + scores.tap_miss + scores.hold_miss + scores.slide_miss + scores.touch_miss + scores.break_miss)::float8
+    / nullif(sum(beatmaps.tap + beatmaps.hold + beatmaps.slide + beatmaps.touch + beatmaps.break), 0) desc nulls last,
+    beatmaps.id
+limit $9
+`
+
+type GetWeakSpotsByUserIDParams struct {
+	UserID     string           `json:"userID"`
+	Difficulty pgtype.Text      `json:"difficulty"`
+	Type       pgtype.Text      `json:"type"`
+	LevelMin   pgtype.Numeric   `json:"levelMin"`
+	LevelMax   pgtype.Numeric   `json:"levelMax"`
+	PlayedFrom pgtype.Timestamp `json:"playedFrom"`
+	PlayedTo   pgtype.Timestamp `json:"playedTo"`
+	MinPlays   int64            `json:"minPlays"`
+	MaxResults int32            `json:"maxResults"`
+}
+
+type GetWeakSpotsByUserIDRow struct {
+	BeatmapID          uuid.UUID      `json:"beatmapID"`
+	SongID             uuid.UUID      `json:"songID"`
+	Title              string         `json:"title"`
+	ImageUrl           string         `json:"imageUrl"`
+	Difficulty         string         `json:"difficulty"`
+	Level              string         `json:"level"`
+	InternalLevel      pgtype.Numeric `json:"internalLevel"`
+	Type               string         `json:"type"`
+	Plays              int64          `json:"plays"`
+	AverageAchievement float64        `json:"averageAchievement"`
+	Great              int64          `json:"great"`
+	Good               int64          `json:"good"`
+	Miss               int64          `json:"miss"`
+	Notes              int64          `json:"notes"`
+	Fast               int64          `json:"fast"`
+	Late               int64          `json:"late"`
+}
+
+// beatmaps the user played at least min_plays times, the most GOODs and
+// MISSes per note first
+func (q *Queries) GetWeakSpotsByUserID(ctx context.Context, arg GetWeakSpotsByUserIDParams) ([]GetWeakSpotsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getWeakSpotsByUserID,
+		arg.UserID,
+		arg.Difficulty,
+		arg.Type,
+		arg.LevelMin,
+		arg.LevelMax,
+		arg.PlayedFrom,
+		arg.PlayedTo,
+		arg.MinPlays,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWeakSpotsByUserIDRow
+	for rows.Next() {
+		var i GetWeakSpotsByUserIDRow
+		if err := rows.Scan(
+			&i.BeatmapID,
+			&i.SongID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Difficulty,
+			&i.Level,
+			&i.InternalLevel,
+			&i.Type,
+			&i.Plays,
+			&i.AverageAchievement,
+			&i.Great,
+			&i.Good,
+			&i.Miss,
+			&i.Notes,
+			&i.Fast,
+			&i.Late,
 		); err != nil {
 			return nil, err
 		}
@@ -27,27 +27,35 @@ insert into songs (
     is_new,
     sort,
     release_date,
-    delete_date
+    delete_date,
+    title_kana,
+    title_romaji,
+    artist_romaji,
+    title_english
 )
-values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-returning id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+returning id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at, title_kana, title_romaji, artist_romaji, title_english
 `
 
 type CreateSongParams struct {
-	ID          uuid.UUID   `json:"id"`
-	AltKey      string      `json:"altKey"`
-	Title       string      `json:"title"`
-	Artist      string      `json:"artist"`
-	Genre       string      `json:"genre"`
-	Bpm         string      `json:"bpm"`
-	ImageUrl    string      `json:"imageUrl"`
-	Version     string      `json:"version"`
-	IsUtage     bool        `json:"isUtage"`
-	IsAvailable bool        `json:"isAvailable"`
-	IsNew       bool        `json:"isNew"`
-	Sort        string      `json:"sort"`
-	ReleaseDate pgtype.Date `json:"releaseDate"`
-	DeleteDate  pgtype.Date `json:"deleteDate"`
+	ID           uuid.UUID   `json:"id"`
+	AltKey       string      `json:"altKey"`
+	Title        string      `json:"title"`
+	Artist       string      `json:"artist"`
+	Genre        string      `json:"genre"`
+	Bpm          string      `json:"bpm"`
+	ImageUrl     string      `json:"imageUrl"`
+	Version      string      `json:"version"`
+	IsUtage      bool        `json:"isUtage"`
+	IsAvailable  bool        `json:"isAvailable"`
+	IsNew        bool        `json:"isNew"`
+	Sort         string      `json:"sort"`
+	ReleaseDate  pgtype.Date `json:"releaseDate"`
+	DeleteDate   pgtype.Date `json:"deleteDate"`
+	TitleKana    string      `json:"titleKana"`
+	TitleRomaji  string      `json:"titleRomaji"`
+	ArtistRomaji string      `json:"artistRomaji"`
+	TitleEnglish pgtype.Text `json:"titleEnglish"`
 }
 
 func (q *Queries) CreateSong(ctx context.Context, arg CreateSongParams) (Song, error) {
@@ -66,6 +74,10 @@ func (q *Queries) CreateSong(ctx context.Context, arg CreateSongParams) (Song, e
 		arg.Sort,
 		arg.ReleaseDate,
 		arg.DeleteDate,
+		arg.TitleKana,
+		arg.TitleRomaji,
+		arg.ArtistRomaji,
+		arg.TitleEnglish,
 	)
 	var i Song
 	err := row.Scan(
@@ -85,92 +97,91 @@ func (q *Queries) CreateSong(ctx context.Context, arg CreateSongParams) (Song, e
 		&i.DeleteDate,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.TitleKana,
+		&i.TitleRomaji,
+		&i.ArtistRomaji,
+		&i.TitleEnglish,
 	)
 	return i, err
 }
 
-const getAllSongs = `-- name: GetAllSongs :many
+const getPlateChartsByUserID = `-- name: GetPlateChartsByUserID :many
 select
-    songs.id,
+    songs.version,
+    beatmaps.id as beatmap_id,
+    songs.id as song_id,
     songs.title,
-    songs.artist,
-    songs.genre,
-    songs.bpm,
     songs.image_url,
+    beatmaps.difficulty,
+    beatmaps.level,
+    beatmaps.internal_level,
+    beatmaps.type,
+    b.accuracy,
+    b.achievement,
+    b.lamp
+from beatmaps
+inner join songs on beatmaps.song_id = songs.id
+cross join users
+left join lateral chart_best(beatmaps.id, users.id) as b on true
+where
+    users.user_id = $1
+    and songs.is_available
+    and not songs.is_utage
+    and beatmaps.difficulty in ('basic', 'advanced', 'expert', 'master')
+    and ($2::text is null or songs.version = $2::text)
+order by
+    min(songs.release_date) over (partition by songs.version) nulls last,
     songs.version,
-    songs.is_utage,
-    songs.is_available,
-    songs.is_new,
-    songs.release_date,
-    songs.delete_date,
-    coalesce(
-        (
-            select
-                json_agg(
-                    jsonb_build_object(
-                        'beatmap_id', beatmaps.id,
-                        'difficulty', beatmaps.difficulty,
-                        'level', beatmaps.level,
-                        'internal_level', beatmaps.internal_level,
-                        'type', beatmaps.type,
-                        'total_notes', beatmaps.total_notes,
-                        'tap', beatmaps.tap,
-                        'hold', beatmaps.hold,
-                        'slide', beatmaps.slide,
-                        'touch', beatmaps.touch,
-                        'break', beatmaps.break,
-                        'note_designer', beatmaps.note_designer,
-                        'max_dx_score', beatmaps.max_dx_score
-                    )
-                )
-            from beatmaps
-            where beatmaps.song_id = songs.id
-        ),
-        '[]'
-    ) as beatmaps
-from songs
-order by songs.release_date desc, songs.sort desc
+    array_position(array['basic', 'advanced', 'expert', 'master'], beatmaps.difficulty),
+    songs.sort,
+    beatmaps.type
 `
 
-type GetAllSongsRow struct {
-	ID          uuid.UUID   `json:"id"`
-	Title       string      `json:"title"`
-	Artist      string      `json:"artist"`
-	Genre       string      `json:"genre"`
-	Bpm         string      `json:"bpm"`
-	ImageUrl    string      `json:"imageUrl"`
-	Version     string      `json:"version"`
-	IsUtage     bool        `json:"isUtage"`
-	IsAvailable bool        `json:"isAvailable"`
-	IsNew       bool        `json:"isNew"`
-	ReleaseDate pgtype.Date `json:"releaseDate"`
-	DeleteDate  pgtype.Date `json:"deleteDate"`
-	Beatmaps    interface{} `json:"beatmaps"`
+type GetPlateChartsByUserIDParams struct {
+	UserID  string      `json:"userID"`
+	Version pgtype.Text `json:"version"`
 }
 
-func (q *Queries) GetAllSongs(ctx context.Context) ([]GetAllSongsRow, error) {
-	rows, err := q.db.Query(ctx, getAllSongs)
+type GetPlateChartsByUserIDRow struct {
+	Version       string         `json:"version"`
+	BeatmapID     uuid.UUID      `json:"beatmapID"`
+	SongID        uuid.UUID      `json:"songID"`
+	Title         string         `json:"title"`
+	ImageUrl      string         `json:"imageUrl"`
+	Difficulty    string         `json:"difficulty"`
+	Level         string         `json:"level"`
+	InternalLevel pgtype.Numeric `json:"internalLevel"`
+	Type          string         `json:"type"`
+	Accuracy      pgtype.Text    `json:"accuracy"`
+	Achievement   pgtype.Float8  `json:"achievement"`
+	Lamp          pgtype.Int4    `json:"lamp"`
+}
+
+// BASIC to MASTER charts of available songs with the user's best
+// achievement and lamp, null when unplayed. lamp is ordered like
+// calculator.Lamp. Versions are ordered by their first release.
+func (q *Queries) GetPlateChartsByUserID(ctx context.Context, arg GetPlateChartsByUserIDParams) ([]GetPlateChartsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getPlateChartsByUserID, arg.UserID, arg.Version)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetAllSongsRow
+	var items []GetPlateChartsByUserIDRow
 	for rows.Next() {
-		var i GetAllSongsRow
+		var i GetPlateChartsByUserIDRow
 		if err := rows.Scan(
-			&i.ID,
+			&i.Version,
+			&i.BeatmapID,
+			&i.SongID,
 			&i.Title,
-			&i.Artist,
-			&i.Genre,
-			&i.Bpm,
 			&i.ImageUrl,
-			&i.Version,
-			&i.IsUtage,
-			&i.IsAvailable,
-			&i.IsNew,
-			&i.ReleaseDate,
-			&i.DeleteDate,
-			&i.Beatmaps,
+			&i.Difficulty,
+			&i.Level,
+			&i.InternalLevel,
+			&i.Type,
+			&i.Accuracy,
+			&i.Achievement,
+			&i.Lamp,
 		); err != nil {
 			return nil, err
 		}
@@ -183,7 +194,7 @@ func (q *Queries) GetAllSongs(ctx context.Context) ([]GetAllSongsRow, error) {
 }
 
 const getSongByAltKey = `-- name: GetSongByAltKey :one
-select id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at
+select id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at, title_kana, title_romaji, artist_romaji, title_english
 from songs
 where alt_key = $1
 `
@@ -208,6 +219,10 @@ func (q *Queries) GetSongByAltKey(ctx context.Context, altKey string) (Song, err
 		&i.DeleteDate,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.TitleKana,
+		&i.TitleRomaji,
+		&i.ArtistRomaji,
+		&i.TitleEnglish,
 	)
 	return i, err
 }
@@ -216,7 +231,11 @@ const getSongByID = `-- name: GetSongByID :one
 select
     songs.id,
     songs.title,
+    songs.title_kana,
+    songs.title_romaji,
+    songs.title_english,
     songs.artist,
+    songs.artist_romaji,
     songs.genre,
     songs.bpm,
     songs.image_url,
@@ -256,19 +275,23 @@ where songs.id = $1
 `
 
 type GetSongByIDRow struct {
-	ID          uuid.UUID   `json:"id"`
-	Title       string      `json:"title"`
-	Artist      string      `json:"artist"`
-	Genre       string      `json:"genre"`
-	Bpm         string      `json:"bpm"`
-	ImageUrl    string      `json:"imageUrl"`
-	Version     string      `json:"version"`
-	IsUtage     bool        `json:"isUtage"`
-	IsAvailable bool        `json:"isAvailable"`
-	IsNew       bool        `json:"isNew"`
-	ReleaseDate pgtype.Date `json:"releaseDate"`
-	DeleteDate  pgtype.Date `json:"deleteDate"`
-	Beatmaps    interface{} `json:"beatmaps"`
+	ID           uuid.UUID   `json:"id"`
+	Title        string      `json:"title"`
+	TitleKana    string      `json:"titleKana"`
+	TitleRomaji  string      `json:"titleRomaji"`
+	TitleEnglish pgtype.Text `json:"titleEnglish"`
+	Artist       string      `json:"artist"`
+	ArtistRomaji string      `json:"artistRomaji"`
+	Genre        string      `json:"genre"`
+	Bpm          string      `json:"bpm"`
+	ImageUrl     string      `json:"imageUrl"`
+	Version      string      `json:"version"`
+	IsUtage      bool        `json:"isUtage"`
+	IsAvailable  bool        `json:"isAvailable"`
+	IsNew        bool        `json:"isNew"`
+	ReleaseDate  pgtype.Date `json:"releaseDate"`
+	DeleteDate   pgtype.Date `json:"deleteDate"`
+	Beatmaps     interface{} `json:"beatmaps"`
 }
 
 func (q *Queries) GetSongByID(ctx context.Context, id uuid.UUID) (GetSongByIDRow, error) {
@@ -277,7 +300,11 @@ func (q *Queries) GetSongByID(ctx context.Context, id uuid.UUID) (GetSongByIDRow
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
+		&i.TitleKana,
+		&i.TitleRomaji,
+		&i.TitleEnglish,
 		&i.Artist,
+		&i.ArtistRomaji,
 		&i.Genre,
 		&i.Bpm,
 		&i.ImageUrl,
@@ -293,7 +320,7 @@ func (q *Queries) GetSongByID(ctx context.Context, id uuid.UUID) (GetSongByIDRow
 }
 
 const getSongByTitleAndArtist = `-- name: GetSongByTitleAndArtist :one
-select id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at
+select id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at, title_kana, title_romaji, artist_romaji, title_english
 from songs
 where title = $1 and artist = $2
 `
@@ -323,12 +350,16 @@ func (q *Queries) GetSongByTitleAndArtist(ctx context.Context, arg GetSongByTitl
 		&i.DeleteDate,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.TitleKana,
+		&i.TitleRomaji,
+		&i.ArtistRomaji,
+		&i.TitleEnglish,
 	)
 	return i, err
 }
 
 const getSongsByTitle = `-- name: GetSongsByTitle :many
-select id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at
+select id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at, title_kana, title_romaji, artist_romaji, title_english
 from songs
 where title = $1
 `
@@ -359,6 +390,392 @@ func (q *Queries) GetSongsByTitle(ctx context.Context, title string) ([]Song, er
 			&i.DeleteDate,
 			&i.UpdatedAt,
 			&i.CreatedAt,
+			&i.TitleKana,
+			&i.TitleRomaji,
+			&i.ArtistRomaji,
+			&i.TitleEnglish,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSongs = `-- name: ListSongs :many
+select
+    s.id,
+    s.title,
+    s.title_kana,
+    s.title_romaji,
+    s.title_english,
+    s.artist,
+    s.artist_romaji,
+    s.genre,
+    s.bpm,
+    s.image_url,
+    s.version,
+    s.is_utage,
+    s.is_available,
+    s.is_new,
+    s.release_date,
+    s.delete_date,
+    s.beatmaps,
+    s.sort_value
+from (
+    select
+        songs.id,
+        songs.title,
+        songs.title_kana,
+        songs.title_romaji,
+        songs.title_english,
+        songs.artist,
+        songs.artist_romaji,
+        songs.genre,
+        songs.bpm,
+        songs.image_url,
+        songs.version,
+        songs.is_utage,
+        songs.is_available,
+        songs.is_new,
+        songs.release_date,
+        songs.delete_date,
+        coalesce(
+            (
+                select
+                    json_agg(
+                        jsonb_build_object(
+                            'beatmapID', beatmaps.id,
+                            'difficulty', beatmaps.difficulty,
+                            'level', beatmaps.level,
+                            'internalLevel', beatmaps.internal_level,
+                            'type', beatmaps.type,
+                            'totalNotes', beatmaps.total_notes,
+                            'tap', beatmaps.tap,
+                            'hold', beatmaps.hold,
+                            'slide', beatmaps.slide,
+                            'touch', beatmaps.touch,
+                            'break', beatmaps.break,
+                            'noteDesigner', beatmaps.note_designer,
+                            'maxDxScore', beatmaps.max_dx_score
+                        )
+                    )
+                from beatmaps
+                where beatmaps.song_id = songs.id
+            ),
+            '[]'
+        ) as beatmaps,
+        (case $1::text
+            when 'title' then songs.title
+            when 'romaji' then lower(coalesce(nullif(songs.title_romaji, ''), songs.title))
+            when 'english' then lower(coalesce(songs.title_english, nullif(songs.title_romaji, ''), songs.title))
+            when 'artist' then songs.artist
+            else coalesce(to_char(songs.release_date, 'YYYY-MM-DD'), '') || lpad(songs.sort, 10, '0')
+        end)::text as sort_value
+    from songs
+    where
+        ($2::text is null or songs.version = $2::text)
+        and ($3::text is null or songs.genre = $3::text)
+) as s
+where
+    $4::text is null
+    or (
+        $5::bool
+        and (s.sort_value, s.id) < ($4::text, $6::uuid)
+    )
+    or (
+        not $5::bool
+        and (s.sort_value, s.id) > ($4::text, $6::uuid)
+    )
+order by
+    case when $5::bool then s.sort_value end desc,
+    case when $5::bool then s.id end desc,
+    case when not $5::bool then s.sort_value end asc,
+    case when not $5::bool then s.id end asc
+limit $7
+`
+
+type ListSongsParams struct {
+	Sort        string      `json:"sort"`
+	Version     pgtype.Text `json:"version"`
+	Genre       pgtype.Text `json:"genre"`
+	CursorValue pgtype.Text `json:"cursorValue"`
+	Descending  bool        `json:"descending"`
+	CursorID    uuid.UUID   `json:"cursorID"`
+	PageSize    int32       `json:"pageSize"`
+}
+
+type ListSongsRow struct {
+	ID           uuid.UUID   `json:"id"`
+	Title        string      `json:"title"`
+	TitleKana    string      `json:"titleKana"`
+	TitleRomaji  string      `json:"titleRomaji"`
+	TitleEnglish pgtype.Text `json:"titleEnglish"`
+	Artist       string      `json:"artist"`
+	ArtistRomaji string      `json:"artistRomaji"`
+	Genre        string      `json:"genre"`
+	Bpm          string      `json:"bpm"`
+	ImageUrl     string      `json:"imageUrl"`
+	Version      string      `json:"version"`
+	IsUtage      bool        `json:"isUtage"`
+	IsAvailable  bool        `json:"isAvailable"`
+	IsNew        bool        `json:"isNew"`
+	ReleaseDate  pgtype.Date `json:"releaseDate"`
+	DeleteDate   pgtype.Date `json:"deleteDate"`
+	Beatmaps     interface{} `json:"beatmaps"`
+	SortValue    string      `json:"sortValue"`
+}
+
+// keyset pagination over (sort_value, id), see handler/pagination.go
+func (q *Queries) ListSongs(ctx context.Context, arg ListSongsParams) ([]ListSongsRow, error) {
+	rows, err := q.db.Query(ctx, listSongs,
+		arg.Sort,
+		arg.Version,
+		arg.Genre,
+		arg.CursorValue,
+		arg.Descending,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSongsRow
+	for rows.Next() {
+		var i ListSongsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.TitleKana,
+			&i.TitleRomaji,
+			&i.TitleEnglish,
+			&i.Artist,
+			&i.ArtistRomaji,
+			&i.Genre,
+			&i.Bpm,
+			&i.ImageUrl,
+			&i.Version,
+			&i.IsUtage,
+			&i.IsAvailable,
+			&i.IsNew,
+			&i.ReleaseDate,
+			&i.DeleteDate,
+			&i.Beatmaps,
+			&i.SortValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSongs = `-- name: SearchSongs :many
+select
+    songs.id,
+    songs.title,
+    songs.title_kana,
+    songs.title_romaji,
+    songs.title_english,
+    songs.artist,
+    songs.artist_romaji,
+    songs.genre,
+    songs.bpm,
+    songs.image_url,
+    songs.version,
+    songs.is_utage,
+    songs.is_available,
+    songs.is_new,
+    songs.release_date,
+    songs.delete_date,
+    m.score,
+    m.matched_by
+from songs
+inner join (
+    select distinct on (c.song_id)
+        c.song_id,
+        c.score,
+        c.matched_by
+    from (
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.title) = lower($1::text) then 1.0
+                when strpos(lower(songs.title), lower($1::text)) = 1 then 0.9
+                when strpos(lower(songs.title), lower($1::text)) > 1 then 0.8
+                else word_similarity($1::text, songs.title) * 0.7
+            end)::float8 as score,
+            'title'::text as matched_by
+        from songs
+        where songs.title ilike '%' || $2::text || '%' or $1::text <% songs.title
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when songs.title_kana = $3::text then 1.0
+                when strpos(songs.title_kana, $3::text) = 1 then 0.9
+                when strpos(songs.title_kana, $3::text) > 1 then 0.8
+                else word_similarity($3::text, songs.title_kana) * 0.7
+            end)::float8 as score,
+            'titleKana'::text as matched_by
+        from songs
+        where
+            $3::text <> ''
+            and (
+                songs.title_kana like '%' || $3::text || '%'
+                or $3::text <% songs.title_kana
+            )
+        union all
+        select
+            song_aliases.song_id as song_id,
+            (case
+                when lower(song_aliases.alias) = lower($1::text) then 1.0
+                when strpos(lower(song_aliases.alias), lower($1::text)) = 1 then 0.9
+                when strpos(lower(song_aliases.alias), lower($1::text)) > 1 then 0.8
+                else word_similarity($1::text, song_aliases.alias) * 0.7
+            end)::float8 as score,
+            'alias'::text as matched_by
+        from song_aliases
+        where song_aliases.alias ilike '%' || $2::text || '%' or $1::text <% song_aliases.alias
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.title_romaji) = lower($1::text) then 1.0
+                when strpos(lower(songs.title_romaji), lower($1::text)) = 1 then 0.9
+                when strpos(lower(songs.title_romaji), lower($1::text)) > 1 then 0.8
+                else word_similarity($1::text, songs.title_romaji) * 0.7
+            end)::float8 as score,
+            'titleRomaji'::text as matched_by
+        from songs
+        where songs.title_romaji ilike '%' || $2::text || '%' or $1::text <% songs.title_romaji
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.title_english) = lower($1::text) then 1.0
+                when strpos(lower(songs.title_english), lower($1::text)) = 1 then 0.9
+                when strpos(lower(songs.title_english), lower($1::text)) > 1 then 0.8
+                else word_similarity($1::text, songs.title_english) * 0.7
+            end)::float8 as score,
+            'titleEnglish'::text as matched_by
+        from songs
+        where songs.title_english ilike '%' || $2::text || '%' or $1::text <% songs.title_english
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.artist) = lower($1::text) then 1.0
+                when strpos(lower(songs.artist), lower($1::text)) = 1 then 0.9
+                when strpos(lower(songs.artist), lower($1::text)) > 1 then 0.8
+                else word_similarity($1::text, songs.artist) * 0.7
+            end * 0.8)::float8 as score,
+            'artist'::text as matched_by
+        from songs
+        where songs.artist ilike '%' || $2::text || '%' or $1::text <% songs.artist
+        union all
+        select
+            songs.id as song_id,
+            (case
+                when lower(songs.artist_romaji) = lower($1::text) then 1.0
+                when strpos(lower(songs.artist_romaji), lower($1::text)) = 1 then 0.9
+                when strpos(lower(songs.artist_romaji), lower($1::text)) > 1 then 0.8
+                else word_similarity($1::text, songs.artist_romaji) * 0.7
+            end * 0.8)::float8 as score,
+            'artistRomaji'::text as matched_by
+        from songs
+        where songs.artist_romaji ilike '%' || $2::text || '%' or $1::text <% songs.artist_romaji
+        union all
+        select
+            songs.id as song_id,
+            (ts_rank(
+                to_tsvector('simple', songs.title || ' ' || songs.artist),
+                plainto_tsquery('simple', $1::text)
+            ) * 0.5)::float8 as score,
+            'fullText'::text as matched_by
+        from songs
+        where to_tsvector('simple', songs.title || ' ' || songs.artist) @@ plainto_tsquery('simple', $1::text)
+    ) as c
+    order by c.song_id, c.score desc
+) as m on songs.id = m.song_id
+order by m.score desc, songs.title
+limit $4
+`
+
+type SearchSongsParams struct {
+	Query        string `json:"query"`
+	QueryPattern string `json:"queryPattern"`
+	KanaQuery    string `json:"kanaQuery"`
+	MaxResults   int32  `json:"maxResults"`
+}
+
+type SearchSongsRow struct {
+	ID           uuid.UUID   `json:"id"`
+	Title        string      `json:"title"`
+	TitleKana    string      `json:"titleKana"`
+	TitleRomaji  string      `json:"titleRomaji"`
+	TitleEnglish pgtype.Text `json:"titleEnglish"`
+	Artist       string      `json:"artist"`
+	ArtistRomaji string      `json:"artistRomaji"`
+	Genre        string      `json:"genre"`
+	Bpm          string      `json:"bpm"`
+	ImageUrl     string      `json:"imageUrl"`
+	Version      string      `json:"version"`
+	IsUtage      bool        `json:"isUtage"`
+	IsAvailable  bool        `json:"isAvailable"`
+	IsNew        bool        `json:"isNew"`
+	ReleaseDate  pgtype.Date `json:"releaseDate"`
+	DeleteDate   pgtype.Date `json:"deleteDate"`
+	Score        float64     `json:"score"`
+	MatchedBy    string      `json:"matchedBy"`
+}
+
+// ranks songs by their best match over title, kana reading, aliases,
+// romaji and English titles and artist: exact > prefix > substring >
+// trigram word similarity, with full-text matches on title and artist as
+// a fallback.
+// query_pattern is query with LIKE wildcards escaped, kana_query is query
+// normalized like title_kana (see utils.NormalizeKana), letters only.
+func (q *Queries) SearchSongs(ctx context.Context, arg SearchSongsParams) ([]SearchSongsRow, error) {
+	rows, err := q.db.Query(ctx, searchSongs,
+		arg.Query,
+		arg.QueryPattern,
+		arg.KanaQuery,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchSongsRow
+	for rows.Next() {
+		var i SearchSongsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.TitleKana,
+			&i.TitleRomaji,
+			&i.TitleEnglish,
+			&i.Artist,
+			&i.ArtistRomaji,
+			&i.Genre,
+			&i.Bpm,
+			&i.ImageUrl,
+			&i.Version,
+			&i.IsUtage,
+			&i.IsAvailable,
+			&i.IsNew,
+			&i.ReleaseDate,
+			&i.DeleteDate,
+			&i.Score,
+			&i.MatchedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -386,26 +803,34 @@ set
     sort = $11,
     release_date = $12,
     delete_date = $13,
+    title_kana = $15,
+    title_romaji = $16,
+    artist_romaji = $17,
+    title_english = $18,
     updated_at = now()
 where id = $14
-returning id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at
+returning id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at, title_kana, title_romaji, artist_romaji, title_english
 `
 
 type UpdateSongParams struct {
-	AltKey      string      `json:"altKey"`
-	Title       string      `json:"title"`
-	Artist      string      `json:"artist"`
-	Genre       string      `json:"genre"`
-	Bpm         string      `json:"bpm"`
-	ImageUrl    string      `json:"imageUrl"`
-	Version     string      `json:"version"`
-	IsUtage     bool        `json:"isUtage"`
-	IsAvailable bool        `json:"isAvailable"`
-	IsNew       bool        `json:"isNew"`
-	Sort        string      `json:"sort"`
-	ReleaseDate pgtype.Date `json:"releaseDate"`
-	DeleteDate  pgtype.Date `json:"deleteDate"`
-	ID          uuid.UUID   `json:"id"`
+	AltKey       string      `json:"altKey"`
+	Title        string      `json:"title"`
+	Artist       string      `json:"artist"`
+	Genre        string      `json:"genre"`
+	Bpm          string      `json:"bpm"`
+	ImageUrl     string      `json:"imageUrl"`
+	Version      string      `json:"version"`
+	IsUtage      bool        `json:"isUtage"`
+	IsAvailable  bool        `json:"isAvailable"`
+	IsNew        bool        `json:"isNew"`
+	Sort         string      `json:"sort"`
+	ReleaseDate  pgtype.Date `json:"releaseDate"`
+	DeleteDate   pgtype.Date `json:"deleteDate"`
+	ID           uuid.UUID   `json:"id"`
+	TitleKana    string      `json:"titleKana"`
+	TitleRomaji  string      `json:"titleRomaji"`
+	ArtistRomaji string      `json:"artistRomaji"`
+	TitleEnglish pgtype.Text `json:"titleEnglish"`
 }
 
 func (q *Queries) UpdateSong(ctx context.Context, arg UpdateSongParams) (Song, error) {
@@ -424,7 +849,53 @@ func (q *Queries) UpdateSong(ctx context.Context, arg UpdateSongParams) (Song, e
 		arg.ReleaseDate,
 		arg.DeleteDate,
 		arg.ID,
+		arg.TitleKana,
+		arg.TitleRomaji,
+		arg.ArtistRomaji,
+		arg.TitleEnglish,
+	)
+	var i Song
+	err := row.Scan(
+		&i.ID,
+		&i.AltKey,
+		&i.Title,
+		&i.Artist,
+		&i.Genre,
+		&i.Bpm,
+		&i.ImageUrl,
+		&i.Version,
+		&i.Sort,
+		&i.IsUtage,
+		&i.IsAvailable,
+		&i.IsNew,
+		&i.ReleaseDate,
+		&i.DeleteDate,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.TitleKana,
+		&i.TitleRomaji,
+		&i.ArtistRomaji,
+		&i.TitleEnglish,
 	)
+	return i, err
+}
+
+const updateSongTitleEnglish = `-- name: UpdateSongTitleEnglish :one
+update songs
+set
+    title_english = $2,
+    updated_at = now()
+where id = $1
+returning id, alt_key, title, artist, genre, bpm, image_url, version, sort, is_utage, is_available, is_new, release_date, delete_date, updated_at, created_at, title_kana, title_romaji, artist_romaji, title_english
+`
+
+type UpdateSongTitleEnglishParams struct {
+	ID           uuid.UUID   `json:"id"`
+	TitleEnglish pgtype.Text `json:"titleEnglish"`
+}
+
+func (q *Queries) UpdateSongTitleEnglish(ctx context.Context, arg UpdateSongTitleEnglishParams) (Song, error) {
+	row := q.db.QueryRow(ctx, updateSongTitleEnglish, arg.ID, arg.TitleEnglish)
 	var i Song
 	err := row.Scan(
 		&i.ID,
@@ -443,6 +914,10 @@ func (q *Queries) UpdateSong(ctx context.Context, arg UpdateSongParams) (Song, e
 		&i.DeleteDate,
 		&i.UpdatedAt,
 		&i.CreatedAt,
+		&i.TitleKana,
+		&i.TitleRomaji,
+		&i.ArtistRomaji,
+		&i.TitleEnglish,
 	)
 	return i, err
 }
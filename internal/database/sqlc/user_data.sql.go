@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createUserData = `-- name: CreateUserData :one
@@ -51,6 +52,151 @@ func (q *Queries) CreateUserData(ctx context.Context, arg CreateUserDataParams)
 	return i, err
 }
 
+const getRatingLeaderboard = `-- name: GetRatingLeaderboard :many
+with ratings as (
+    select
+        users.id,
+        users.user_id,
+        users.display_name,
+        d.rating
+    from users
+    left join user_privacy p on users.id = p.user_uuid
+    inner join lateral (
+        select user_data.rating
+        from user_data
+        where user_data.user_uuid = users.id
+        order by user_data.created_at desc
+        limit 1
+    ) as d on true
+    where
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or users.user_id = $1::text
+        or (p.profile_visibility = 'friends' and is_friend(users.id, $1::text))
+),
+ranked as (
+    select
+        ratings.id,
+        ratings.user_id,
+        ratings.display_name,
+        ratings.rating,
+        rank() over (order by ratings.rating desc) as rank
+    from ratings
+)
+select ranked.id, ranked.user_id, ranked.display_name, ranked.rating, ranked.rank
+from ranked
+where
+    $2::int is null
+    or (-ranked.rating, ranked.user_id) > (-$2::int, $3::text)
+order by ranked.rating desc, ranked.user_id
+limit $4
+`
+
+type GetRatingLeaderboardParams struct {
+	ViewerID     pgtype.Text `json:"viewerID"`
+	CursorRating pgtype.Int4 `json:"cursorRating"`
+	CursorUserID string      `json:"cursorUserID"`
+	PageSize     int32       `json:"pageSize"`
+}
+
+type GetRatingLeaderboardRow struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      string    `json:"userID"`
+	DisplayName string    `json:"displayName"`
+	Rating      int32     `json:"rating"`
+	Rank        int64     `json:"rank"`
+}
+
+// users by the rating of their latest user_data snapshot. users whose
+// profile is not public only show up for themselves (viewer_id). keyset
+// pagination over (rating, user_id), the rating negated as it descends.
+func (q *Queries) GetRatingLeaderboard(ctx context.Context, arg GetRatingLeaderboardParams) ([]GetRatingLeaderboardRow, error) {
+	rows, err := q.db.Query(ctx, getRatingLeaderboard,
+		arg.ViewerID,
+		arg.CursorRating,
+		arg.CursorUserID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingLeaderboardRow
+	for rows.Next() {
+		var i GetRatingLeaderboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.DisplayName,
+			&i.Rating,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingLeaderboardEntry = `-- name: GetRatingLeaderboardEntry :one
+with ratings as (
+    select
+        users.id,
+        users.user_id,
+        users.display_name,
+        d.rating
+    from users
+    left join user_privacy p on users.id = p.user_uuid
+    inner join lateral (
+        select user_data.rating
+        from user_data
+        where user_data.user_uuid = users.id
+        order by user_data.created_at desc
+        limit 1
+    ) as d on true
+    where
+        coalesce(p.profile_visibility, 'public') = 'public'
+        or users.user_id = $1::text
+        or (p.profile_visibility = 'friends' and is_friend(users.id, $1::text))
+),
+ranked as (
+    select
+        ratings.id,
+        ratings.user_id,
+        ratings.display_name,
+        ratings.rating,
+        rank() over (order by ratings.rating desc) as rank
+    from ratings
+)
+select ranked.id, ranked.user_id, ranked.display_name, ranked.rating, ranked.rank
+from ranked
+where ranked.user_id = $1::text
+`
+
+type GetRatingLeaderboardEntryRow struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      string    `json:"userID"`
+	DisplayName string    `json:"displayName"`
+	Rating      int32     `json:"rating"`
+	Rank        int64     `json:"rank"`
+}
+
+// the viewer's row of GetRatingLeaderboard
+func (q *Queries) GetRatingLeaderboardEntry(ctx context.Context, viewerID pgtype.Text) (GetRatingLeaderboardEntryRow, error) {
+	row := q.db.QueryRow(ctx, getRatingLeaderboardEntry, viewerID)
+	var i GetRatingLeaderboardEntryRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DisplayName,
+		&i.Rating,
+		&i.Rank,
+	)
+	return i, err
+}
+
 const getUserDataByUserUUID = `-- name: GetUserDataByUserUUID :one
 select
     id,
@@ -78,3 +224,78 @@ func (q *Queries) GetUserDataByUserUUID(ctx context.Context, userUuid uuid.UUID)
 	)
 	return i, err
 }
+
+const getUserDataHistoryByUserID = `-- name: GetUserDataHistoryByUserID :many
+select
+    d.id,
+    d.rating,
+    d.season_play_count,
+    d.total_play_count,
+    d.created_at
+from (
+    select
+        user_data.id,
+        user_data.rating,
+        user_data.season_play_count,
+        user_data.total_play_count,
+        user_data.created_at,
+        lag(user_data.rating) over w as previous_rating,
+        lag(user_data.season_play_count) over w as previous_season_play_count,
+        lag(user_data.total_play_count) over w as previous_total_play_count
+    from user_data
+    inner join users on user_data.user_uuid = users.id
+    where
+        users.user_id = $1
+        and (
+            $2::timestamp is null
+            or user_data.created_at < $2::timestamp
+        )
+    window w as (order by user_data.created_at, user_data.id)
+) as d
+where
+    d.previous_rating is null
+    or (d.rating, d.season_play_count, d.total_play_count)
+    is distinct from (d.previous_rating, d.previous_season_play_count, d.previous_total_play_count)
+order by d.created_at, d.id
+`
+
+type GetUserDataHistoryByUserIDParams struct {
+	UserID        string           `json:"userID"`
+	CreatedBefore pgtype.Timestamp `json:"createdBefore"`
+}
+
+type GetUserDataHistoryByUserIDRow struct {
+	ID              uuid.UUID        `json:"id"`
+	Rating          int32            `json:"rating"`
+	SeasonPlayCount int32            `json:"seasonPlayCount"`
+	TotalPlayCount  int32            `json:"totalPlayCount"`
+	CreatedAt       pgtype.Timestamp `json:"createdAt"`
+}
+
+// snapshots oldest first, skipping the ones that did not change since the
+// previous snapshot. created_before is an exclusive UTC bound.
+func (q *Queries) GetUserDataHistoryByUserID(ctx context.Context, arg GetUserDataHistoryByUserIDParams) ([]GetUserDataHistoryByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getUserDataHistoryByUserID, arg.UserID, arg.CreatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserDataHistoryByUserIDRow
+	for rows.Next() {
+		var i GetUserDataHistoryByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Rating,
+			&i.SeasonPlayCount,
+			&i.TotalPlayCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
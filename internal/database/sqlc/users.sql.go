@@ -140,6 +140,48 @@ func (q *Queries) GetPasswordHashByUserID(ctx context.Context, userID string) (G
 	return i, err
 }
 
+const getSegaCredentialsBatch = `-- name: GetSegaCredentialsBatch :many
+select
+    id,
+    encrypted_sega_id,
+    encrypted_sega_password
+from users
+where id > $1
+order by id
+limit $2
+`
+
+type GetSegaCredentialsBatchParams struct {
+	ID    uuid.UUID `json:"id"`
+	Limit int32     `json:"limit"`
+}
+
+type GetSegaCredentialsBatchRow struct {
+	ID                    uuid.UUID `json:"id"`
+	EncryptedSegaID       string    `json:"encryptedSegaID"`
+	EncryptedSegaPassword string    `json:"encryptedSegaPassword"`
+}
+
+func (q *Queries) GetSegaCredentialsBatch(ctx context.Context, arg GetSegaCredentialsBatchParams) ([]GetSegaCredentialsBatchRow, error) {
+	rows, err := q.db.Query(ctx, getSegaCredentialsBatch, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSegaCredentialsBatchRow
+	for rows.Next() {
+		var i GetSegaCredentialsBatchRow
+		if err := rows.Scan(&i.ID, &i.EncryptedSegaID, &i.EncryptedSegaPassword); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSegaCredentialsByUserID = `-- name: GetSegaCredentialsByUserID :one
 select
     encrypted_sega_id,
@@ -290,6 +332,127 @@ func (q *Queries) GetUserByUserID(ctx context.Context, userID string) (GetUserBy
 	return i, err
 }
 
+const listUsers = `-- name: ListUsers :many
+select
+    u.id,
+    u.user_id,
+    u.display_name,
+    u.last_played_at,
+    u.rating,
+    u.sort_number,
+    u.sort_time,
+    u.sort_value
+from (
+    select
+        users.id,
+        users.user_id,
+        users.display_name,
+        users.last_played_at,
+        d.rating,
+        (case when $1::text = 'rating' then coalesce(d.rating, 0) end)::numeric as sort_number,
+        (case when $1::text = 'lastPlayedAt' then users.last_played_at end)::timestamp as sort_time,
+        (case when $1::text = 'userID' then users.user_id end)::text as sort_value
+    from users
+    left join user_privacy p on users.id = p.user_uuid
+    left join lateral (
+        select user_data.rating
+        from user_data
+        where user_data.user_uuid = users.id
+        order by user_data.created_at desc
+        limit 1
+    ) as d on true
+    where coalesce(p.profile_visibility, 'public') = 'public'
+) as u
+where
+    $2::text is null
+    or (
+        $3::bool
+        and (
+            (u.sort_number, u.id) < ($4::numeric, $5::uuid)
+            or (u.sort_time, u.id) < ($6::timestamp, $5::uuid)
+            or (u.sort_value, u.id) < ($2::text, $5::uuid)
+        )
+    )
+    or (
+        not $3::bool
+        and (
+            (u.sort_number, u.id) > ($4::numeric, $5::uuid)
+            or (u.sort_time, u.id) > ($6::timestamp, $5::uuid)
+            or (u.sort_value, u.id) > ($2::text, $5::uuid)
+        )
+    )
+order by
+    case when $3::bool then u.sort_number end desc,
+    case when $3::bool then u.sort_time end desc,
+    case when $3::bool then u.sort_value end desc,
+    case when $3::bool then u.id end desc,
+    case when not $3::bool then u.sort_number end asc,
+    case when not $3::bool then u.sort_time end asc,
+    case when not $3::bool then u.sort_value end asc,
+    case when not $3::bool then u.id end asc
+limit $7
+`
+
+type ListUsersParams struct {
+	Sort         string           `json:"sort"`
+	CursorValue  pgtype.Text      `json:"cursorValue"`
+	Descending   bool             `json:"descending"`
+	CursorNumber pgtype.Numeric   `json:"cursorNumber"`
+	CursorID     uuid.UUID        `json:"cursorID"`
+	CursorTime   pgtype.Timestamp `json:"cursorTime"`
+	PageSize     int32            `json:"pageSize"`
+}
+
+type ListUsersRow struct {
+	ID           uuid.UUID        `json:"id"`
+	UserID       string           `json:"userID"`
+	DisplayName  string           `json:"displayName"`
+	LastPlayedAt pgtype.Timestamp `json:"lastPlayedAt"`
+	Rating       pgtype.Int4      `json:"rating"`
+	SortNumber   pgtype.Numeric   `json:"sortNumber"`
+	SortTime     pgtype.Timestamp `json:"sortTime"`
+	SortValue    pgtype.Text      `json:"sortValue"`
+}
+
+// keyset pagination over (sort_number, id), (sort_time, id) or
+// (sort_value, id), see handler/pagination.go
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers,
+		arg.Sort,
+		arg.CursorValue,
+		arg.Descending,
+		arg.CursorNumber,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.DisplayName,
+			&i.LastPlayedAt,
+			&i.Rating,
+			&i.SortNumber,
+			&i.SortTime,
+			&i.SortValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateLastPlayedAt = `-- name: UpdateLastPlayedAt :one
 update users
 set
@@ -342,6 +505,26 @@ func (q *Queries) UpdateLastScrapedAt(ctx context.Context, arg UpdateLastScraped
 	return i, err
 }
 
+const updateSegaCredentials = `-- name: UpdateSegaCredentials :exec
+update users
+set
+    encrypted_sega_id = $2,
+    encrypted_sega_password = $3,
+    updated_at = now()
+where id = $1
+`
+
+type UpdateSegaCredentialsParams struct {
+	ID                    uuid.UUID `json:"id"`
+	EncryptedSegaID       string    `json:"encryptedSegaID"`
+	EncryptedSegaPassword string    `json:"encryptedSegaPassword"`
+}
+
+func (q *Queries) UpdateSegaCredentials(ctx context.Context, arg UpdateSegaCredentialsParams) error {
+	_, err := q.db.Exec(ctx, updateSegaCredentials, arg.ID, arg.EncryptedSegaID, arg.EncryptedSegaPassword)
+	return err
+}
+
 const updateUserByUUID = `-- name: UpdateUserByUUID :one
 update users
 set
@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ranks below this are not worth a feed item
+var minFeedRank, _ = calculator.ParseRank("S")
+
+// recordScoreFeedItems adds feed items for what a new score achieved over
+// the earlier plays of its beatmap: a new rank of S or above, otherwise a
+// new personal best, and a new lamp. A first play only counts for its rank
+// and lamp. Failures are only logged, the score is saved either way.
+func recordScoreFeedItems(queries *database.Queries, score database.Score) {
+	achievement, err := calculator.ParseAchievement(score.Accuracy)
+	if err != nil {
+		log.Printf("failed to parse accuracy of score %s: %s\n", score.ID, err)
+		return
+	}
+
+	plays, err := queries.GetScoresByUserUUIDAndBeatmapID(context.Background(), database.GetScoresByUserUUIDAndBeatmapIDParams{
+		UserUuid:  score.UserUuid,
+		BeatmapID: score.BeatmapID,
+	})
+	if err != nil {
+		log.Printf("failed to get earlier scores of beatmap %s: %s\n", score.BeatmapID, err)
+		return
+	}
+	var (
+		played   bool
+		best     float64
+		bestAcc  string
+		bestLamp calculator.Lamp
+	)
+	for _, p := range plays {
+		if p.ID == score.ID || !p.PlayedAt.Time.Before(score.PlayedAt.Time) {
+			continue
+		}
+		if a, err := calculator.ParseAchievement(p.Accuracy); err == nil && (!played || a > best) {
+			best, bestAcc = a, p.Accuracy
+		}
+		bestLamp = max(bestLamp, calculator.ScoreJudgements(p).Lamp())
+		played = true
+	}
+
+	newItem := func(kind string) database.CreateFeedItemParams {
+		return database.CreateFeedItemParams{
+			ID:        uuid.New(),
+			UserUuid:  score.UserUuid,
+			Kind:      kind,
+			ScoreID:   pgtype.UUID{Bytes: score.ID, Valid: true},
+			BeatmapID: pgtype.UUID{Bytes: score.BeatmapID, Valid: true},
+			Accuracy:  pgtype.Text{String: score.Accuracy, Valid: true},
+			CreatedAt: score.PlayedAt,
+		}
+	}
+	var items []database.CreateFeedItemParams
+	rank := calculator.RankOf(achievement)
+	switch {
+	case rank.Achievement >= minFeedRank.Achievement && (!played || rank.Achievement > calculator.RankOf(best).Achievement):
+		item := newItem("rank")
+		item.Rank = pgtype.Text{String: rank.Name, Valid: true}
+		items = append(items, item)
+	case played && achievement > best:
+		items = append(items, newItem("personal_best"))
+	}
+	if lamp := calculator.ScoreJudgements(score).Lamp(); lamp > bestLamp {
+		item := newItem("lamp")
+		item.Lamp = pgtype.Text{String: lamp.String(), Valid: true}
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		if played {
+			item.PreviousAccuracy = pgtype.Text{String: bestAcc, Valid: true}
+		}
+		if _, err := queries.CreateFeedItem(context.Background(), item); err != nil {
+			log.Printf("failed to create %s feed item for score %s: %s\n", item.Kind, score.ID, err)
+		}
+	}
+}
+
+// recordRatingMilestone adds a feed item when the rating passed one of
+// calculator.RatingMilestones since the previous snapshot
+func recordRatingMilestone(queries *database.Queries, userUuid uuid.UUID, previous, current int32) {
+	milestone, ok := calculator.RatingMilestone(previous, current)
+	if !ok {
+		return
+	}
+	_, err := queries.CreateFeedItem(context.Background(), database.CreateFeedItemParams{
+		ID:        uuid.New(),
+		UserUuid:  userUuid,
+		Kind:      "rating_milestone",
+		Rating:    pgtype.Int4{Int32: milestone, Valid: true},
+		CreatedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		log.Printf("failed to create rating milestone feed item: %s\n", err)
+	}
+}
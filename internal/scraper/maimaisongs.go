@@ -101,6 +101,11 @@ func upsertSong(queries *sqlc.Queries, ms maimaisong) (sqlc.Song, error) {
 		return sqlc.Song{}, err
 	}
 
+	// the reading is romanized as given, folding drops the dakuten and
+	// small kana romaji needs. Only the folded form is stored, for search.
+	titleKana := utils.NormalizeKana(ms.TitleKana)
+	titleRomaji := utils.RomanizeWithReading(ms.Title, ms.TitleKana)
+
 	song, getSongErr := queries.GetSongByTitleAndArtist(context.Background(), sqlc.GetSongByTitleAndArtistParams{
 		Title:  ms.Title,
 		Artist: ms.Artist,
@@ -109,19 +114,22 @@ func upsertSong(queries *sqlc.Queries, ms maimaisong) (sqlc.Song, error) {
 		if strings.Contains(getSongErr.Error(), "no rows in result set") {
 			// insert if it does not exist in DB
 			newSong, createSongErr := queries.CreateSong(context.Background(), sqlc.CreateSongParams{
-				ID:          uuid.New(),
-				AltKey:      utils.CreateAltKey(ms.Title, ms.Artist),
-				Title:       ms.Title,
-				Artist:      ms.Artist,
-				Genre:       ms.Genre,
-				Bpm:         "",
-				ImageUrl:    ms.ImageUrl,
-				Version:     versionMap[ms.Version[0:3]],
-				Sort:        ms.Sort,
-				IsUtage:     ms.Genre == "宴会場",
-				IsAvailable: true,
-				IsNew:       ms.Date == "NEW",
-				ReleaseDate: pgtype.Date{Time: releaseDate, Valid: true},
+				ID:           uuid.New(),
+				AltKey:       utils.CreateAltKey(ms.Title, ms.Artist),
+				Title:        ms.Title,
+				TitleKana:    titleKana,
+				TitleRomaji:  titleRomaji,
+				Artist:       ms.Artist,
+				ArtistRomaji: utils.RomanizeWithReading(ms.Artist, ""),
+				Genre:        ms.Genre,
+				Bpm:          "",
+				ImageUrl:     ms.ImageUrl,
+				Version:      versionMap[ms.Version[0:3]],
+				Sort:         ms.Sort,
+				IsUtage:      ms.Genre == "宴会場",
+				IsAvailable:  true,
+				IsNew:        ms.Date == "NEW",
+				ReleaseDate:  pgtype.Date{Time: releaseDate, Valid: true},
 			})
 			if createSongErr != nil {
 				return sqlc.Song{}, fmt.Errorf("failed to create song: %w", createSongErr)
@@ -139,22 +147,33 @@ func upsertSong(queries *sqlc.Queries, ms maimaisong) (sqlc.Song, error) {
 		return sqlc.Song{}, fmt.Errorf("failed to get song '%s': %w", ms.Title, getSongErr)
 	}
 
+	// the source has no artist reading, keep romaji a curator set for
+	// artists with kanji
+	artistRomaji := utils.RomanizeWithReading(ms.Artist, "")
+	if artistRomaji == "" {
+		artistRomaji = song.ArtistRomaji
+	}
+
 	// update song
 	updatedSong, updateErr := queries.UpdateSong(context.Background(), sqlc.UpdateSongParams{
-		ID:          song.ID,
-		AltKey:      utils.CreateAltKey(ms.Title, ms.Artist),
-		Title:       ms.Title,
-		Artist:      ms.Artist,
-		Genre:       ms.Genre,
-		Bpm:         song.Bpm,
-		ImageUrl:    ms.ImageUrl,
-		Version:     versionMap[ms.Version[0:3]],
-		Sort:        ms.Sort,
-		IsUtage:     ms.Genre == "宴会場",
-		IsAvailable: true,
-		IsNew:       ms.Date == "NEW",
-		ReleaseDate: pgtype.Date{Time: releaseDate, Valid: true},
-		DeleteDate:  song.DeleteDate,
+		ID:           song.ID,
+		AltKey:       utils.CreateAltKey(ms.Title, ms.Artist),
+		Title:        ms.Title,
+		TitleKana:    titleKana,
+		TitleRomaji:  titleRomaji,
+		TitleEnglish: song.TitleEnglish,
+		Artist:       ms.Artist,
+		ArtistRomaji: artistRomaji,
+		Genre:        ms.Genre,
+		Bpm:          song.Bpm,
+		ImageUrl:     ms.ImageUrl,
+		Version:      versionMap[ms.Version[0:3]],
+		Sort:         ms.Sort,
+		IsUtage:      ms.Genre == "宴会場",
+		IsAvailable:  true,
+		IsNew:        ms.Date == "NEW",
+		ReleaseDate:  pgtype.Date{Time: releaseDate, Valid: true},
+		DeleteDate:   song.DeleteDate,
 	})
 	if updateErr != nil {
 		return sqlc.Song{}, fmt.Errorf("failed to update song: %w", updateErr)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -73,6 +74,8 @@ func scrapeScores(m *maimaiclient.Client, queries *database.Queries, lastPlayedA
 	return scores, nil
 }
 
+var trackPattern = regexp.MustCompile(`TRACK 0([0-9])`)
+
 // scrape score details
 // provide hiddenValue found in a hidden tag at records page
 func scrapeScore(queries *database.Queries, m *maimaiclient.Client, recordID string) (database.Score, error) {
@@ -156,6 +159,12 @@ func scrapeScore(queries *database.Queries, m *maimaiclient.Client, recordID str
 	}
 	score.PlayedAt = pgtype.Timestamp{Time: playedAt, Valid: true}
 
+	// track number, "TRACK 01" starts a credit
+	if track := trackPattern.FindStringSubmatch(dateStr); track != nil {
+		n, _ := strconv.Atoi(track[1])
+		score.Track = pgtype.Int4{Int32: int32(n), Valid: true}
+	}
+
 	// Title
 	doc.Find(`.basic_block.m_5.p_5.p_l_10.f_13.break`).Find(`div`).Remove()
 	title := strings.TrimSpace(doc.Find(`.basic_block.m_5.p_5.p_l_10.f_13.break`).Text())
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// plays further apart than this are different visits to the arcade
+const sessionGap = 30 * time.Minute
+
+// sessionTracker groups a user's new scores into sessions, continuing the
+// latest session when the first new play is close enough to it. Scores
+// must be assigned oldest first.
+type sessionTracker struct {
+	queries  *database.Queries
+	userUuid uuid.UUID
+
+	loaded  bool
+	current *database.Session
+	touched []uuid.UUID
+}
+
+func newSessionTracker(queries *database.Queries, userUuid uuid.UUID) *sessionTracker {
+	return &sessionTracker{queries: queries, userUuid: userUuid}
+}
+
+// assign returns the session a play at playedAt belongs to, creating it
+// when needed
+func (t *sessionTracker) assign(playedAt time.Time) (uuid.UUID, error) {
+	if !t.loaded {
+		latest, err := t.queries.GetLatestSessionByUserUUID(context.Background(), t.userUuid)
+		switch {
+		case err == nil:
+			t.current = &latest
+		case !errors.Is(err, pgx.ErrNoRows):
+			return uuid.Nil, fmt.Errorf("failed to get latest session: %w", err)
+		}
+		t.loaded = true
+	}
+
+	if t.current != nil && playedAt.Sub(t.current.EndedAt.Time) <= sessionGap {
+		if playedAt.After(t.current.EndedAt.Time) {
+			t.current.EndedAt.Time = playedAt
+		}
+		t.touch(t.current.ID)
+		return t.current.ID, nil
+	}
+
+	session, err := t.queries.CreateSession(context.Background(), database.CreateSessionParams{
+		ID:        uuid.New(),
+		UserUuid:  t.userUuid,
+		StartedAt: pgtype.Timestamp{Time: playedAt, Valid: true},
+		EndedAt:   pgtype.Timestamp{Time: playedAt, Valid: true},
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	t.current = &session
+	t.touch(session.ID)
+	return session.ID, nil
+}
+
+func (t *sessionTracker) touch(id uuid.UUID) {
+	if len(t.touched) == 0 || t.touched[len(t.touched)-1] != id {
+		t.touched = append(t.touched, id)
+	}
+}
+
+// summarize recomputes the summary of every session a score was assigned
+// to. Failures are only logged, the scores are saved either way.
+func (t *sessionTracker) summarize() {
+	for _, id := range t.touched {
+		if _, err := t.queries.UpdateSessionSummary(context.Background(), id); err != nil {
+			log.Printf("failed to update summary of session %s: %s\n", id, err)
+		}
+	}
+}
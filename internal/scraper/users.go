@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/asashakira/maitrack/internal/calculator"
 	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/asashakira/maitrack/internal/service"
 	"github.com/asashakira/maitrack/internal/utils"
 	"github.com/asashakira/maitrack/pkg/maimaiclient"
 	"github.com/google/uuid"
@@ -85,6 +87,7 @@ func ScrapeUser(m *maimaiclient.Client, queries *database.Queries, user ScrapeUs
 		log.Println(scrapeErr)
 		return scrapeErr
 	}
+	previousUserData, previousErr := queries.GetUserDataByUserUUID(context.Background(), user.ID)
 	_, createUserDataErr := queries.CreateUserData(context.Background(), database.CreateUserDataParams{
 		ID:              uuid.New(),
 		UserUuid:        user.ID,
@@ -95,6 +98,10 @@ func ScrapeUser(m *maimaiclient.Client, queries *database.Queries, user ScrapeUs
 	if createUserDataErr != nil {
 		return fmt.Errorf("failed to create user data for '%s': %s", user.UserID, createUserDataErr)
 	}
+	// the first snapshot has nothing to compare with
+	if previousErr == nil {
+		recordRatingMilestone(queries, user.ID, previousUserData.Rating, scrapedUserData.Rating)
+	}
 
 	updateProfileImageUrlErr := queries.UpdateProfileImageUrl(context.Background(), database.UpdateProfileImageUrlParams{
 		UserUuid:        user.ID,
@@ -111,17 +118,37 @@ func ScrapeUser(m *maimaiclient.Client, queries *database.Queries, user ScrapeUs
 	}
 
 	var lastPlayedAt pgtype.Timestamp
+	sessions := newSessionTracker(queries, user.ID)
+	// summaries count the scores saved so far, also when a later one fails
+	defer sessions.summarize()
 
 	// update database
 	for _, score := range scores {
 		score.UserUuid = user.ID
 
+		sessionID, sessionErr := sessions.assign(score.PlayedAt.Time)
+		if sessionErr != nil {
+			return sessionErr
+		}
+		score.SessionID = pgtype.UUID{Bytes: sessionID, Valid: true}
+
+		// keep scores that do not add up but flag them for review
+		score.FlagReason = checkScore(queries, score)
+		if score.FlagReason.Valid {
+			log.Printf("flagged score of '%s' on beatmap %s: %s\n", user.UserID, score.BeatmapID, score.FlagReason.String)
+		}
+
 		// create new score
-		createScoreErr := createScore(queries, score)
+		created, createScoreErr := createScore(queries, score)
 		if createScoreErr != nil {
 			return createScoreErr
 		}
 
+		// flagged scores are not records
+		if !created.FlagReason.Valid {
+			recordScoreFeedItems(queries, created)
+		}
+
 		// update beatmap if notes are not set
 		updateBeatmapErr := updateBeatmapNoteCounts(queries, score)
 		if updateBeatmapErr != nil {
@@ -134,6 +161,11 @@ func ScrapeUser(m *maimaiclient.Client, queries *database.Queries, user ScrapeUs
 		}
 	}
 
+	// after the scores and the rating are saved
+	if goalsErr := service.EvaluateGoals(queries, user.ID); goalsErr != nil {
+		log.Println(goalsErr)
+	}
+
 	if lastPlayedAt.Valid {
 		// update LastPlayedAt
 		_, updateErr := queries.UpdateLastPlayedAt(context.Background(), database.UpdateLastPlayedAtParams{
@@ -221,8 +253,8 @@ func ScrapeProfileImageUrl(m *maimaiclient.Client) (string, error) {
 }
 
 // insert new score to database
-func createScore(queries *database.Queries, score database.Score) error {
-	_, createScoreErr := queries.CreateScore(context.Background(), database.CreateScoreParams{
+func createScore(queries *database.Queries, score database.Score) (database.Score, error) {
+	created, createScoreErr := queries.CreateScore(context.Background(), database.CreateScoreParams{
 		ID:            uuid.New(),
 		BeatmapID:     score.BeatmapID,
 		SongID:        score.SongID,
@@ -258,11 +290,41 @@ func createScore(queries *database.Queries, score database.Score) error {
 		Fast:          score.Fast,
 		Late:          score.Late,
 		PlayedAt:      score.PlayedAt,
+		FlagReason:    score.FlagReason,
+		Track:         score.Track,
+		SessionID:     score.SessionID,
 	})
 	if createScoreErr != nil {
-		return fmt.Errorf("failed to create score: %w", createScoreErr)
+		return database.Score{}, fmt.Errorf("failed to create score: %w", createScoreErr)
 	}
-	return nil
+	return created, nil
+}
+
+// recompute the score from its judgements and the beatmap's notes,
+// returns why it disagrees with the record page or NULL when it does not
+func checkScore(queries *database.Queries, score database.Score) pgtype.Text {
+	var notes calculator.Notes
+	beatmap, err := queries.GetBeatmapByBeatmapID(context.Background(), score.BeatmapID)
+	if err != nil {
+		log.Printf("failed to get beatmap, checking score without notes: %s\n", err)
+	} else {
+		notes = calculator.Notes{Tap: beatmap.Tap, Hold: beatmap.Hold, Slide: beatmap.Slide, Touch: beatmap.Touch, Break: beatmap.Break}
+	}
+
+	problems := calculator.Check(calculator.Score{
+		Judgements: calculator.ScoreJudgements(score),
+		Accuracy:   score.Accuracy,
+		MaxCombo:   score.MaxCombo,
+		DxScore:    score.DxScore,
+	}, notes)
+	if len(problems) == 0 {
+		return pgtype.Text{}
+	}
+	reasons := make([]string, 0, len(problems))
+	for _, p := range problems {
+		reasons = append(reasons, p.String())
+	}
+	return pgtype.Text{String: strings.Join(reasons, "; "), Valid: true}
 }
 
 // update beatmap only if notes are not set
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/asashakira/maitrack/internal/calculator"
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// goalEvaluator computes the progress of a user's goals, loading their
+// chart records and rating once for all goals
+type goalEvaluator struct {
+	queries  *database.Queries
+	userUuid uuid.UUID
+
+	loaded  bool
+	records []database.GetChartRecordsByUserUUIDRow
+	rating  *int32
+}
+
+// EvaluateGoals updates the progress of every goal of a user.
+// A failing goal is logged and the rest are still evaluated.
+func EvaluateGoals(queries *database.Queries, userUuid uuid.UUID) error {
+	goals, err := queries.GetGoalsByUserUUID(context.Background(), userUuid)
+	if err != nil {
+		return fmt.Errorf("failed to get goals: %w", err)
+	}
+	e := &goalEvaluator{queries: queries, userUuid: userUuid}
+	for _, goal := range goals {
+		if _, err := e.evaluate(goal); err != nil {
+			log.Printf("failed to evaluate goal %s: %s\n", goal.ID, err)
+		}
+	}
+	return nil
+}
+
+// EvaluateGoal updates the progress of one goal and returns it
+func EvaluateGoal(queries *database.Queries, goal database.Goal) (database.Goal, error) {
+	e := &goalEvaluator{queries: queries, userUuid: goal.UserUuid}
+	return e.evaluate(goal)
+}
+
+func (e *goalEvaluator) evaluate(goal database.Goal) (database.Goal, error) {
+	var progress, target int32
+	switch goal.Kind {
+	case "rating":
+		rating, err := e.loadRating()
+		if err != nil {
+			return goal, err
+		}
+		progress, target = rating, goal.TargetRating.Int32
+
+	case "chart":
+		records, err := e.loadRecords()
+		if err != nil {
+			return goal, err
+		}
+		target = 1
+		for _, r := range records {
+			if r.BeatmapID == goal.BeatmapID.Bytes && goalReached(goal, r) {
+				progress = 1
+			}
+		}
+
+	case "chart_set", "count":
+		records, err := e.loadRecords()
+		if err != nil {
+			return goal, err
+		}
+		for _, r := range records {
+			if goalCovers(goal, r) && goalReached(goal, r) {
+				progress++
+			}
+		}
+		if goal.Kind == "count" {
+			target = goal.TargetCount.Int32
+			break
+		}
+		total, err := e.queries.CountGoalCharts(context.Background(), database.CountGoalChartsParams{
+			Difficulty: goal.Difficulty,
+			Type:       goal.Type,
+			Level:      goal.Level,
+			LevelMin:   goal.LevelMin,
+			LevelMax:   goal.LevelMax,
+		})
+		if err != nil {
+			return goal, fmt.Errorf("failed to count charts: %w", err)
+		}
+		target = int32(total)
+
+	default:
+		return goal, fmt.Errorf("unknown goal kind %q", goal.Kind)
+	}
+
+	updated, err := e.queries.UpdateGoalProgress(context.Background(), database.UpdateGoalProgressParams{
+		Progress: progress,
+		Target:   target,
+		ID:       goal.ID,
+	})
+	if err != nil {
+		return goal, fmt.Errorf("failed to update goal progress: %w", err)
+	}
+	return updated, nil
+}
+
+func (e *goalEvaluator) loadRecords() ([]database.GetChartRecordsByUserUUIDRow, error) {
+	if e.loaded {
+		return e.records, nil
+	}
+	records, err := e.queries.GetChartRecordsByUserUUID(context.Background(), e.userUuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart records: %w", err)
+	}
+	e.records, e.loaded = records, true
+	return records, nil
+}
+
+// the latest rating, 0 before the first scrape
+func (e *goalEvaluator) loadRating() (int32, error) {
+	if e.rating != nil {
+		return *e.rating, nil
+	}
+	var rating int32
+	data, err := e.queries.GetUserDataByUserUUID(context.Background(), e.userUuid)
+	switch {
+	case err == nil:
+		rating = data.Rating
+	case !errors.Is(err, pgx.ErrNoRows):
+		return 0, fmt.Errorf("failed to get user data: %w", err)
+	}
+	e.rating = &rating
+	return rating, nil
+}
+
+// whether a chart is in the set of a chart_set or count goal, the same
+// filters as the CountGoalCharts query
+func goalCovers(goal database.Goal, r database.GetChartRecordsByUserUUIDRow) bool {
+	if !r.IsAvailable {
+		return false
+	}
+	if goal.Difficulty.Valid {
+		if r.Difficulty != goal.Difficulty.String {
+			return false
+		}
+	} else if r.Difficulty == "utage" {
+		return false
+	}
+	if goal.Type.Valid && r.Type != goal.Type.String {
+		return false
+	}
+	if goal.Level.Valid && r.Level != goal.Level.String {
+		return false
+	}
+	if goal.LevelMin.Valid || goal.LevelMax.Valid {
+		level, err := r.InternalLevel.Float64Value()
+		if err != nil || !level.Valid {
+			return false
+		}
+		if lo, _ := goal.LevelMin.Float64Value(); lo.Valid && level.Float64 < lo.Float64 {
+			return false
+		}
+		if hi, _ := goal.LevelMax.Float64Value(); hi.Valid && level.Float64 > hi.Float64 {
+			return false
+		}
+	}
+	return true
+}
+
+// whether a chart record meets the rank and lamp a goal asks for
+func goalReached(goal database.Goal, r database.GetChartRecordsByUserUUIDRow) bool {
+	if goal.TargetRank.Valid {
+		rank, ok := calculator.ParseRank(goal.TargetRank.String)
+		if !ok || calculator.RankOf(r.Achievement).Achievement < rank.Achievement {
+			return false
+		}
+	}
+	if goal.TargetLamp.Valid {
+		lamp, ok := calculator.ParseLamp(goal.TargetLamp.String)
+		if !ok || calculator.Lamp(r.Lamp) < lamp {
+			return false
+		}
+	}
+	return true
+}
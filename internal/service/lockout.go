@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	database "github.com/asashakira/maitrack/internal/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// failed logins allowed before an account is locked
+const lockoutThreshold = 5
+
+const (
+	lockoutBaseDuration = time.Minute
+	lockoutMaxDuration  = time.Hour
+
+	// failures are forgotten once none happened for this long
+	lockoutForgetAfter = 2 * lockoutMaxDuration
+
+	// how often the memory store drops forgotten accounts
+	lockoutPurgeInterval = 10 * time.Minute
+)
+
+// LoginLockout locks a userID out on one client address after repeated
+// failed logins from it, so nobody can lock a user out of their own
+// account from elsewhere. Guessing from many addresses is left to the
+// login rate limits. The first lock lasts a minute and doubles with every
+// further failure, up to an hour. A successful login clears the history.
+type LoginLockout struct {
+	store LockoutStore
+}
+
+// LockoutStore keeps the failed logins of each account.
+type LockoutStore interface {
+	// Failures returns the failed logins of key and when the last one happened.
+	Failures(ctx context.Context, key string) (int, time.Time, error)
+	// RecordFailure counts a failed login at now, starting over when the
+	// previous one happened before forgetBefore, and returns the failures.
+	RecordFailure(ctx context.Context, key string, now, forgetBefore time.Time) (int, error)
+	Clear(ctx context.Context, key string) error
+}
+
+// NewLoginLockout keeps failures in memory unless RATE_LIMIT_STORE=postgres,
+// which shares them between server instances through the rate_limits table.
+func NewLoginLockout(queries *database.Queries) *LoginLockout {
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		return &LoginLockout{store: &postgresLockoutStore{queries: queries}}
+	}
+	store := &memoryLockoutStore{accounts: map[string]*lockoutState{}}
+	go store.purgeEvery(lockoutPurgeInterval)
+	return &LoginLockout{store: store}
+}
+
+// rate_limits keys of the lockout, apart from the rate limiters' keys
+func lockoutKey(userID, ip string) string {
+	return "login-lockout:" + strings.ToLower(userID) + ":" + ip
+}
+
+// how long an account stays locked after its last failure
+func lockDuration(failures int) time.Duration {
+	if failures < lockoutThreshold {
+		return 0
+	}
+	lock := lockoutBaseDuration << (failures - lockoutThreshold)
+	if lock > lockoutMaxDuration || lock <= 0 {
+		lock = lockoutMaxDuration
+	}
+	return lock
+}
+
+// returns how long userID is still locked out on ip, 0 if it is not
+func (l *LoginLockout) LockedFor(ctx context.Context, userID, ip string) (time.Duration, error) {
+	failures, lastFailure, err := l.store.Failures(ctx, lockoutKey(userID, ip))
+	if err != nil {
+		return 0, err
+	}
+	return max(time.Until(lastFailure.Add(lockDuration(failures))), 0), nil
+}
+
+// records a failed login and returns the resulting lock duration
+func (l *LoginLockout) Fail(ctx context.Context, userID, ip string) (time.Duration, error) {
+	now := time.Now().UTC()
+	failures, err := l.store.RecordFailure(ctx, lockoutKey(userID, ip), now, now.Add(-lockoutForgetAfter))
+	if err != nil {
+		return 0, err
+	}
+	return lockDuration(failures), nil
+}
+
+func (l *LoginLockout) Reset(ctx context.Context, userID, ip string) error {
+	return l.store.Clear(ctx, lockoutKey(userID, ip))
+}
+
+type lockoutState struct {
+	failures    int
+	lastFailure time.Time
+}
+
+type memoryLockoutStore struct {
+	mu       sync.Mutex
+	accounts map[string]*lockoutState
+}
+
+func (s *memoryLockoutStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.accounts[key]
+	if !ok {
+		return 0, time.Time{}, nil
+	}
+	return state.failures, state.lastFailure, nil
+}
+
+func (s *memoryLockoutStore) RecordFailure(ctx context.Context, key string, now, forgetBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.accounts[key]
+	if !ok || state.lastFailure.Before(forgetBefore) {
+		state = &lockoutState{}
+		s.accounts[key] = state
+	}
+	state.failures++
+	state.lastFailure = now
+	return state.failures, nil
+}
+
+func (s *memoryLockoutStore) Clear(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.accounts, key)
+	return nil
+}
+
+// forget accounts that have not failed for a while. Every lock has ended
+// by then, as no lock outlasts lockoutForgetAfter.
+func (s *memoryLockoutStore) purgeEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		forgetBefore := time.Now().UTC().Add(-lockoutForgetAfter)
+		s.mu.Lock()
+		for key, state := range s.accounts {
+			if state.lastFailure.Before(forgetBefore) {
+				delete(s.accounts, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// rows are removed along with expired rate limit counters, see
+// postgresRateLimitStore in middleware/ratelimit.go
+type postgresLockoutStore struct {
+	queries *database.Queries
+}
+
+func (s *postgresLockoutStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	row, err := s.queries.GetLoginFailures(ctx, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, time.Time{}, nil
+		}
+		return 0, time.Time{}, fmt.Errorf("GetLoginFailures: %w", err)
+	}
+	return int(row.Count), row.WindowStart.Time, nil
+}
+
+func (s *postgresLockoutStore) RecordFailure(ctx context.Context, key string, now, forgetBefore time.Time) (int, error) {
+	failures, err := s.queries.RecordLoginFailure(ctx, database.RecordLoginFailureParams{
+		Key:          key,
+		WindowStart:  pgtype.Timestamp{Time: now, Valid: true},
+		ForgetBefore: pgtype.Timestamp{Time: forgetBefore, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("RecordLoginFailure: %w", err)
+	}
+	return int(failures), nil
+}
+
+func (s *postgresLockoutStore) Clear(ctx context.Context, key string) error {
+	if err := s.queries.DeleteLoginFailures(ctx, key); err != nil {
+		return fmt.Errorf("DeleteLoginFailures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package service
+
+const (
+	RoleUser    = "user"
+	RoleCurator = "curator"
+	RoleAdmin   = "admin"
+)
+
+// every role can do what the roles ranked below it can
+var roleRanks = map[string]int{
+	RoleUser:    0,
+	RoleCurator: 1,
+	RoleAdmin:   2,
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole reports whether userRole ranks at least as high as role.
+func HasRole(userRole, role string) bool {
+	return roleRanks[userRole] >= roleRanks[role]
+}
@@ -7,79 +7,86 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
-	"os"
+	"sync"
 )
 
 func encode(b []byte) string {
 	return base64.StdEncoding.EncodeToString(b)
 }
 
-func Encrypt(text string) (string, error) {
-	plainText := []byte(text)
-
-	secretKey := os.Getenv("SECRET_KEY")
-	if secretKey == "" {
-		return "", fmt.Errorf("SECRET_KEY not found")
-	}
-
-	block, err := aes.NewCipher([]byte(secretKey))
+func decode(s string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return data, nil
+}
 
-	gcm, err := cipher.NewGCM(block)
+// keyring loaded from the environment once, by InitKeyring at startup or
+// on first use
+var defaultKeyring = sync.OnceValues(LoadKeyring)
+
+// InitKeyring loads the keyring Encrypt and Decrypt use, so a bad
+// configuration fails at startup instead of on the first request.
+func InitKeyring() error {
+	_, err := defaultKeyring()
+	return err
+}
+
+// encrypts text with the active key of the keyring loaded from the environment
+func Encrypt(text string) (string, error) {
+	keyring, err := defaultKeyring()
 	if err != nil {
 		return "", err
 	}
+	return keyring.Encrypt(text)
+}
 
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+// decrypts text with whichever key of the keyring it was encrypted with
+func Decrypt(text string) (string, error) {
+	keyring, err := defaultKeyring()
+	if err != nil {
 		return "", err
 	}
-
-	ciphertext := gcm.Seal(nonce, nonce, plainText, nil)
-	return encode(ciphertext), nil
+	return keyring.Decrypt(text)
 }
 
-func decode(s string) ([]byte, error) {
-	data, err := base64.StdEncoding.DecodeString(s)
+// AES-GCM seal with a random nonce prepended to the ciphertext
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
-}
 
-func Decrypt(text string) (string, error) {
-	secretKey := os.Getenv("SECRET_KEY")
-	if secretKey == "" {
-		return "", fmt.Errorf("SECRET_KEY not found")
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
 	}
 
-	ciphertext, err := decode(text)
-	if err != nil {
-		return "", err
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
 	}
 
-	block, err := aes.NewCipher([]byte(secretKey))
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	nonceSize := gcm.NonceSize()
 	if len(ciphertext) < nonceSize {
-		return "", fmt.Errorf("ciphertext too short")
+		return nil, fmt.Errorf("ciphertext too short")
 	}
 
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return "", err
-	}
-
-	return string(plaintext), nil
+	return gcm.Open(nil, nonce, ciphertext, nil)
 }
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrorCode is the machine-readable part of an error response. Clients
+// branch on the code, the message is only meant for humans and may change.
+type ErrorCode string
+
+const (
+	CodeBadRequest             ErrorCode = "BAD_REQUEST"
+	CodeValidationFailed       ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized           ErrorCode = "UNAUTHORIZED"
+	CodeInvalidCredentials     ErrorCode = "INVALID_CREDENTIALS"
+	CodeInvalidSegaCredentials ErrorCode = "INVALID_SEGA_CREDENTIALS"
+	CodeForbidden              ErrorCode = "FORBIDDEN"
+	CodeNotFound               ErrorCode = "NOT_FOUND"
+	CodeConflict               ErrorCode = "CONFLICT"
+	CodeUserExists             ErrorCode = "USER_EXISTS"
+	CodeRateLimited            ErrorCode = "RATE_LIMITED"
+	CodeInternal               ErrorCode = "INTERNAL_ERROR"
+	CodeUpstreamError          ErrorCode = "UPSTREAM_ERROR"
+	CodeUpstreamMaintenance    ErrorCode = "UPSTREAM_MAINTENANCE"
+)
+
+// postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgStringTooLong        = "22001"
+	pgNumericOutOfRange    = "22003"
+	pgInvalidDatetime      = "22007"
+	pgDatetimeOutOfRange   = "22008"
+	pgInvalidTextRepresent = "22P02"
+)
+
+// FieldError describes why one field of a request was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// APIError is rendered as
+//
+//	{"error": {"code": "NOT_FOUND", "message": "...", "details": [...]}}
+//
+// Details are only set for VALIDATION_FAILED.
+type APIError struct {
+	Status  int          `json:"-"`
+	Code    ErrorCode    `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+
+	// logged for 5xx responses, never sent to the client
+	cause error
+}
+
+func (e *APIError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %s", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.cause
+}
+
+func NewAPIError(status int, code ErrorCode, format string, args ...any) *APIError {
+	return &APIError{Status: status, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func BadRequest(format string, args ...any) *APIError {
+	return NewAPIError(http.StatusBadRequest, CodeBadRequest, format, args...)
+}
+
+func NotFound(format string, args ...any) *APIError {
+	return NewAPIError(http.StatusNotFound, CodeNotFound, format, args...)
+}
+
+func Conflict(code ErrorCode, format string, args ...any) *APIError {
+	return NewAPIError(http.StatusConflict, code, format, args...)
+}
+
+func Unauthorized() *APIError {
+	return NewAPIError(http.StatusUnauthorized, CodeUnauthorized, "Unauthorized")
+}
+
+func Forbidden() *APIError {
+	return NewAPIError(http.StatusForbidden, CodeForbidden, "Forbidden")
+}
+
+// ValidationFailed reports every invalid field of a request at once.
+func ValidationFailed(details ...FieldError) *APIError {
+	return &APIError{
+		Status:  http.StatusBadRequest,
+		Code:    CodeValidationFailed,
+		Message: "validation failed",
+		Details: details,
+	}
+}
+
+// Invalid is ValidationFailed for a single field.
+func Invalid(field, format string, args ...any) *APIError {
+	return ValidationFailed(FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Internal hides cause from the client but keeps it for the log.
+func Internal(cause error) *APIError {
+	return &APIError{
+		Status:  http.StatusInternalServerError,
+		Code:    CodeInternal,
+		Message: "Internal Server Error",
+		cause:   cause,
+	}
+}
+
+// Upstream is returned when maimai DX NET fails in a way that is not the
+// user's fault.
+func Upstream(cause error) *APIError {
+	return &APIError{
+		Status:  http.StatusBadGateway,
+		Code:    CodeUpstreamError,
+		Message: "maimai DX NET could not be reached, please try again later",
+		cause:   cause,
+	}
+}
+
+func UpstreamMaintenance() *APIError {
+	return NewAPIError(http.StatusServiceUnavailable, CodeUpstreamMaintenance, "maimai DX NET is under maintenance, please try again later")
+}
+
+// DatabaseError maps errors from sqlc queries to API errors: no rows
+// becomes NOT_FOUND with notFound as message, constraint and input
+// violations become CONFLICT or VALIDATION_FAILED and anything else is
+// an internal error. Raw postgres messages are never sent to the client.
+func DatabaseError(err error, notFound string) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotFound("%s", notFound)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return Internal(err)
+	}
+
+	field := pgErr.ColumnName
+	if field == "" {
+		field = pgErr.ConstraintName
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return Conflict(CodeConflict, "a resource with the same %s already exists", field)
+	case pgForeignKeyViolation:
+		return Invalid(field, "refers to a resource that does not exist")
+	case pgNotNullViolation:
+		return Invalid(field, "is required")
+	case pgCheckViolation:
+		return Invalid(field, "is not an allowed value")
+	case pgStringTooLong:
+		return Invalid(field, "is too long")
+	case pgNumericOutOfRange:
+		return Invalid(field, "is out of range")
+	case pgInvalidDatetime, pgDatetimeOutOfRange, pgInvalidTextRepresent:
+		return Invalid(field, "has an invalid format")
+	}
+	return Internal(err)
+}
+
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// voiced and small katakana mapped to their plain full-size form
+var kanaFolds = buildKanaFolds(
+	"ガギグゲゴザジズゼゾダヂヅデドバビブベボパピプペポヴァィゥェォッャュョヮヵヶ",
+	"カキクケコサシスセソタチツテトハヒフヘホハヒフヘホウアイウエオツヤユヨワカケ",
+)
+
+func buildKanaFolds(from, to string) map[rune]rune {
+	f, t := []rune(from), []rune(to)
+	folds := make(map[rune]rune, len(f))
+	for i := range f {
+		folds[f[i]] = t[i]
+	}
+	return folds
+}
+
+// NormalizeKana folds a reading into the form song readings are stored
+// and searched in: hiragana become katakana, voiced and small kana become
+// their plain form, latin letters are uppercased and everything that is
+// not a letter or digit, including "ー", is dropped.
+//
+//	"うみゆり" -> "ウミユリ", "ぽっぴっぽー" -> "ホツヒツホ"
+func NormalizeKana(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		// hiragana ぁ-ゖ sit 0x60 below their katakana
+		if r >= 'ぁ' && r <= 'ゖ' {
+			r += 0x60
+		}
+		if folded, ok := kanaFolds[r]; ok {
+			r = folded
+		}
+		if r == 'ー' || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			continue
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+var kanaRomaji = map[rune]string{
+	'ア': "a", 'イ': "i", 'ウ': "u", 'エ': "e", 'オ': "o",
+	'カ': "ka", 'キ': "ki", 'ク': "ku", 'ケ': "ke", 'コ': "ko",
+	'ガ': "ga", 'ギ': "gi", 'グ': "gu", 'ゲ': "ge", 'ゴ': "go",
+	'サ': "sa", 'シ': "shi", 'ス': "su", 'セ': "se", 'ソ': "so",
+	'ザ': "za", 'ジ': "ji", 'ズ': "zu", 'ゼ': "ze", 'ゾ': "zo",
+	'タ': "ta", 'チ': "chi", 'ツ': "tsu", 'テ': "te", 'ト': "to",
+	'ダ': "da", 'ヂ': "ji", 'ヅ': "zu", 'デ': "de", 'ド': "do",
+	'ナ': "na", 'ニ': "ni", 'ヌ': "nu", 'ネ': "ne", 'ノ': "no",
+	'ハ': "ha", 'ヒ': "hi", 'フ': "fu", 'ヘ': "he", 'ホ': "ho",
+	'バ': "ba", 'ビ': "bi", 'ブ': "bu", 'ベ': "be", 'ボ': "bo",
+	'パ': "pa", 'ピ': "pi", 'プ': "pu", 'ペ': "pe", 'ポ': "po",
+	'マ': "ma", 'ミ': "mi", 'ム': "mu", 'メ': "me", 'モ': "mo",
+	'ヤ': "ya", 'ユ': "yu", 'ヨ': "yo",
+	'ラ': "ra", 'リ': "ri", 'ル': "ru", 'レ': "re", 'ロ': "ro",
+	'ワ': "wa", 'ヰ': "i", 'ヱ': "e", 'ヲ': "o", 'ン': "n", 'ヴ': "vu",
+	'ヵ': "ka", 'ヶ': "ke",
+}
+
+// small kana that combine with the kana before them
+var (
+	smallYouon = map[rune]string{'ャ': "a", 'ュ': "u", 'ョ': "o"}
+	smallVowel = map[rune]string{'ァ': "a", 'ィ': "i", 'ゥ': "u", 'ェ': "e", 'ォ': "o", 'ヮ': "a"}
+)
+
+// Romanize transliterates kana to Hepburn-style romaji, e.g.
+// "ぽっぴっぽー" -> "poppippo" and "シャルル" -> "sharuru". Latin letters,
+// digits and punctuation are kept, full-width ones narrowed. Returns false
+// when s contains kanji or other text there is no reading for.
+func Romanize(s string) (string, bool) {
+	var out []string
+	// a pending ッ doubles the consonant of the next kana
+	sokuon := false
+
+	for _, r := range s {
+		// hiragana ぁ-ゖ sit 0x60 below their katakana
+		if r >= 'ぁ' && r <= 'ゖ' {
+			r += 0x60
+		}
+		// full-width ASCII
+		if r >= '！' && r <= '～' {
+			r -= 0xFEE0
+		}
+
+		var syllable string
+		switch {
+		case r == 'ッ':
+			sokuon = true
+			continue
+		case r == 'ー':
+			// long vowels are not written
+			continue
+		case r == '・' || r == '　':
+			syllable = " "
+		case smallYouon[r] != "" && len(out) > 0 && strings.HasSuffix(out[len(out)-1], "i"):
+			// キャ -> kya, シャ -> sha
+			prev := strings.TrimSuffix(out[len(out)-1], "i")
+			if !strings.HasSuffix(prev, "sh") && !strings.HasSuffix(prev, "ch") && prev != "j" {
+				prev += "y"
+			}
+			out[len(out)-1] = prev + smallYouon[r]
+			continue
+		case smallVowel[r] != "" && len(out) > 0 && isKanaSyllable(out[len(out)-1]):
+			// ファ -> fa, ティ -> ti, ウィ -> wi
+			prev := out[len(out)-1]
+			consonant := strings.TrimRight(prev, "aiueo")
+			if consonant == "" {
+				consonant = "w"
+			}
+			out[len(out)-1] = consonant + smallVowel[r]
+			continue
+		case smallYouon[r] != "":
+			syllable = "y" + smallYouon[r]
+		case smallVowel[r] != "":
+			syllable = smallVowel[r]
+		case kanaRomaji[r] != "":
+			syllable = kanaRomaji[r]
+		case r < unicode.MaxASCII:
+			syllable = string(r)
+		case unicode.IsLetter(r):
+			// kanji and anything else we have no reading for
+			return "", false
+		default:
+			syllable = string(r)
+		}
+
+		if sokuon {
+			sokuon = false
+			if isKanaSyllable(syllable) && !strings.ContainsAny(syllable[:1], "aiueon") {
+				if strings.HasPrefix(syllable, "ch") {
+					syllable = "t" + syllable
+				} else {
+					syllable = syllable[:1] + syllable
+				}
+			}
+		}
+		out = append(out, syllable)
+	}
+
+	return strings.Join(out, ""), true
+}
+
+func isKanaSyllable(s string) bool {
+	return s != "" && s[0] >= 'a' && s[0] <= 'z'
+}
+
+// RomanizeWithReading romanizes text, falling back to its kana reading
+// when text itself has kanji. Returns "" when neither can be read.
+func RomanizeWithReading(text, reading string) string {
+	if romaji, ok := Romanize(text); ok {
+		return romaji
+	}
+	if romaji, ok := Romanize(reading); ok && reading != "" {
+		return romaji
+	}
+	return ""
+}
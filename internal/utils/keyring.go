@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Ciphertexts use envelope encryption:
+//
+//	v1$<key id>$<sealed data key>$<sealed text>
+//
+// The data key is sealed with the key named by the key id and the text
+// with the data key, both base64 encoded. Every value gets its own random
+// data key. Rotating re-encrypts the value under a fresh data key, so a
+// retired key cannot open the rotated ciphertext even together with an
+// old copy of it. Values without the "v1$" prefix were written before
+// keyrings existed and are sealed directly with SECRET_KEY.
+const (
+	envelopeVersion = "v1"
+	envelopeSep     = "$"
+
+	// key id SECRET_KEY is registered under
+	LegacyKeyID = "legacy"
+
+	dataKeySize = 32
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+type Keyring struct {
+	keys     map[string][]byte
+	activeID string
+}
+
+// LoadKeyring builds the keyring from the environment.
+//
+//	ENCRYPTION_KEYS=2025-01:<32 byte key>,2025-06:<32 byte key>
+//	ENCRYPTION_ACTIVE_KEY_ID=2025-06
+//
+// SECRET_KEY is kept as the "legacy" key so values encrypted before
+// rotation was introduced stay readable. When no active key id is set
+// the legacy key is used for new values.
+func LoadKeyring() (*Keyring, error) {
+	keys := map[string][]byte{}
+
+	if secretKey := os.Getenv("SECRET_KEY"); secretKey != "" {
+		keys[LegacyKeyID] = []byte(secretKey)
+	}
+
+	for _, entry := range strings.Split(os.Getenv("ENCRYPTION_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid ENCRYPTION_KEYS entry: expected <id>:<key>")
+		}
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid key id '%s'", id)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("duplicate key id '%s'", id)
+		}
+		keys[id] = []byte(key)
+	}
+
+	activeID := os.Getenv("ENCRYPTION_ACTIVE_KEY_ID")
+	if activeID == "" {
+		activeID = LegacyKeyID
+	}
+
+	return NewKeyring(keys, activeID)
+}
+
+func NewKeyring(keys map[string][]byte, activeID string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("SECRET_KEY not found")
+	}
+	for id, key := range keys {
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("key '%s' must be 16, 24 or 32 bytes long", id)
+		}
+	}
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("active key '%s' not found in keyring", activeID)
+	}
+	return &Keyring{keys: keys, activeID: activeID}, nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+func (k *Keyring) Encrypt(text string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	sealedText, err := seal(dataKey, []byte(text))
+	if err != nil {
+		return "", err
+	}
+
+	sealedKey, err := seal(k.keys[k.activeID], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{envelopeVersion, k.activeID, encode(sealedKey), encode(sealedText)}, envelopeSep), nil
+}
+
+func (k *Keyring) Decrypt(text string) (string, error) {
+	env, err := parseEnvelope(text)
+	if err != nil {
+		return "", err
+	}
+
+	// values from before envelopes were sealed directly with SECRET_KEY
+	if env.legacy {
+		key, ok := k.keys[LegacyKeyID]
+		if !ok {
+			return "", fmt.Errorf("legacy key not found in keyring")
+		}
+		plaintext, err := open(key, env.sealedText)
+		if err != nil {
+			return "", err
+		}
+		return string(plaintext), nil
+	}
+
+	dataKey, err := k.openDataKey(env)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, env.sealedText)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap re-encrypts text under the active key with a fresh data key.
+// Returns the text unchanged and false if it already uses the active key.
+func (k *Keyring) Rewrap(text string) (string, bool, error) {
+	env, err := parseEnvelope(text)
+	if err != nil {
+		return "", false, err
+	}
+	if !env.legacy && env.keyID == k.activeID {
+		return text, false, nil
+	}
+
+	plaintext, err := k.Decrypt(text)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := k.Encrypt(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return rewrapped, true, nil
+}
+
+func (k *Keyring) openDataKey(env envelope) ([]byte, error) {
+	key, ok := k.keys[env.keyID]
+	if !ok {
+		return nil, fmt.Errorf("key '%s' not found in keyring", env.keyID)
+	}
+	dataKey, err := open(key, env.sealedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+type envelope struct {
+	legacy     bool
+	keyID      string
+	sealedKey  []byte
+	sealedText []byte
+}
+
+func parseEnvelope(text string) (envelope, error) {
+	if !strings.HasPrefix(text, envelopeVersion+envelopeSep) {
+		sealedText, err := decode(text)
+		if err != nil {
+			return envelope{}, err
+		}
+		return envelope{legacy: true, sealedText: sealedText}, nil
+	}
+
+	parts := strings.Split(text, envelopeSep)
+	if len(parts) != 4 {
+		return envelope{}, fmt.Errorf("malformed ciphertext")
+	}
+	sealedKey, err := decode(parts[2])
+	if err != nil {
+		return envelope{}, err
+	}
+	sealedText, err := decode(parts[3])
+	if err != nil {
+		return envelope{}, err
+	}
+	return envelope{keyID: parts[1], sealedKey: sealedKey, sealedText: sealedText}, nil
+}
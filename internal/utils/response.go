@@ -2,19 +2,27 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 )
 
-func RespondWithError(w http.ResponseWriter, code int, msg string) {
-	if code > 499 {
-		log.Println("Responding with 5XX error: ", msg)
+// RespondWithError writes err in the error envelope. Errors that are not
+// an *APIError are treated as internal errors so their text never reaches
+// the client.
+func RespondWithError(w http.ResponseWriter, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = Internal(err)
+	}
+	if apiErr.Status > 499 {
+		log.Println("Responding with 5XX error: ", apiErr)
 	}
 	type errResponse struct {
-		Error string `json:"error"`
+		Error *APIError `json:"error"`
 	}
-	RespondWithJSON(w, code, errResponse{
-		Error: msg,
+	RespondWithJSON(w, apiErr.Status, errResponse{
+		Error: apiErr,
 	})
 }
 
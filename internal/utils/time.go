@@ -33,3 +33,10 @@ func StringToUTCTime(value string) (time.Time, error) {
 
 	return utcTime, nil
 }
+
+// JSTDay returns the start of the JST day t falls on, in JST
+func JSTDay(t time.Time) time.Time {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	t = t.In(jst)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, jst)
+}
@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Validate checks the `validate` tags of a request struct and returns a
+// VALIDATION_FAILED error listing every invalid field, or nil. Fields are
+// reported under their json name. Rules are separated by commas:
+//
+//	required       must be set: non-empty string, non-nil pointer, non-zero UUID
+//	nonempty       may be omitted, but must not be an empty string when given
+//	min=N, max=N   numbers must be within [N, ...] / [..., N]
+//	maxlen=N       strings must be at most N characters
+//	oneof=a b c    strings must be one of the listed values
+//	uuid           strings must be a UUID
+//	userid         3-20 letters, numbers, '_' or '-'
+//	password       8-72 bytes with at least one letter and one number
+//	accuracy       achievement as shown in game, e.g. "100.5000%"
+//	jsttime        JST time formatted as "2006-01-02 15:04"
+//	twitterid      1-15 letters, numbers or underscores
+//	dive           validate a nested struct, its fields are reported as
+//	               parent.child
+//
+// Rules other than required and nonempty are skipped for nil pointers and
+// empty strings, so optional fields are only checked when given.
+func Validate(v any) error {
+	details := validateStruct(reflect.Indirect(reflect.ValueOf(v)), "")
+	if len(details) > 0 {
+		return ValidationFailed(details...)
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, prefix string) []FieldError {
+	rt := rv.Type()
+
+	var details []FieldError
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		name := prefix + jsonName(rt.Field(i))
+		if tag == "dive" {
+			if field := reflect.Indirect(rv.Field(i)); field.IsValid() {
+				details = append(details, validateStruct(field, name+".")...)
+			}
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if msg := checkRule(rv.Field(i), rule); msg != "" {
+				details = append(details, FieldError{Field: name, Message: msg})
+				// one message per field is enough
+				break
+			}
+		}
+	}
+	return details
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+var (
+	userIDPattern    = regexp.MustCompile(`^[A-Za-z0-9_-]{3,20}$`)
+	accuracyPattern  = regexp.MustCompile(`^\d{1,3}\.\d{4}%$`)
+	twitterIDPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+)
+
+// maimai caps achievement at 101%
+const maxAccuracy = 101
+
+// returns why v breaks rule, "" if it does not
+func checkRule(v reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	if name == "required" {
+		if isEmpty(v) {
+			return "is required"
+		}
+		return ""
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" {
+		if name == "nonempty" {
+			return "must not be empty"
+		}
+		return ""
+	}
+
+	switch name {
+	case "nonempty":
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: bad %s rule %q", name, rule))
+		}
+		n, ok := number(v)
+		if !ok {
+			panic(fmt.Sprintf("validate: %s rule on %s", name, v.Kind()))
+		}
+		if name == "min" && n < limit {
+			return fmt.Sprintf("must be at least %s", arg)
+		}
+		if name == "max" && n > limit {
+			return fmt.Sprintf("must be at most %s", arg)
+		}
+	case "maxlen":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			panic(fmt.Sprintf("validate: bad maxlen rule %q", rule))
+		}
+		if utf8.RuneCountInString(v.String()) > limit {
+			return fmt.Sprintf("must be at most %d characters", limit)
+		}
+	case "oneof":
+		allowed := strings.Fields(arg)
+		if !slices.Contains(allowed, v.String()) {
+			return "must be one of " + strings.Join(allowed, ", ")
+		}
+	case "uuid":
+		if _, err := uuid.Parse(v.String()); err != nil {
+			return "must be a UUID"
+		}
+	case "userid":
+		if !userIDPattern.MatchString(v.String()) {
+			return "must be 3-20 letters, numbers, '_' or '-'"
+		}
+	case "password":
+		return checkPassword(v.String())
+	case "accuracy":
+		s := v.String()
+		if !accuracyPattern.MatchString(s) {
+			return "must be formatted like 100.5000%"
+		}
+		if f, _ := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64); f > maxAccuracy {
+			return fmt.Sprintf("must be at most %d%%", maxAccuracy)
+		}
+	case "jsttime":
+		if _, err := StringToUTCTime(v.String()); err != nil {
+			return "must be a JST time like 2006-01-02 15:04"
+		}
+	case "twitterid":
+		if !twitterIDPattern.MatchString(v.String()) {
+			return "must be 1-15 letters, numbers or underscores"
+		}
+	default:
+		panic(fmt.Sprintf("validate: unknown rule %q", rule))
+	}
+	return ""
+}
+
+func checkPassword(s string) string {
+	// bcrypt ignores everything after 72 bytes
+	if len(s) < 8 || len(s) > 72 {
+		return "must be 8-72 characters"
+	}
+	hasLetter := strings.ContainsFunc(s, unicode.IsLetter)
+	hasDigit := strings.ContainsFunc(s, unicode.IsDigit)
+	if !hasLetter || !hasDigit {
+		return "must contain at least one letter and one number"
+	}
+	return ""
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	}
+	if u, ok := v.Interface().(uuid.UUID); ok {
+		return u == uuid.Nil
+	}
+	return false
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// JSTTime parses a time validated with the jsttime rule
+func JSTTime(s string) time.Time {
+	t, _ := StringToUTCTime(s)
+	return t
+}
@@ -19,6 +19,12 @@ const (
 	ErrorEndpoint    = "/error/"
 )
 
+var (
+	ErrInvalidCredentials = errors.New("incorrect SEGA ID or password")
+	// maimai DX NET goes down for maintenance every night and on update days
+	ErrMaintenance = errors.New("maimai DX NET is under maintenance")
+)
+
 type Client struct {
 	HTTPClient *http.Client
 }
@@ -59,6 +65,10 @@ func (m *Client) Login(segaId, password string) error {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusServiceUnavailable {
+		return ErrMaintenance
+	}
+
 	csrfToken, extractCSRFTokenErr := extractCSRFToken(res)
 	if extractCSRFTokenErr != nil {
 		return extractCSRFTokenErr
@@ -90,7 +100,7 @@ func (m *Client) Login(segaId, password string) error {
 
 	// check if login was successful
 	if loginRes.Request.URL.String() == BaseURL+ErrorEndpoint {
-		return fmt.Errorf("login failed: incorrect Sega ID or password")
+		return fmt.Errorf("login failed: %w", ErrInvalidCredentials)
 	}
 
 	// redirect to set cookies
@@ -122,6 +132,10 @@ func extractCSRFToken(res *http.Response) (string, error) {
 
 	csrfToken := doc.Find(`.black input[type="hidden"]`).AttrOr("value", "")
 	if csrfToken == "" {
+		// the maintenance page has no login form
+		if strings.Contains(doc.Text(), "メンテナンス") {
+			return "", ErrMaintenance
+		}
 		return "", errors.New("CSRF token not found")
 	}
 
@@ -0,0 +1,119 @@
+// Package maitrackapi is a typed client for the maitrack v1 API.
+//
+// The request and response types and one method per operation are
+// generated from internal/api/openapi/openapi.json into maitrackapi.gen.go.
+//
+//	c := maitrackapi.New("http://localhost:8080/v1")
+//	if _, err := c.Login(ctx, maitrackapi.LoginRequest{UserID: "...", Password: "..."}); err != nil {
+//		...
+//	}
+//	scores, err := c.ListScores(ctx, "someone", nil)
+package maitrackapi
+
+//go:generate go run ../../cmd/openapigen -out maitrackapi.gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+)
+
+type Client struct {
+	// e.g. "http://localhost:8080/v1"
+	BaseURL string
+	// keeps the auth_token cookie set by Login and Register
+	HTTPClient *http.Client
+	// sent as a bearer token when set, instead of relying on the cookie
+	Token string
+}
+
+func New(baseURL string) *Client {
+	jar, _ := cookiejar.New(nil)
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Jar: jar},
+	}
+}
+
+// Error is returned for every non-2xx response, branch on Code.
+type Error struct {
+	StatusCode int
+	ErrorBody
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("maitrack: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (*http.Response, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		var envelope struct {
+			Error ErrorBody `json:"error"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+			envelope.Error.Message = res.Status
+		}
+		return res, &Error{StatusCode: res.StatusCode, ErrorBody: envelope.Error}
+	}
+
+	if out != nil && res.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return res, fmt.Errorf("maitrack: decoding %s %s: %w", method, path, err)
+		}
+	}
+	return res, nil
+}
+
+// returns the cursor of the rel="next" Link, "" on the last page
+func nextCursor(res *http.Response) string {
+	for _, link := range strings.Split(res.Header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("cursor")
+	}
+	return ""
+}
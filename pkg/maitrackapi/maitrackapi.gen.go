@@ -0,0 +1,2120 @@
+// Code generated by cmd/openapigen from internal/api/openapi/openapi.json. DO NOT EDIT.
+
+package maitrackapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// currentStreak, the streak that includes today or yesterday, and longestStreak are null when there is none
+type Activity struct {
+	// first JST day of the calendar
+	From string `json:"from"`
+	// last JST day of the calendar
+	To         string `json:"to"`
+	TotalPlays int64  `json:"totalPlays"`
+	// days with plays
+	ActiveDays int32 `json:"activeDays"`
+	// every day from from to to, oldest first
+	Days          []ActivityDay `json:"days"`
+	CurrentStreak *Streak       `json:"currentStreak,omitempty"`
+	LongestStreak *Streak       `json:"longestStreak,omitempty"`
+	// the 10 beatmaps played most in the calendar range
+	MostPlayed []PlayedBeatmap `json:"mostPlayed"`
+	// plays in the calendar range per version of the song, most played first
+	Versions []VersionPlays `json:"versions"`
+}
+
+type ActivityDay struct {
+	// JST date
+	Date  string `json:"date"`
+	Plays int64  `json:"plays"`
+}
+
+type AddRivalRequest struct {
+	UserID string `json:"userID"`
+}
+
+type AliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+type Analytics struct {
+	Plays      int64          `json:"plays"`
+	Judgements JudgementRates `json:"judgements"`
+	// share of breaks judged CRITICAL PERFECT
+	BreakCriticalRate *float64 `json:"breakCriticalRate"`
+	FastLate          FastLate `json:"fastLate"`
+	// oldest week first
+	FastLateTrend []FastLateWeek `json:"fastLateTrend"`
+	// change of the weekly bias per week from a least squares fit, positive when getting later, null with fewer than two weeks
+	FastLateSlope *float64 `json:"fastLateSlope"`
+	// the most GOODs and MISSes per note first
+	WeakSpots []WeakSpot `json:"weakSpots"`
+}
+
+// song fields are only included by the endpoints that join the song
+type Beatmap struct {
+	ID     string `json:"id"`
+	SongID string `json:"songID"`
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty    string   `json:"difficulty"`
+	Level         string   `json:"level"`
+	InternalLevel *float64 `json:"internalLevel"`
+	// one of dx, std, utage
+	Type         string  `json:"type"`
+	TotalNotes   int32   `json:"totalNotes"`
+	Tap          int32   `json:"tap"`
+	Hold         int32   `json:"hold"`
+	Slide        int32   `json:"slide"`
+	Touch        int32   `json:"touch"`
+	Break        int32   `json:"break"`
+	NoteDesigner string  `json:"noteDesigner"`
+	MaxDxScore   int32   `json:"maxDxScore"`
+	Title        *string `json:"title,omitempty"`
+	Artist       *string `json:"artist,omitempty"`
+	Genre        *string `json:"genre,omitempty"`
+	Bpm          *string `json:"bpm,omitempty"`
+	ImageUrl     *string `json:"imageUrl,omitempty"`
+	Version      *string `json:"version,omitempty"`
+}
+
+// me is the signed in user's entry wherever it is on the leaderboard, omitted when signed out or not on it
+type BeatmapLeaderboard struct {
+	Entries    []BeatmapLeaderboardEntry `json:"entries"`
+	Me         *BeatmapLeaderboardEntry  `json:"me,omitempty"`
+	NextCursor *string                   `json:"nextCursor,omitempty"`
+	HasMore    bool                      `json:"hasMore"`
+}
+
+type BeatmapLeaderboardEntry struct {
+	// users with the same achievement, DX score and play date share a rank
+	Rank        int64     `json:"rank"`
+	UserID      string    `json:"userID"`
+	DisplayName string    `json:"displayName"`
+	ScoreID     string    `json:"scoreID"`
+	Accuracy    string    `json:"accuracy"`
+	DxScore     int32     `json:"dxScore"`
+	PlayedAt    time.Time `json:"playedAt"`
+	// the entry of the signed in user
+	IsMe bool `json:"isMe"`
+}
+
+// user and rival are omitted for the side that has not played the chart
+type ChartComparison struct {
+	BeatmapID     string        `json:"beatmapID"`
+	SongID        string        `json:"songID"`
+	Title         string        `json:"title"`
+	ImageUrl      string        `json:"imageUrl"`
+	Difficulty    string        `json:"difficulty"`
+	Level         string        `json:"level"`
+	InternalLevel *float64      `json:"internalLevel"`
+	Type          string        `json:"type"`
+	User          *PersonalBest `json:"user,omitempty"`
+	Rival         *PersonalBest `json:"rival,omitempty"`
+	// better achievement, then better DX score. Null unless both played the chart, one of user, rival, tie
+	Leader *string `json:"leader"`
+	// the user's achievement minus the rival's
+	Margin *float64 `json:"margin"`
+}
+
+type CreateBeatmapRequest struct {
+	SongID string `json:"songID"`
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty    string   `json:"difficulty"`
+	Level         string   `json:"level"`
+	InternalLevel *float64 `json:"internalLevel,omitempty"`
+	// one of dx, std, utage
+	Type         string  `json:"type"`
+	TotalNotes   *int32  `json:"totalNotes,omitempty"`
+	Tap          *int32  `json:"tap,omitempty"`
+	Hold         *int32  `json:"hold,omitempty"`
+	Slide        *int32  `json:"slide,omitempty"`
+	Touch        *int32  `json:"touch,omitempty"`
+	Break        *int32  `json:"break,omitempty"`
+	NoteDesigner *string `json:"noteDesigner,omitempty"`
+	MaxDxScore   *int32  `json:"maxDxScore,omitempty"`
+	PlayCount    *int32  `json:"playCount,omitempty"`
+}
+
+type CreateScoreRequest struct {
+	BeatmapID string `json:"beatmapID"`
+	SongID    string `json:"songID"`
+	UserUuid  string `json:"userUuid"`
+	// at most 101.0000%
+	Accuracy      string `json:"accuracy"`
+	MaxCombo      *int32 `json:"maxCombo,omitempty"`
+	DxScore       *int32 `json:"dxScore,omitempty"`
+	TapCritical   *int32 `json:"tapCritical,omitempty"`
+	TapPerfect    *int32 `json:"tapPerfect,omitempty"`
+	TapGreat      *int32 `json:"tapGreat,omitempty"`
+	TapGood       *int32 `json:"tapGood,omitempty"`
+	TapMiss       *int32 `json:"tapMiss,omitempty"`
+	HoldCritical  *int32 `json:"holdCritical,omitempty"`
+	HoldPerfect   *int32 `json:"holdPerfect,omitempty"`
+	HoldGreat     *int32 `json:"holdGreat,omitempty"`
+	HoldGood      *int32 `json:"holdGood,omitempty"`
+	HoldMiss      *int32 `json:"holdMiss,omitempty"`
+	SlideCritical *int32 `json:"slideCritical,omitempty"`
+	SlidePerfect  *int32 `json:"slidePerfect,omitempty"`
+	SlideGreat    *int32 `json:"slideGreat,omitempty"`
+	SlideGood     *int32 `json:"slideGood,omitempty"`
+	SlideMiss     *int32 `json:"slideMiss,omitempty"`
+	TouchCritical *int32 `json:"touchCritical,omitempty"`
+	TouchPerfect  *int32 `json:"touchPerfect,omitempty"`
+	TouchGreat    *int32 `json:"touchGreat,omitempty"`
+	TouchGood     *int32 `json:"touchGood,omitempty"`
+	TouchMiss     *int32 `json:"touchMiss,omitempty"`
+	BreakCritical *int32 `json:"breakCritical,omitempty"`
+	BreakPerfect  *int32 `json:"breakPerfect,omitempty"`
+	BreakGreat    *int32 `json:"breakGreat,omitempty"`
+	BreakGood     *int32 `json:"breakGood,omitempty"`
+	BreakMiss     *int32 `json:"breakMiss,omitempty"`
+	Fast          *int32 `json:"fast,omitempty"`
+	Late          *int32 `json:"late,omitempty"`
+	// JST time formatted as 2006-01-02 15:04
+	PlayedAt string `json:"playedAt"`
+}
+
+type CreateSongRequest struct {
+	Title     string  `json:"title"`
+	TitleKana *string `json:"titleKana,omitempty"`
+	Artist    string  `json:"artist"`
+	// reading of the artist, only used to romanize artists with kanji
+	ArtistKana  *string `json:"artistKana,omitempty"`
+	Genre       string  `json:"genre"`
+	Bpm         *string `json:"bpm,omitempty"`
+	ImageUrl    *string `json:"imageUrl,omitempty"`
+	Version     string  `json:"version"`
+	IsUtage     *bool   `json:"isUtage,omitempty"`
+	IsAvailable *bool   `json:"isAvailable,omitempty"`
+	// JST time formatted as 2006-01-02 15:04
+	ReleaseDate string `json:"releaseDate"`
+	// JST time formatted as 2006-01-02 15:04
+	DeleteDate *string `json:"deleteDate,omitempty"`
+}
+
+type DxStarDifficulty struct {
+	Difficulty string `json:"difficulty"`
+	Played     int32  `json:"played"`
+	// 5 stars first
+	DxStars []LevelTableCount `json:"dxStars"`
+}
+
+type DxStarStats struct {
+	// charts matching the filters
+	Charts int32 `json:"charts"`
+	Played int32 `json:"played"`
+	// played charts whose max DX score is not known, not counted in dxStars
+	Unknown int32 `json:"unknown"`
+	// 5 stars first
+	DxStars []LevelTableCount `json:"dxStars"`
+	// played difficulties only, easiest first
+	Difficulties []DxStarDifficulty `json:"difficulties"`
+	Below        *int32             `json:"below"`
+	// empty unless below is given
+	BelowCharts []LevelTableChart `json:"belowCharts"`
+}
+
+type ErrorBody struct {
+	// machine-readable, clients should branch on this instead of the message, one of BAD_REQUEST, VALIDATION_FAILED, UNAUTHORIZED, INVALID_CREDENTIALS, INVALID_SEGA_CREDENTIALS, FORBIDDEN, NOT_FOUND, CONFLICT, USER_EXISTS, RATE_LIMITED, INTERNAL_ERROR, UPSTREAM_ERROR, UPSTREAM_MAINTENANCE
+	Code string `json:"code"`
+	// human readable, may change
+	Message string `json:"message"`
+	// invalid fields, only set for VALIDATION_FAILED
+	Details []FieldError `json:"details,omitempty"`
+}
+
+type FastLate struct {
+	Fast int64 `json:"fast"`
+	Late int64 `json:"late"`
+	// (late - fast) / (fast + late): -1 all fast, 1 all late, null without either
+	Bias *float64 `json:"bias"`
+}
+
+type FastLateWeek struct {
+	// Monday the JST week starts on
+	Week  string `json:"week"`
+	Plays int64  `json:"plays"`
+	Fast  int64  `json:"fast"`
+	Late  int64  `json:"late"`
+	// (late - fast) / (fast + late): -1 all fast, 1 all late, null without either
+	Bias *float64 `json:"bias"`
+}
+
+type FeedItem struct {
+	ID string `json:"id"`
+	// one of personal_best, rank, lamp, rating_milestone
+	Kind        string  `json:"kind"`
+	UserID      string  `json:"userID"`
+	DisplayName string  `json:"displayName"`
+	ScoreID     *string `json:"scoreID"`
+	BeatmapID   *string `json:"beatmapID"`
+	SongID      *string `json:"songID"`
+	Title       *string `json:"title"`
+	ImageUrl    *string `json:"imageUrl"`
+	Difficulty  *string `json:"difficulty"`
+	Level       *string `json:"level"`
+	Type        *string `json:"type"`
+	Accuracy    *string `json:"accuracy"`
+	// best before the play, null on a first play
+	PreviousAccuracy *string `json:"previousAccuracy"`
+	// set for rank items
+	Rank *string `json:"rank"`
+	// set for lamp items, one of FC, FC+, AP, AP+
+	Lamp *string `json:"lamp"`
+	// the milestone reached, set for rating milestones
+	Rating    *int32    `json:"rating"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type FeedPage struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor *string    `json:"nextCursor,omitempty"`
+	HasMore    bool       `json:"hasMore"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Follow struct {
+	ID          string `json:"id"`
+	UserID      string `json:"userID"`
+	DisplayName string `json:"displayName"`
+	// whether they follow each other, which makes them friends
+	Mutual     bool      `json:"mutual"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+type FollowRequest struct {
+	UserID string `json:"userID"`
+}
+
+type Goal struct {
+	ID string `json:"id"`
+	// one of rating, chart, chart_set, count
+	Kind         string   `json:"kind"`
+	Title        string   `json:"title"`
+	TargetRating *int32   `json:"targetRating"`
+	BeatmapID    *string  `json:"beatmapID"`
+	TargetRank   *string  `json:"targetRank"`
+	TargetLamp   *string  `json:"targetLamp"`
+	Difficulty   *string  `json:"difficulty"`
+	Type         *string  `json:"type"`
+	Level        *string  `json:"level"`
+	LevelMin     *float64 `json:"levelMin"`
+	LevelMax     *float64 `json:"levelMax"`
+	TargetCount  *int32   `json:"targetCount"`
+	// the rating for rating goals, 0 or 1 for chart goals and the charts reaching the target otherwise
+	Progress int32 `json:"progress"`
+	// progress needed to complete the goal
+	Target    int32 `json:"target"`
+	Completed bool  `json:"completed"`
+	// kept once set, even if progress drops again
+	CompletedAt *time.Time `json:"completedAt"`
+	EvaluatedAt *time.Time `json:"evaluatedAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// rating goals take targetRating. chart goals take beatmapID and targetRank and/or targetLamp. chart_set goals take targetRank and/or targetLamp and optional chart filters, and are reached on every chart matching them. count goals are chart_set goals reached on targetCount charts. kind is required on create.
+type GoalRequest struct {
+	// one of rating, chart, chart_set, count
+	Kind         *string `json:"kind,omitempty"`
+	Title        *string `json:"title,omitempty"`
+	TargetRating *int32  `json:"targetRating,omitempty"`
+	BeatmapID    *string `json:"beatmapID,omitempty"`
+	// one of SSS+, SSS, SS+, SS, S+, S, AAA, AA, A, BBB, BB, B, C
+	TargetRank *string `json:"targetRank,omitempty"`
+	// one of FC, FC+, AP, AP+
+	TargetLamp *string `json:"targetLamp,omitempty"`
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty *string `json:"difficulty,omitempty"`
+	// one of dx, std
+	Type *string `json:"type,omitempty"`
+	// level shown in game, one of 1, 2, 3, 4, 5, 6, 7, 7+, 8, 8+, 9, 9+, 10, 10+, 11, 11+, 12, 12+, 13, 13+, 14, 14+, 15
+	Level *string `json:"level,omitempty"`
+	// internal level, inclusive
+	LevelMin *float64 `json:"levelMin,omitempty"`
+	// internal level, inclusive
+	LevelMax    *float64 `json:"levelMax,omitempty"`
+	TargetCount *int32   `json:"targetCount,omitempty"`
+}
+
+type HeadToHead struct {
+	UserID       string            `json:"userID"`
+	RivalID      string            `json:"rivalID"`
+	Summary      HeadToHeadCounts  `json:"summary"`
+	ByDifficulty []HeadToHeadGroup `json:"byDifficulty"`
+	// hardest level first
+	ByLevel []HeadToHeadGroup `json:"byLevel"`
+	// hardest first
+	Charts []ChartComparison `json:"charts"`
+}
+
+// wins and losses are from the user's side
+type HeadToHeadCounts struct {
+	Wins   int32 `json:"wins"`
+	Losses int32 `json:"losses"`
+	Ties   int32 `json:"ties"`
+	// charts only the user has played
+	OnlyUser int32 `json:"onlyUser"`
+	// charts only the rival has played
+	OnlyRival int32 `json:"onlyRival"`
+}
+
+type HeadToHeadGroup struct {
+	// difficulty or level
+	Group  string `json:"group"`
+	Wins   int32  `json:"wins"`
+	Losses int32  `json:"losses"`
+	Ties   int32  `json:"ties"`
+	// charts only the user has played
+	OnlyUser int32 `json:"onlyUser"`
+	// charts only the rival has played
+	OnlyRival int32 `json:"onlyRival"`
+}
+
+type JudgementRates struct {
+	Tap   NoteTypeRates `json:"tap"`
+	Hold  NoteTypeRates `json:"hold"`
+	Slide NoteTypeRates `json:"slide"`
+	Touch NoteTypeRates `json:"touch"`
+	Break NoteTypeRates `json:"break"`
+}
+
+type LevelTable struct {
+	Level    *string  `json:"level"`
+	LevelMin *float64 `json:"levelMin"`
+	LevelMax *float64 `json:"levelMax"`
+	Charts   int32    `json:"charts"`
+	Played   int32    `json:"played"`
+	// best rank first
+	Ranks []LevelTableCount `json:"ranks"`
+	// AP+ first
+	Lamps []LevelTableCount `json:"lamps"`
+	// 5 stars first
+	DxStars []LevelTableCount `json:"dxStars"`
+	Tiers   []LevelTier       `json:"tiers"`
+}
+
+type LevelTableChart struct {
+	BeatmapID     string   `json:"beatmapID"`
+	SongID        string   `json:"songID"`
+	Title         string   `json:"title"`
+	ImageUrl      string   `json:"imageUrl"`
+	Difficulty    string   `json:"difficulty"`
+	Level         string   `json:"level"`
+	InternalLevel *float64 `json:"internalLevel"`
+	Type          string   `json:"type"`
+	// 0 when the notes are not known
+	MaxDxScore int32 `json:"maxDxScore"`
+	// best achievement, null when unplayed
+	Accuracy    *string  `json:"accuracy"`
+	Achievement *float64 `json:"achievement"`
+	Rank        *string  `json:"rank"`
+	// one of FC, FC+, AP, AP+
+	Lamp *string `json:"lamp"`
+	// best DX score
+	DxScore *int32 `json:"dxScore"`
+	// stars of the best DX score, null when unplayed or the max DX score is not known
+	DxStars *int32 `json:"dxStars"`
+}
+
+// played charts whose best is exactly this rank, lamp or number of stars
+type LevelTableCount struct {
+	Name  string `json:"name"`
+	Count int32  `json:"count"`
+}
+
+type LevelTier struct {
+	// null for charts without a known internal level
+	InternalLevel *float64          `json:"internalLevel"`
+	Charts        []LevelTableChart `json:"charts"`
+}
+
+type LoginRequest struct {
+	UserID   string `json:"userID"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	User Me `json:"user"`
+}
+
+type Me struct {
+	UserID      string `json:"userID"`
+	DisplayName string `json:"displayName"`
+}
+
+type Message struct {
+	Message string `json:"message"`
+}
+
+type NoteCounts struct {
+	Tap   int32 `json:"tap"`
+	Hold  int32 `json:"hold"`
+	Slide int32 `json:"slide"`
+	Touch int32 `json:"touch"`
+	Break int32 `json:"break"`
+}
+
+// judgements of one note type, rates are null without notes
+type NoteTypeRates struct {
+	Notes        int64    `json:"notes"`
+	Critical     int64    `json:"critical"`
+	Perfect      int64    `json:"perfect"`
+	Great        int64    `json:"great"`
+	Good         int64    `json:"good"`
+	Miss         int64    `json:"miss"`
+	CriticalRate *float64 `json:"criticalRate"`
+	GreatRate    *float64 `json:"greatRate"`
+	GoodRate     *float64 `json:"goodRate"`
+	MissRate     *float64 `json:"missRate"`
+}
+
+type PersonalBest struct {
+	ScoreID  string `json:"scoreID"`
+	Accuracy string `json:"accuracy"`
+	DxScore  int32  `json:"dxScore"`
+	// null when the max DX score of the beatmap is not known
+	DxStars  *int32    `json:"dxStars"`
+	PlayedAt time.Time `json:"playedAt"`
+}
+
+type PlateChart struct {
+	BeatmapID     string   `json:"beatmapID"`
+	SongID        string   `json:"songID"`
+	Title         string   `json:"title"`
+	ImageUrl      string   `json:"imageUrl"`
+	Difficulty    string   `json:"difficulty"`
+	Level         string   `json:"level"`
+	InternalLevel *float64 `json:"internalLevel"`
+	Type          string   `json:"type"`
+	// the user's best, null when unplayed
+	Accuracy *string `json:"accuracy"`
+	// the user's best, null without one, one of FC, FC+, AP, AP+
+	Lamp *string `json:"lamp"`
+}
+
+type PlateDifficulty struct {
+	Difficulty string `json:"difficulty"`
+	Cleared    int32  `json:"cleared"`
+	Total      int32  `json:"total"`
+}
+
+type PlateProgress struct {
+	// 極, 将, 神 or 舞舞
+	Name string `json:"name"`
+	// FC, SSS, AP or FDX on every chart
+	Requirement string `json:"requirement"`
+	// false when the progress cannot be told
+	Tracked   bool   `json:"tracked"`
+	Cleared   *int32 `json:"cleared"`
+	Remaining *int32 `json:"remaining"`
+	Complete  bool   `json:"complete"`
+	// empty when not tracked
+	ByDifficulty []PlateDifficulty `json:"byDifficulty"`
+	// empty when not tracked
+	RemainingCharts []PlateChart `json:"remainingCharts"`
+}
+
+type PlateSummary struct {
+	// 極, 将, 神 or 舞舞
+	Name string `json:"name"`
+	// FC, SSS, AP or FDX on every chart
+	Requirement string `json:"requirement"`
+	// false when the progress cannot be told
+	Tracked   bool   `json:"tracked"`
+	Cleared   *int32 `json:"cleared"`
+	Remaining *int32 `json:"remaining"`
+	Complete  bool   `json:"complete"`
+}
+
+type PlayedBeatmap struct {
+	BeatmapID  string `json:"beatmapID"`
+	SongID     string `json:"songID"`
+	Title      string `json:"title"`
+	ImageUrl   string `json:"imageUrl"`
+	Difficulty string `json:"difficulty"`
+	Level      string `json:"level"`
+	Type       string `json:"type"`
+	Plays      int64  `json:"plays"`
+}
+
+type Privacy struct {
+	// one of public, friends, private
+	ProfileVisibility string `json:"profileVisibility"`
+	// one of public, friends, private
+	ScoresVisibility string `json:"scoresVisibility"`
+	// one of public, friends, private
+	RatingHistoryVisibility string `json:"ratingHistoryVisibility"`
+}
+
+// omitted fields are left unchanged
+type PrivacyUpdate struct {
+	// one of public, friends, private
+	ProfileVisibility *string `json:"profileVisibility,omitempty"`
+	// one of public, friends, private
+	ScoresVisibility *string `json:"scoresVisibility,omitempty"`
+	// one of public, friends, private
+	RatingHistoryVisibility *string `json:"ratingHistoryVisibility,omitempty"`
+}
+
+type Profile struct {
+	UserID          string  `json:"userID"`
+	DisplayName     string  `json:"displayName"`
+	Bio             *string `json:"bio"`
+	Location        *string `json:"location"`
+	TwitterID       *string `json:"twitterID"`
+	ProfileImageUrl *string `json:"profileImageUrl"`
+	Rating          int32   `json:"rating"`
+	SeasonPlayCount int32   `json:"seasonPlayCount"`
+	TotalPlayCount  int32   `json:"totalPlayCount"`
+}
+
+// omitted fields are left unchanged, empty strings clear them
+type ProfileUpdate struct {
+	Bio       *string `json:"bio,omitempty"`
+	Location  *string `json:"location,omitempty"`
+	TwitterID *string `json:"twitterID,omitempty"`
+}
+
+// how many more of each judgement the play can take on its own and keep the rank
+type RankBudget struct {
+	// one of SSS+, SSS, SS+, SS, S+, S, AAA, AA, A, BBB, BB, B, C, D
+	Rank string `json:"rank"`
+	// lowest achievement of the rank
+	Achievement float64    `json:"achievement"`
+	Great       NoteCounts `json:"great"`
+	Good        NoteCounts `json:"good"`
+	Miss        NoteCounts `json:"miss"`
+	// PERFECT breaks of the lowest grade
+	BreakPerfect int32 `json:"breakPerfect"`
+}
+
+type RatingHistory struct {
+	// one of snapshot, day, week
+	Bucket string `json:"bucket"`
+	// oldest first
+	Points []RatingHistoryPoint `json:"points"`
+}
+
+// deltas are against the previous point, null for the first snapshot of the user
+type RatingHistoryPoint struct {
+	// JST day of the snapshot, or the day or Monday of the week the bucket starts
+	Date string `json:"date"`
+	// when the snapshot was taken, the last one of the bucket
+	RecordedAt           time.Time `json:"recordedAt"`
+	Rating               int32     `json:"rating"`
+	SeasonPlayCount      int32     `json:"seasonPlayCount"`
+	TotalPlayCount       int32     `json:"totalPlayCount"`
+	RatingDelta          *int32    `json:"ratingDelta"`
+	SeasonPlayCountDelta *int32    `json:"seasonPlayCountDelta"`
+	TotalPlayCountDelta  *int32    `json:"totalPlayCountDelta"`
+}
+
+// me is the signed in user's entry wherever it is on the leaderboard, omitted when signed out or not on it
+type RatingLeaderboard struct {
+	Entries    []RatingLeaderboardEntry `json:"entries"`
+	Me         *RatingLeaderboardEntry  `json:"me,omitempty"`
+	NextCursor *string                  `json:"nextCursor,omitempty"`
+	HasMore    bool                     `json:"hasMore"`
+}
+
+type RatingLeaderboardEntry struct {
+	// users with the same rating share a rank
+	Rank        int64  `json:"rank"`
+	UserID      string `json:"userID"`
+	DisplayName string `json:"displayName"`
+	Rating      int32  `json:"rating"`
+	// the entry of the signed in user
+	IsMe bool `json:"isMe"`
+}
+
+type Recommendation struct {
+	BeatmapID string `json:"beatmapID"`
+	SongID    string `json:"songID"`
+	Title     string `json:"title"`
+	ImageUrl  string `json:"imageUrl"`
+	// one of basic, advanced, expert, master, remaster
+	Difficulty    string  `json:"difficulty"`
+	Level         string  `json:"level"`
+	InternalLevel float64 `json:"internalLevel"`
+	// one of dx, std
+	Type string `json:"type"`
+	// counts towards the new songs of the rating frame
+	IsNew bool `json:"isNew"`
+	// achievement of the personal best, e.g. "99.1234%"
+	Accuracy string `json:"accuracy"`
+	// one of SSS+, SSS, SS+, SS, S+, S, AAA, AA, A, BBB, BB, B, C, D
+	Rank string `json:"rank"`
+	// rating of the personal best
+	Rating int32 `json:"rating"`
+	// one of SSS+, SSS, SS+, SS, S+, S, AAA, AA, A, BBB, BB, B, C, D
+	TargetRank string `json:"targetRank"`
+	// lowest achievement of targetRank
+	RequiredAchievement float64 `json:"requiredAchievement"`
+	// rating at requiredAchievement
+	TargetRating int32 `json:"targetRating"`
+	// how much the rating frame would rise
+	Gain int32 `json:"gain"`
+	// share of recent plays within 0.3 internal levels that reached requiredAchievement, null without any
+	ReachRate *float64 `json:"reachRate"`
+	// recent plays reachRate is taken from
+	Samples int32 `json:"samples"`
+}
+
+type Recommendations struct {
+	// total of the current rating frame, best 35 old and 15 new charts
+	Rating          int32            `json:"rating"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+type RegisterRequest struct {
+	UserID      string  `json:"userID"`
+	DisplayName *string `json:"displayName,omitempty"`
+	// must contain at least one letter and one number
+	Password     string `json:"password"`
+	SegaID       string `json:"segaID"`
+	SegaPassword string `json:"segaPassword"`
+}
+
+type RegisterResponse struct {
+	ID          string `json:"id"`
+	UserID      string `json:"userID"`
+	DisplayName string `json:"displayName"`
+}
+
+type Rival struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userID"`
+	DisplayName string    `json:"displayName"`
+	Rating      *int32    `json:"rating"`
+	AddedAt     time.Time `json:"addedAt"`
+}
+
+// beatmap and song fields are only included by the endpoints that join them
+type Score struct {
+	ID            string     `json:"id"`
+	BeatmapID     string     `json:"beatmapID"`
+	SongID        string     `json:"songID"`
+	Accuracy      string     `json:"accuracy"`
+	MaxCombo      int32      `json:"maxCombo"`
+	DxScore       int32      `json:"dxScore"`
+	TapCritical   int32      `json:"tapCritical"`
+	TapPerfect    int32      `json:"tapPerfect"`
+	TapGreat      int32      `json:"tapGreat"`
+	TapGood       int32      `json:"tapGood"`
+	TapMiss       int32      `json:"tapMiss"`
+	HoldCritical  int32      `json:"holdCritical"`
+	HoldPerfect   int32      `json:"holdPerfect"`
+	HoldGreat     int32      `json:"holdGreat"`
+	HoldGood      int32      `json:"holdGood"`
+	HoldMiss      int32      `json:"holdMiss"`
+	SlideCritical int32      `json:"slideCritical"`
+	SlidePerfect  int32      `json:"slidePerfect"`
+	SlideGreat    int32      `json:"slideGreat"`
+	SlideGood     int32      `json:"slideGood"`
+	SlideMiss     int32      `json:"slideMiss"`
+	TouchCritical int32      `json:"touchCritical"`
+	TouchPerfect  int32      `json:"touchPerfect"`
+	TouchGreat    int32      `json:"touchGreat"`
+	TouchGood     int32      `json:"touchGood"`
+	TouchMiss     int32      `json:"touchMiss"`
+	BreakCritical int32      `json:"breakCritical"`
+	BreakPerfect  int32      `json:"breakPerfect"`
+	BreakGreat    int32      `json:"breakGreat"`
+	BreakGood     int32      `json:"breakGood"`
+	BreakMiss     int32      `json:"breakMiss"`
+	Fast          int32      `json:"fast"`
+	Late          int32      `json:"late"`
+	PlayedAt      *time.Time `json:"playedAt"`
+	// why the scraper thinks the judgements do not add up to the record page, omitted when they do
+	FlagReason *string `json:"flagReason,omitempty"`
+	// track of the credit, TRACK 01 starts one. Null when not known
+	Track *int32 `json:"track"`
+	// session the play belongs to, null for scores not added by the scraper
+	SessionID     *string  `json:"sessionID"`
+	Title         *string  `json:"title,omitempty"`
+	Artist        *string  `json:"artist,omitempty"`
+	Genre         *string  `json:"genre,omitempty"`
+	ImageUrl      *string  `json:"imageUrl,omitempty"`
+	Version       *string  `json:"version,omitempty"`
+	Difficulty    *string  `json:"difficulty,omitempty"`
+	Level         *string  `json:"level,omitempty"`
+	InternalLevel *float64 `json:"internalLevel,omitempty"`
+	Type          *string  `json:"type,omitempty"`
+	// max DX score of the beatmap, included with the beatmap fields
+	MaxDxScore *int32 `json:"maxDxScore,omitempty"`
+	// DX stars the score earns, left out when the max DX score of the beatmap is not known
+	DxStars *int32 `json:"dxStars,omitempty"`
+}
+
+type ScorePage struct {
+	Scores     []Score `json:"scores"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+	HasMore    bool    `json:"hasMore"`
+}
+
+// plays of a user less than 30 minutes apart
+type Session struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	// from the first to the last play
+	DurationSeconds int64 `json:"durationSeconds"`
+	Plays           int32 `json:"plays"`
+	// plays on TRACK 01
+	Credits int32 `json:"credits"`
+	// distinct beatmaps played
+	Charts int32 `json:"charts"`
+	// plays better than every earlier play of the beatmap, first plays included
+	PersonalBests int32 `json:"personalBests"`
+	// rating of the last snapshot before the session, null when the rating history of the user is hidden from the viewer
+	RatingBefore *int32 `json:"ratingBefore"`
+	// rating of the first snapshot after the session, null when the rating history of the user is hidden from the viewer
+	RatingAfter  *int32 `json:"ratingAfter"`
+	RatingChange *int32 `json:"ratingChange"`
+}
+
+type SessionDetail struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	// from the first to the last play
+	DurationSeconds int64 `json:"durationSeconds"`
+	Plays           int32 `json:"plays"`
+	// plays on TRACK 01
+	Credits int32 `json:"credits"`
+	// distinct beatmaps played
+	Charts int32 `json:"charts"`
+	// plays better than every earlier play of the beatmap, first plays included
+	PersonalBests int32 `json:"personalBests"`
+	// rating of the last snapshot before the session
+	RatingBefore *int32 `json:"ratingBefore"`
+	// rating of the first snapshot after the session
+	RatingAfter  *int32 `json:"ratingAfter"`
+	RatingChange *int32 `json:"ratingChange"`
+	// oldest first
+	Scores []Score `json:"scores"`
+}
+
+type SessionPage struct {
+	Sessions   []Session `json:"sessions"`
+	NextCursor *string   `json:"nextCursor,omitempty"`
+	HasMore    bool      `json:"hasMore"`
+}
+
+type SimulateRequest struct {
+	Tap   *SimulatedJudgement `json:"tap,omitempty"`
+	Hold  *SimulatedJudgement `json:"hold,omitempty"`
+	Slide *SimulatedJudgement `json:"slide,omitempty"`
+	Touch *SimulatedJudgement `json:"touch,omitempty"`
+	Break *SimulatedJudgement `json:"break,omitempty"`
+	// rank to budget for, every rank from S up when omitted, one of SSS+, SSS, SS+, SS, S+, S, AAA, AA, A, BBB, BB, B, C, D
+	Target *string `json:"target,omitempty"`
+}
+
+// judgements of one note type, notes not counted here are CRITICAL PERFECT
+type SimulatedJudgement struct {
+	Perfect *int32 `json:"perfect,omitempty"`
+	Great   *int32 `json:"great,omitempty"`
+	Good    *int32 `json:"good,omitempty"`
+	Miss    *int32 `json:"miss,omitempty"`
+}
+
+// achievement is a range because break grades are not shown on maimai DX NET, rank is earned by achievementMin
+type Simulation struct {
+	BeatmapID      string  `json:"beatmapID"`
+	AchievementMin float64 `json:"achievementMin"`
+	AchievementMax float64 `json:"achievementMax"`
+	// one of SSS+, SSS, SS+, SS, S+, S, AAA, AA, A, BBB, BB, B, C, D
+	Rank       string       `json:"rank"`
+	DxScore    int32        `json:"dxScore"`
+	MaxDxScore int32        `json:"maxDxScore"`
+	Budgets    []RankBudget `json:"budgets"`
+}
+
+// beatmaps are only included by the endpoints that list them
+type Song struct {
+	ID           string        `json:"id"`
+	Title        string        `json:"title"`
+	TitleKana    string        `json:"titleKana"`
+	TitleRomaji  string        `json:"titleRomaji"`
+	TitleEnglish *string       `json:"titleEnglish"`
+	Artist       string        `json:"artist"`
+	ArtistRomaji string        `json:"artistRomaji"`
+	Genre        string        `json:"genre"`
+	Bpm          string        `json:"bpm"`
+	ImageUrl     string        `json:"imageUrl"`
+	Version      string        `json:"version"`
+	IsUtage      bool          `json:"isUtage"`
+	IsAvailable  bool          `json:"isAvailable"`
+	IsNew        bool          `json:"isNew"`
+	ReleaseDate  *string       `json:"releaseDate"`
+	DeleteDate   *string       `json:"deleteDate"`
+	Beatmaps     []SongBeatmap `json:"beatmaps,omitempty"`
+}
+
+type SongAlias struct {
+	ID        string     `json:"id"`
+	SongID    string     `json:"songID"`
+	Alias     string     `json:"alias"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+type SongBeatmap struct {
+	BeatmapID string `json:"beatmapID"`
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty    string   `json:"difficulty"`
+	Level         string   `json:"level"`
+	InternalLevel *float64 `json:"internalLevel"`
+	// one of dx, std, utage
+	Type         string `json:"type"`
+	TotalNotes   int32  `json:"totalNotes"`
+	Tap          int32  `json:"tap"`
+	Hold         int32  `json:"hold"`
+	Slide        int32  `json:"slide"`
+	Touch        int32  `json:"touch"`
+	Break        int32  `json:"break"`
+	NoteDesigner string `json:"noteDesigner"`
+	MaxDxScore   int32  `json:"maxDxScore"`
+}
+
+type SongSearchResult struct {
+	Song
+	Score float64 `json:"score"`
+	// one of title, titleKana, titleRomaji, titleEnglish, artist, artistRomaji, alias, fullText
+	MatchedBy string `json:"matchedBy"`
+}
+
+// consecutive JST days with plays, both inclusive
+type Streak struct {
+	Days  int64  `json:"days"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type TitleEnglishRequest struct {
+	// an empty string clears the override
+	TitleEnglish string `json:"titleEnglish"`
+}
+
+// omitted fields are left unchanged
+type UpdateBeatmapRequest struct {
+	BeatmapID string  `json:"beatmapID"`
+	SongID    *string `json:"songID,omitempty"`
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty    *string  `json:"difficulty,omitempty"`
+	Level         *string  `json:"level,omitempty"`
+	InternalLevel *float64 `json:"internalLevel,omitempty"`
+	// one of dx, std, utage
+	Type         *string `json:"type,omitempty"`
+	TotalNotes   *int32  `json:"totalNotes,omitempty"`
+	Tap          *int32  `json:"tap,omitempty"`
+	Hold         *int32  `json:"hold,omitempty"`
+	Slide        *int32  `json:"slide,omitempty"`
+	Touch        *int32  `json:"touch,omitempty"`
+	Break        *int32  `json:"break,omitempty"`
+	NoteDesigner *string `json:"noteDesigner,omitempty"`
+	MaxDxScore   *int32  `json:"maxDxScore,omitempty"`
+	PlayCount    *int32  `json:"playCount,omitempty"`
+}
+
+// omitted fields are left unchanged
+type UpdateSongRequest struct {
+	SongID    string  `json:"songID"`
+	Title     *string `json:"title,omitempty"`
+	TitleKana *string `json:"titleKana,omitempty"`
+	Artist    *string `json:"artist,omitempty"`
+	// reading of the artist, only used to romanize artists with kanji
+	ArtistKana  *string `json:"artistKana,omitempty"`
+	Genre       *string `json:"genre,omitempty"`
+	Bpm         *string `json:"bpm,omitempty"`
+	ImageUrl    *string `json:"imageUrl,omitempty"`
+	Version     *string `json:"version,omitempty"`
+	IsUtage     *bool   `json:"isUtage,omitempty"`
+	IsAvailable *bool   `json:"isAvailable,omitempty"`
+	// JST time formatted as 2006-01-02 15:04
+	ReleaseDate *string `json:"releaseDate,omitempty"`
+	// JST time formatted as 2006-01-02 15:04
+	DeleteDate *string `json:"deleteDate,omitempty"`
+}
+
+type User struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"userID"`
+	DisplayName     string     `json:"displayName"`
+	LastPlayedAt    *time.Time `json:"lastPlayedAt"`
+	LastScrapedAt   *time.Time `json:"lastScrapedAt"`
+	ScrapeStatus    *string    `json:"scrapeStatus"`
+	Rating          int32      `json:"rating"`
+	SeasonPlayCount int32      `json:"seasonPlayCount"`
+	TotalPlayCount  int32      `json:"totalPlayCount"`
+	Bio             *string    `json:"bio"`
+	ProfileImageUrl *string    `json:"profileImageUrl"`
+	Location        *string    `json:"location"`
+	TwitterID       *string    `json:"twitterID"`
+}
+
+type UserSummary struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"userID"`
+	DisplayName  string     `json:"displayName"`
+	LastPlayedAt *time.Time `json:"lastPlayedAt"`
+	Rating       *int32     `json:"rating"`
+}
+
+type VersionPlateProgress struct {
+	Version string          `json:"version"`
+	Charts  int32           `json:"charts"`
+	Plates  []PlateProgress `json:"plates"`
+}
+
+type VersionPlates struct {
+	Version string         `json:"version"`
+	Charts  int32          `json:"charts"`
+	Plates  []PlateSummary `json:"plates"`
+}
+
+type VersionPlays struct {
+	Version string `json:"version"`
+	Plays   int64  `json:"plays"`
+}
+
+// a beatmap played at least twice, rates are per note
+type WeakSpot struct {
+	BeatmapID          string   `json:"beatmapID"`
+	SongID             string   `json:"songID"`
+	Title              string   `json:"title"`
+	ImageUrl           string   `json:"imageUrl"`
+	Difficulty         string   `json:"difficulty"`
+	Level              string   `json:"level"`
+	InternalLevel      *float64 `json:"internalLevel"`
+	Type               string   `json:"type"`
+	Plays              int64    `json:"plays"`
+	AverageAchievement float64  `json:"averageAchievement"`
+	// notes of every play summed up
+	Notes     int64    `json:"notes"`
+	GreatRate *float64 `json:"greatRate"`
+	GoodRate  *float64 `json:"goodRate"`
+	MissRate  *float64 `json:"missRate"`
+	Fast      int64    `json:"fast"`
+	Late      int64    `json:"late"`
+	// (late - fast) / (fast + late): -1 all fast, 1 all late, null without either
+	Bias *float64 `json:"bias"`
+}
+
+// AddMyRival: Add a rival
+//
+//	POST /me/rivals
+func (c *Client) AddMyRival(ctx context.Context, body AddRivalRequest) error {
+	_, err := c.do(ctx, "POST", "/me/rivals", nil, body, nil)
+	return err
+}
+
+// CompareUsers: Personal bests of two users side by side
+//
+//	GET /users/by-user-id/{userID}/compare/{rivalID}
+func (c *Client) CompareUsers(ctx context.Context, userID string, rivalID string) (*HeadToHead, error) {
+	var out HeadToHead
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/compare/"+url.PathEscape(rivalID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBeatmap: Create a beatmap
+//
+//	POST /beatmaps
+func (c *Client) CreateBeatmap(ctx context.Context, body CreateBeatmapRequest) (*Beatmap, error) {
+	var out Beatmap
+	if _, err := c.do(ctx, "POST", "/beatmaps", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateMyGoal: Create a goal
+//
+//	POST /me/goals
+func (c *Client) CreateMyGoal(ctx context.Context, body GoalRequest) (*Goal, error) {
+	var out Goal
+	if _, err := c.do(ctx, "POST", "/me/goals", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateScore: Record a score
+//
+//	POST /scores
+func (c *Client) CreateScore(ctx context.Context, body CreateScoreRequest) (*Score, error) {
+	var out Score
+	if _, err := c.do(ctx, "POST", "/scores", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSong: Create a song
+//
+//	POST /songs
+func (c *Client) CreateSong(ctx context.Context, body CreateSongRequest) (*Song, error) {
+	var out Song
+	if _, err := c.do(ctx, "POST", "/songs", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSongAlias: Add an alias to a song (curators only)
+//
+//	POST /songs/by-id/{id}/aliases
+func (c *Client) CreateSongAlias(ctx context.Context, id string, body AliasRequest) (*SongAlias, error) {
+	var out SongAlias
+	if _, err := c.do(ctx, "POST", "/songs/by-id/"+url.PathEscape(id)+"/aliases", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMyGoal: Delete a goal
+//
+//	DELETE /me/goals/{goalID}
+func (c *Client) DeleteMyGoal(ctx context.Context, goalID string) error {
+	_, err := c.do(ctx, "DELETE", "/me/goals/"+url.PathEscape(goalID), nil, nil, nil)
+	return err
+}
+
+// DeleteSongAlias: Remove an alias (curators only)
+//
+//	DELETE /aliases/{aliasID}
+func (c *Client) DeleteSongAlias(ctx context.Context, aliasID string) error {
+	_, err := c.do(ctx, "DELETE", "/aliases/"+url.PathEscape(aliasID), nil, nil, nil)
+	return err
+}
+
+// ErrorCheck: Always fails, for checking error handling
+//
+//	GET /err
+func (c *Client) ErrorCheck(ctx context.Context) error {
+	_, err := c.do(ctx, "GET", "/err", nil, nil, nil)
+	return err
+}
+
+// FollowUser: Follow a user
+//
+//	POST /me/follows
+func (c *Client) FollowUser(ctx context.Context, body FollowRequest) error {
+	_, err := c.do(ctx, "POST", "/me/follows", nil, body, nil)
+	return err
+}
+
+// GetActivityParams are the query parameters of GetActivity, nil fields are not sent
+type GetActivityParams struct {
+	// calendar year to cover, the last 365 days when omitted
+	Year *int32
+}
+
+// GetActivity: Play calendar and streaks of a user
+//
+//	GET /users/by-user-id/{userID}/activity
+func (c *Client) GetActivity(ctx context.Context, userID string, params *GetActivityParams) (*Activity, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Year != nil {
+			query.Set("year", fmt.Sprint(*params.Year))
+		}
+	}
+	var out Activity
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/activity", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAnalyticsParams are the query parameters of GetAnalytics, nil fields are not sent
+type GetAnalyticsParams struct {
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty *string
+	// one of dx, std, utage
+	Type *string
+	// internal level
+	LevelMin *float64
+	// internal level
+	LevelMax *float64
+	// JST date, inclusive
+	PlayedFrom *string
+	// JST date, inclusive
+	PlayedTo *string
+	// number of weak spots, default 10
+	Limit *int32
+}
+
+// GetAnalytics: Judgement rates and fast/late of a user's plays
+//
+//	GET /users/by-user-id/{userID}/analytics
+func (c *Client) GetAnalytics(ctx context.Context, userID string, params *GetAnalyticsParams) (*Analytics, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Difficulty != nil {
+			query.Set("difficulty", fmt.Sprint(*params.Difficulty))
+		}
+		if params.Type != nil {
+			query.Set("type", fmt.Sprint(*params.Type))
+		}
+		if params.LevelMin != nil {
+			query.Set("levelMin", fmt.Sprint(*params.LevelMin))
+		}
+		if params.LevelMax != nil {
+			query.Set("levelMax", fmt.Sprint(*params.LevelMax))
+		}
+		if params.PlayedFrom != nil {
+			query.Set("playedFrom", fmt.Sprint(*params.PlayedFrom))
+		}
+		if params.PlayedTo != nil {
+			query.Set("playedTo", fmt.Sprint(*params.PlayedTo))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out Analytics
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/analytics", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBeatmapLeaderboardParams are the query parameters of GetBeatmapLeaderboard, nil fields are not sent
+type GetBeatmapLeaderboardParams struct {
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// GetBeatmapLeaderboard: Best scores of every user on a beatmap
+//
+//	GET /beatmaps/{id}/leaderboard
+func (c *Client) GetBeatmapLeaderboard(ctx context.Context, id string, params *GetBeatmapLeaderboardParams) (*BeatmapLeaderboard, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out BeatmapLeaderboard
+	if _, err := c.do(ctx, "GET", "/beatmaps/"+url.PathEscape(id)+"/leaderboard", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBeatmapsBySong: Beatmaps of a song
+//
+//	GET /beatmaps/by-song-id/{songID}
+func (c *Client) GetBeatmapsBySong(ctx context.Context, songID string) ([]Beatmap, error) {
+	var out []Beatmap
+	if _, err := c.do(ctx, "GET", "/beatmaps/by-song-id/"+url.PathEscape(songID), nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetDxStarsParams are the query parameters of GetDxStars, nil fields are not sent
+type GetDxStarsParams struct {
+	// level shown in game, + encoded as %2B, one of 1, 2, 3, 4, 5, 6, 7, 7+, 8, 8+, 9, 9+, 10, 10+, 11, 11+, 12, 12+, 13, 13+, 14, 14+, 15
+	Level *string
+	// one of basic, advanced, expert, master, remaster
+	Difficulty *string
+	// one of dx, std, utage
+	Type *string
+	// internal level
+	LevelMin *float64
+	// internal level
+	LevelMax *float64
+	// list the played charts with fewer DX stars than this. Charts whose max DX score is not known are left out, one of 1, 2, 3, 4, 5
+	Below *string
+}
+
+// GetDxStars: DX star distribution of a user
+//
+//	GET /users/by-user-id/{userID}/dx-stars
+func (c *Client) GetDxStars(ctx context.Context, userID string, params *GetDxStarsParams) (*DxStarStats, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Level != nil {
+			query.Set("level", fmt.Sprint(*params.Level))
+		}
+		if params.Difficulty != nil {
+			query.Set("difficulty", fmt.Sprint(*params.Difficulty))
+		}
+		if params.Type != nil {
+			query.Set("type", fmt.Sprint(*params.Type))
+		}
+		if params.LevelMin != nil {
+			query.Set("levelMin", fmt.Sprint(*params.LevelMin))
+		}
+		if params.LevelMax != nil {
+			query.Set("levelMax", fmt.Sprint(*params.LevelMax))
+		}
+		if params.Below != nil {
+			query.Set("below", fmt.Sprint(*params.Below))
+		}
+	}
+	var out DxStarStats
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/dx-stars", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLevelTableParams are the query parameters of GetLevelTable, nil fields are not sent
+type GetLevelTableParams struct {
+	// level shown in game, + encoded as %2B. level or levelMin/levelMax is required, one of 1, 2, 3, 4, 5, 6, 7, 7+, 8, 8+, 9, 9+, 10, 10+, 11, 11+, 12, 12+, 13, 13+, 14, 14+, 15
+	Level *string
+	// one of basic, advanced, expert, master, remaster
+	Difficulty *string
+	// one of dx, std, utage
+	Type *string
+	// internal level
+	LevelMin *float64
+	// internal level
+	LevelMax *float64
+	// only played charts with fewer DX stars than this. Charts whose max DX score is not known are left out, one of 1, 2, 3, 4, 5
+	DxStarsBelow *string
+}
+
+// GetLevelTable: Level table of a user
+//
+//	GET /users/by-user-id/{userID}/level-table
+func (c *Client) GetLevelTable(ctx context.Context, userID string, params *GetLevelTableParams) (*LevelTable, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Level != nil {
+			query.Set("level", fmt.Sprint(*params.Level))
+		}
+		if params.Difficulty != nil {
+			query.Set("difficulty", fmt.Sprint(*params.Difficulty))
+		}
+		if params.Type != nil {
+			query.Set("type", fmt.Sprint(*params.Type))
+		}
+		if params.LevelMin != nil {
+			query.Set("levelMin", fmt.Sprint(*params.LevelMin))
+		}
+		if params.LevelMax != nil {
+			query.Set("levelMax", fmt.Sprint(*params.LevelMax))
+		}
+		if params.DxStarsBelow != nil {
+			query.Set("dxStarsBelow", fmt.Sprint(*params.DxStarsBelow))
+		}
+	}
+	var out LevelTable
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/level-table", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMe: The authenticated user
+//
+//	GET /auth/me
+func (c *Client) GetMe(ctx context.Context) (*Me, error) {
+	var out Me
+	if _, err := c.do(ctx, "GET", "/auth/me", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyFeedParams are the query parameters of GetMyFeed, nil fields are not sent
+type GetMyFeedParams struct {
+	// only items of this kind, one of personal_best, rank, lamp, rating_milestone
+	Kind *string
+	// sort key, prefixed with - for descending order (default -createdAt), one of createdAt, -createdAt
+	Sort *string
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// GetMyFeed: Activity feed
+//
+//	GET /me/feed
+func (c *Client) GetMyFeed(ctx context.Context, params *GetMyFeedParams) (*FeedPage, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Kind != nil {
+			query.Set("kind", fmt.Sprint(*params.Kind))
+		}
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out FeedPage
+	if _, err := c.do(ctx, "GET", "/me/feed", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyGoal: A goal of the signed in user
+//
+//	GET /me/goals/{goalID}
+func (c *Client) GetMyGoal(ctx context.Context, goalID string) (*Goal, error) {
+	var out Goal
+	if _, err := c.do(ctx, "GET", "/me/goals/"+url.PathEscape(goalID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyPrivacy: Privacy settings of the authenticated user
+//
+//	GET /me/privacy
+func (c *Client) GetMyPrivacy(ctx context.Context) (*Privacy, error) {
+	var out Privacy
+	if _, err := c.do(ctx, "GET", "/me/privacy", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyProfile: Profile of the authenticated user
+//
+//	GET /me/profile
+func (c *Client) GetMyProfile(ctx context.Context) (*Profile, error) {
+	var out Profile
+	if _, err := c.do(ctx, "GET", "/me/profile", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPISpec: This document
+//
+//	GET /openapi.json
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]any, error) {
+	var out map[string]any
+	if _, err := c.do(ctx, "GET", "/openapi.json", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetRatingHistoryParams are the query parameters of GetRatingHistory, nil fields are not sent
+type GetRatingHistoryParams struct {
+	// keep the last snapshot of every JST day or week (weeks start on Monday), default snapshot, one of snapshot, day, week
+	Bucket *string
+	// JST date, inclusive
+	DateFrom *string
+	// JST date, inclusive
+	DateTo *string
+}
+
+// GetRatingHistory: Rating and play counts of a user over time
+//
+//	GET /users/by-user-id/{userID}/history
+func (c *Client) GetRatingHistory(ctx context.Context, userID string, params *GetRatingHistoryParams) (*RatingHistory, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Bucket != nil {
+			query.Set("bucket", fmt.Sprint(*params.Bucket))
+		}
+		if params.DateFrom != nil {
+			query.Set("dateFrom", fmt.Sprint(*params.DateFrom))
+		}
+		if params.DateTo != nil {
+			query.Set("dateTo", fmt.Sprint(*params.DateTo))
+		}
+	}
+	var out RatingHistory
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/history", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRatingLeaderboardParams are the query parameters of GetRatingLeaderboard, nil fields are not sent
+type GetRatingLeaderboardParams struct {
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// GetRatingLeaderboard: Users by their latest rating
+//
+//	GET /leaderboards/rating
+func (c *Client) GetRatingLeaderboard(ctx context.Context, params *GetRatingLeaderboardParams) (*RatingLeaderboard, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out RatingLeaderboard
+	if _, err := c.do(ctx, "GET", "/leaderboards/rating", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSession: A play session with its plays
+//
+//	GET /users/by-user-id/{userID}/sessions/{sessionID}
+func (c *Client) GetSession(ctx context.Context, userID string, sessionID string) (*SessionDetail, error) {
+	var out SessionDetail
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/sessions/"+url.PathEscape(sessionID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSong: A song with its beatmaps
+//
+//	GET /songs/by-id/{id}
+func (c *Client) GetSong(ctx context.Context, id string) (*Song, error) {
+	var out Song
+	if _, err := c.do(ctx, "GET", "/songs/by-id/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSongAliases: Aliases of a song
+//
+//	GET /songs/by-id/{id}/aliases
+func (c *Client) GetSongAliases(ctx context.Context, id string) ([]SongAlias, error) {
+	var out []SongAlias
+	if _, err := c.do(ctx, "GET", "/songs/by-id/"+url.PathEscape(id)+"/aliases", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetSongByAltKey: A song by its maimai DX NET key
+//
+//	GET /songs/by-altkey/{altkey}
+func (c *Client) GetSongByAltKey(ctx context.Context, altkey string) (*Song, error) {
+	var out Song
+	if _, err := c.do(ctx, "GET", "/songs/by-altkey/"+url.PathEscape(altkey), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSongsByTitle: Songs with exactly this title
+//
+//	GET /songs/by-title/{title}
+func (c *Client) GetSongsByTitle(ctx context.Context, title string) ([]Song, error) {
+	var out []Song
+	if _, err := c.do(ctx, "GET", "/songs/by-title/"+url.PathEscape(title), nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetUser: A user
+//
+//	GET /users/by-user-id/{userID}
+func (c *Client) GetUser(ctx context.Context, userID string) (*User, error) {
+	var out User
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserHealthCheck: Checks that the token is valid
+//
+//	GET /users/healthz
+func (c *Client) GetUserHealthCheck(ctx context.Context) (string, error) {
+	var out string
+	if _, err := c.do(ctx, "GET", "/users/healthz", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetUserProfile: Profile of a user
+//
+//	GET /users/{userID}/profile
+func (c *Client) GetUserProfile(ctx context.Context, userID string) (*Profile, error) {
+	var out Profile
+	if _, err := c.do(ctx, "GET", "/users/"+url.PathEscape(userID)+"/profile", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVersionPlates: Plate progress of a user for one version
+//
+//	GET /users/by-user-id/{userID}/plates/{version}
+func (c *Client) GetVersionPlates(ctx context.Context, userID string, version string) (*VersionPlateProgress, error) {
+	var out VersionPlateProgress
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/plates/"+url.PathEscape(version), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HealthCheck: Liveness probe
+//
+//	GET /healthz
+func (c *Client) HealthCheck(ctx context.Context) (map[string]any, error) {
+	var out map[string]any
+	if _, err := c.do(ctx, "GET", "/healthz", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListBeatmapsParams are the query parameters of ListBeatmaps, nil fields are not sent
+type ListBeatmapsParams struct {
+	Version *string
+	Genre   *string
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty *string
+	// one of dx, std, utage
+	Type *string
+	// displayed level, e.g. 13+
+	Level *string
+	// internal level
+	LevelMin *float64
+	// internal level
+	LevelMax *float64
+	// sort key, prefixed with - for descending order (default -level), one of level, -level, title, -title, releaseDate, -releaseDate
+	Sort *string
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// ListBeatmaps: Beatmaps with their songs
+//
+//	GET /beatmaps
+func (c *Client) ListBeatmaps(ctx context.Context, params *ListBeatmapsParams) ([]Beatmap, string, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Version != nil {
+			query.Set("version", fmt.Sprint(*params.Version))
+		}
+		if params.Genre != nil {
+			query.Set("genre", fmt.Sprint(*params.Genre))
+		}
+		if params.Difficulty != nil {
+			query.Set("difficulty", fmt.Sprint(*params.Difficulty))
+		}
+		if params.Type != nil {
+			query.Set("type", fmt.Sprint(*params.Type))
+		}
+		if params.Level != nil {
+			query.Set("level", fmt.Sprint(*params.Level))
+		}
+		if params.LevelMin != nil {
+			query.Set("levelMin", fmt.Sprint(*params.LevelMin))
+		}
+		if params.LevelMax != nil {
+			query.Set("levelMax", fmt.Sprint(*params.LevelMax))
+		}
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out []Beatmap
+	res, err := c.do(ctx, "GET", "/beatmaps", query, nil, &out)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, nextCursor(res), nil
+}
+
+// ListFollowers: Users following a user
+//
+//	GET /users/by-user-id/{userID}/followers
+func (c *Client) ListFollowers(ctx context.Context, userID string) ([]Follow, error) {
+	var out []Follow
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/followers", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListFollowing: Users a user follows
+//
+//	GET /users/by-user-id/{userID}/following
+func (c *Client) ListFollowing(ctx context.Context, userID string) ([]Follow, error) {
+	var out []Follow
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/following", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListMyGoalsParams are the query parameters of ListMyGoals, nil fields are not sent
+type ListMyGoalsParams struct {
+	// only completed or only open goals
+	Completed *bool
+}
+
+// ListMyGoals: Goals of the signed in user
+//
+//	GET /me/goals
+func (c *Client) ListMyGoals(ctx context.Context, params *ListMyGoalsParams) ([]Goal, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Completed != nil {
+			query.Set("completed", fmt.Sprint(*params.Completed))
+		}
+	}
+	var out []Goal
+	if _, err := c.do(ctx, "GET", "/me/goals", query, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListPlates: Plate progress of a user for every version
+//
+//	GET /users/by-user-id/{userID}/plates
+func (c *Client) ListPlates(ctx context.Context, userID string) ([]VersionPlates, error) {
+	var out []VersionPlates
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/plates", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListRecommendationsParams are the query parameters of ListRecommendations, nil fields are not sent
+type ListRecommendationsParams struct {
+	// default 20
+	Limit *int32
+}
+
+// ListRecommendations: Charts where reaching the next rank would raise the rating the most
+//
+//	GET /users/by-user-id/{userID}/recommendations
+func (c *Client) ListRecommendations(ctx context.Context, userID string, params *ListRecommendationsParams) (*Recommendations, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out Recommendations
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/recommendations", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRivals: Rivals of a user
+//
+//	GET /users/by-user-id/{userID}/rivals
+func (c *Client) ListRivals(ctx context.Context, userID string) ([]Rival, error) {
+	var out []Rival
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/rivals", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListScoresParams are the query parameters of ListScores, nil fields are not sent
+type ListScoresParams struct {
+	// one of basic, advanced, expert, master, remaster, utage
+	Difficulty *string
+	// one of dx, std, utage
+	Type    *string
+	Version *string
+	// internal level
+	LevelMin *float64
+	// internal level
+	LevelMax *float64
+	// JST date, inclusive
+	PlayedFrom *string
+	// JST date, inclusive
+	PlayedTo       *string
+	AchievementMin *float64
+	AchievementMax *float64
+	// sort key, prefixed with - for descending order (default -playedAt), one of playedAt, -playedAt, achievement, -achievement, level, -level, dxScore, -dxScore
+	Sort *string
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// ListScores: Scores of a user, subject to their privacy settings
+//
+//	GET /users/by-user-id/{userID}/scores
+func (c *Client) ListScores(ctx context.Context, userID string, params *ListScoresParams) (*ScorePage, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Difficulty != nil {
+			query.Set("difficulty", fmt.Sprint(*params.Difficulty))
+		}
+		if params.Type != nil {
+			query.Set("type", fmt.Sprint(*params.Type))
+		}
+		if params.Version != nil {
+			query.Set("version", fmt.Sprint(*params.Version))
+		}
+		if params.LevelMin != nil {
+			query.Set("levelMin", fmt.Sprint(*params.LevelMin))
+		}
+		if params.LevelMax != nil {
+			query.Set("levelMax", fmt.Sprint(*params.LevelMax))
+		}
+		if params.PlayedFrom != nil {
+			query.Set("playedFrom", fmt.Sprint(*params.PlayedFrom))
+		}
+		if params.PlayedTo != nil {
+			query.Set("playedTo", fmt.Sprint(*params.PlayedTo))
+		}
+		if params.AchievementMin != nil {
+			query.Set("achievementMin", fmt.Sprint(*params.AchievementMin))
+		}
+		if params.AchievementMax != nil {
+			query.Set("achievementMax", fmt.Sprint(*params.AchievementMax))
+		}
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out ScorePage
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/scores", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSessionsParams are the query parameters of ListSessions, nil fields are not sent
+type ListSessionsParams struct {
+	// JST date, inclusive
+	StartedFrom *string
+	// JST date, inclusive
+	StartedTo *string
+	// sort key, prefixed with - for descending order (default -startedAt), one of startedAt, -startedAt
+	Sort *string
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// ListSessions: Play sessions of a user
+//
+//	GET /users/by-user-id/{userID}/sessions
+func (c *Client) ListSessions(ctx context.Context, userID string, params *ListSessionsParams) (*SessionPage, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.StartedFrom != nil {
+			query.Set("startedFrom", fmt.Sprint(*params.StartedFrom))
+		}
+		if params.StartedTo != nil {
+			query.Set("startedTo", fmt.Sprint(*params.StartedTo))
+		}
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out SessionPage
+	if _, err := c.do(ctx, "GET", "/users/by-user-id/"+url.PathEscape(userID)+"/sessions", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSongsParams are the query parameters of ListSongs, nil fields are not sent
+type ListSongsParams struct {
+	Version *string
+	Genre   *string
+	// sort key, prefixed with - for descending order (default -releaseDate), one of releaseDate, -releaseDate, title, -title, romaji, -romaji, english, -english, artist, -artist
+	Sort *string
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// ListSongs: Songs with their beatmaps
+//
+//	GET /songs
+func (c *Client) ListSongs(ctx context.Context, params *ListSongsParams) ([]Song, string, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Version != nil {
+			query.Set("version", fmt.Sprint(*params.Version))
+		}
+		if params.Genre != nil {
+			query.Set("genre", fmt.Sprint(*params.Genre))
+		}
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out []Song
+	res, err := c.do(ctx, "GET", "/songs", query, nil, &out)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, nextCursor(res), nil
+}
+
+// ListUsersParams are the query parameters of ListUsers, nil fields are not sent
+type ListUsersParams struct {
+	// sort key, prefixed with - for descending order (default userID), one of userID, -userID, rating, -rating, lastPlayedAt, -lastPlayedAt
+	Sort *string
+	// page size, 1-500
+	Limit *int32
+	// opaque cursor from the previous page
+	Cursor *string
+}
+
+// ListUsers: Users with a public profile
+//
+//	GET /users
+func (c *Client) ListUsers(ctx context.Context, params *ListUsersParams) ([]UserSummary, string, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out []UserSummary
+	res, err := c.do(ctx, "GET", "/users", query, nil, &out)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, nextCursor(res), nil
+}
+
+// Login: Log in and receive the auth_token cookie
+//
+//	POST /auth/login
+func (c *Client) Login(ctx context.Context, body LoginRequest) (*LoginResponse, error) {
+	var out LoginResponse
+	if _, err := c.do(ctx, "POST", "/auth/login", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Logout: Clear the auth_token cookie
+//
+//	POST /auth/logout
+func (c *Client) Logout(ctx context.Context) (*Message, error) {
+	var out Message
+	if _, err := c.do(ctx, "POST", "/auth/logout", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Register: Create an account verified against maimai DX NET
+//
+//	POST /auth/register
+func (c *Client) Register(ctx context.Context, body RegisterRequest) (*RegisterResponse, error) {
+	var out RegisterResponse
+	if _, err := c.do(ctx, "POST", "/auth/register", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveMyRival: Remove a rival
+//
+//	DELETE /me/rivals/{userID}
+func (c *Client) RemoveMyRival(ctx context.Context, userID string) error {
+	_, err := c.do(ctx, "DELETE", "/me/rivals/"+url.PathEscape(userID), nil, nil, nil)
+	return err
+}
+
+// SearchSongsParams are the query parameters of SearchSongs, nil fields are not sent
+type SearchSongsParams struct {
+	Q *string
+	// default 20
+	Limit *int32
+}
+
+// SearchSongs: Find songs by title, reading, romaji, English title, artist or alias
+//
+//	GET /songs/search
+func (c *Client) SearchSongs(ctx context.Context, params *SearchSongsParams) ([]SongSearchResult, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Q != nil {
+			query.Set("q", fmt.Sprint(*params.Q))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out []SongSearchResult
+	if _, err := c.do(ctx, "GET", "/songs/search", query, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// SimulateBeatmap: Achievement, DX score and judgement budget of a simulated play
+//
+//	POST /beatmaps/{id}/simulate
+func (c *Client) SimulateBeatmap(ctx context.Context, id string, body SimulateRequest) (*Simulation, error) {
+	var out Simulation
+	if _, err := c.do(ctx, "POST", "/beatmaps/"+url.PathEscape(id)+"/simulate", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnfollowUser: Unfollow a user
+//
+//	DELETE /me/follows/{userID}
+func (c *Client) UnfollowUser(ctx context.Context, userID string) error {
+	_, err := c.do(ctx, "DELETE", "/me/follows/"+url.PathEscape(userID), nil, nil, nil)
+	return err
+}
+
+// UpdateBeatmap: Update a beatmap
+//
+//	PATCH /beatmaps
+func (c *Client) UpdateBeatmap(ctx context.Context, body UpdateBeatmapRequest) (*Beatmap, error) {
+	var out Beatmap
+	if _, err := c.do(ctx, "PATCH", "/beatmaps", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMyGoal: Update a goal
+//
+//	PATCH /me/goals/{goalID}
+func (c *Client) UpdateMyGoal(ctx context.Context, goalID string, body GoalRequest) (*Goal, error) {
+	var out Goal
+	if _, err := c.do(ctx, "PATCH", "/me/goals/"+url.PathEscape(goalID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMyPrivacy: Update the privacy settings of the authenticated user
+//
+//	PATCH /me/privacy
+func (c *Client) UpdateMyPrivacy(ctx context.Context, body PrivacyUpdate) (*Privacy, error) {
+	var out Privacy
+	if _, err := c.do(ctx, "PATCH", "/me/privacy", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMyProfile: Update the profile of the authenticated user
+//
+//	PATCH /me/profile
+func (c *Client) UpdateMyProfile(ctx context.Context, body ProfileUpdate) (*Profile, error) {
+	var out Profile
+	if _, err := c.do(ctx, "PATCH", "/me/profile", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSong: Update a song
+//
+//	PATCH /songs
+func (c *Client) UpdateSong(ctx context.Context, body UpdateSongRequest) (*Song, error) {
+	var out Song
+	if _, err := c.do(ctx, "PATCH", "/songs", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSongAlias: Rename an alias (curators only)
+//
+//	PATCH /aliases/{aliasID}
+func (c *Client) UpdateSongAlias(ctx context.Context, aliasID string, body AliasRequest) (*SongAlias, error) {
+	var out SongAlias
+	if _, err := c.do(ctx, "PATCH", "/aliases/"+url.PathEscape(aliasID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSongTitleEnglish: Set or clear the English title of a song (curators only)
+//
+//	PATCH /songs/by-id/{id}/title-english
+func (c *Client) UpdateSongTitleEnglish(ctx context.Context, id string, body TitleEnglishRequest) (*Song, error) {
+	var out Song
+	if _, err := c.do(ctx, "PATCH", "/songs/by-id/"+url.PathEscape(id)+"/title-english", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateUser: Re-scrape a user from maimai DX NET
+//
+//	POST /users/by-user-id/{userID}/update
+func (c *Client) UpdateUser(ctx context.Context, userID string) (string, error) {
+	var out string
+	if _, err := c.do(ctx, "POST", "/users/by-user-id/"+url.PathEscape(userID)+"/update", nil, nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}